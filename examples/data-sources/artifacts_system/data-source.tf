@@ -0,0 +1,5 @@
+data "artifacts_system" "current" {}
+
+output "artifactory_version" {
+  value = data.artifacts_system.current.version
+}
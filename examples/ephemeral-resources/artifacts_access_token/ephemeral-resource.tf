@@ -0,0 +1,13 @@
+ephemeral "artifacts_access_token" "deploy" {
+  // a token limited to the deployers group, revoked as soon as the run is done with it
+  scope      = "applied-permissions/groups:deployers"
+  expires_in = 900
+}
+
+provider "helm" {
+  registries = [{
+    url      = "oci://example.com/helm-local"
+    username = "deployer"
+    password = ephemeral.artifacts_access_token.deploy.token
+  }]
+}
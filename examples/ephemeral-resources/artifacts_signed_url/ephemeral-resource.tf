@@ -0,0 +1,12 @@
+ephemeral "artifacts_signed_url" "installer" {
+  // a signed URL, or on editions without them a token limited to the readers group, revoked after the run
+  path        = artifacts_upload.installer.full_path
+  expires_in  = 900
+  token_scope = "applied-permissions/groups:readers"
+}
+
+locals {
+  installer_headers = ephemeral.artifacts_signed_url.installer.token == "" ? {} : {
+    Authorization = "Bearer ${ephemeral.artifacts_signed_url.installer.token}"
+  }
+}
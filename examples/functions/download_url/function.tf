@@ -0,0 +1,11 @@
+variable "artifactory_url" {
+  default = "https://example.com/artifactory"
+}
+
+provider "artifacts" {
+  url = var.artifactory_url
+}
+
+output "installer_url" {
+  value = provider::artifacts::download_url(var.artifactory_url, artifacts_upload.installer.full_path)
+}
@@ -0,0 +1,9 @@
+resource "artifacts_upload" "installer" {
+  upload_path = "sas-generic/installer/installer.sh"
+  upload_file = "build/installer.sh"
+}
+
+// unlike artifacts_upload.installer.sha256, the checksum is known at plan time
+output "installer_sha256" {
+  value = provider::artifacts::file_sha256("build/installer.sh")
+}
@@ -0,0 +1,7 @@
+resource "artifacts_upload" "sources" {
+  upload_path = provider::artifacts::path_join(
+    "sas-maven",
+    provider::artifacts::maven_path("com.example", "app", "1.2.0", "sources", "jar"),
+  )
+  upload_file = "target/app-1.2.0-sources.jar"
+}
@@ -0,0 +1,8 @@
+locals {
+  release_dir = provider::artifacts::path_join("sas-generic", var.product, var.version)
+}
+
+resource "artifacts_upload" "installer" {
+  upload_path = provider::artifacts::path_join(local.release_dir, "installer.sh")
+  upload_file = "build/installer.sh"
+}
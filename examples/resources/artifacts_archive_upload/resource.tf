@@ -0,0 +1,15 @@
+resource "artifacts_archive_upload" "site" {
+  // the format, tar.gz, is implied by the extension
+  upload_path = "upload/site-1.0.0.tar.gz"
+  source_dir  = "./site"
+  excludes    = [".git", "*.tmp"]
+}
+
+resource "artifacts_archive_upload" "tool" {
+  upload_path = "upload/tool-{version}"
+  path_vars = {
+    version = "1.0.0"
+  }
+  source_dir = "./build/tool"
+  format     = "tar.zst"
+}
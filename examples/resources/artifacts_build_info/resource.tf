@@ -0,0 +1,29 @@
+resource "artifacts_upload" "tool" {
+  upload_path = "my-repo/tool/1.0.0/tool.tar.gz"
+  upload_file = "./build/tool.tar.gz"
+}
+
+resource "artifacts_build_info" "tool" {
+  name         = "tool"
+  number       = var.ci_build_number
+  vcs_revision = var.git_commit
+  vcs_url      = "https://github.com/my-org/tool"
+
+  module {
+    id = "my-org:tool:1.0.0"
+
+    artifact {
+      full_path = artifacts_upload.tool.full_path
+      sha1      = artifacts_upload.tool.sha1
+      sha256    = artifacts_upload.tool.sha256
+      md5       = artifacts_upload.tool.md5
+    }
+  }
+
+  // capture the CI job's variables, except those matching CI_DEPLOY_*
+  env_include = ["CI_*"]
+  env_exclude = ["CI_DEPLOY_*"]
+
+  // stamp build.name and build.number onto tool.tar.gz
+  set_properties = true
+}
@@ -0,0 +1,9 @@
+resource "artifacts_build_promotion" "tool" {
+  // moves the build's artifacts from my-staging-repo to my-release-repo
+  name              = artifacts_build_info.tool.name
+  number            = artifacts_build_info.tool.number
+  source_repository = "my-staging-repo"
+  target_repository = "my-release-repo"
+  status            = "released"
+  comment           = "Passed integration tests"
+}
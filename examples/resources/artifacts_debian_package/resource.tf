@@ -0,0 +1,15 @@
+resource "artifacts_debian_package" "tool" {
+  // uploads to my-debian-repo/pool/main/t/tool/tool_1.0.0_amd64.deb, named from the package's control file
+  repository    = "my-debian-repo"
+  upload_file   = "./build/tool_1.0.0_amd64.deb"
+  distributions = ["focal", "jammy"]
+}
+
+resource "artifacts_debian_package" "tool_docs" {
+  repository    = "my-debian-repo"
+  upload_file   = "./build/tool-doc_1.0.0_all.deb"
+  distributions = ["focal"]
+  component     = "contrib"
+  // index an architecture-independent package for a specific architecture
+  architecture = "amd64"
+}
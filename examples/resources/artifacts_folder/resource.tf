@@ -0,0 +1,9 @@
+resource "artifacts_folder" "team_drop_zone" {
+  path = "my-repo/teams/my-team/drop"
+}
+
+resource "artifacts_folder" "decommissioned_team" {
+  path = "my-repo/teams/old-team"
+  // delete the folder, and everything uploaded to it, when this resource is destroyed
+  force_destroy = true
+}
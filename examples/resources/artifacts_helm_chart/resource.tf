@@ -0,0 +1,14 @@
+resource "artifacts_helm_chart" "tool" {
+  // packages the chart and uploads it to my-helm-repo/tool-1.0.0.tgz, named from its Chart.yaml
+  repository = "my-helm-repo"
+  chart      = "./charts/tool"
+}
+
+resource "artifacts_helm_chart" "tool_packaged" {
+  // uploads a chart packaged by helm package
+  repository = "my-helm-repo"
+  directory  = "prerelease"
+  chart      = "./build/tool-1.1.0-rc.1.tgz"
+  // make the chart installable as soon as it's applied
+  reindex = true
+}
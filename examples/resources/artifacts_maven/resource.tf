@@ -0,0 +1,19 @@
+resource "artifacts_maven" "tool" {
+  // uploads my-maven-repo/com/example/tools/tool/1.0.0/tool-1.0.0.jar, and tool-1.0.0.pom next to it
+  repository   = "my-maven-repo"
+  group_id     = "com.example.tools"
+  artifact_id  = "tool"
+  version      = "1.0.0"
+  upload_file  = "./build/tool.jar"
+  generate_pom = true
+}
+
+resource "artifacts_maven" "tool_linux" {
+  repository  = "my-maven-repo"
+  group_id    = "com.example.tools"
+  artifact_id = "tool"
+  // each change to the file uploads a new timestamped build, such as tool-1.1.0-20211201.123456-3-linux-amd64.tar.gz
+  version     = "1.1.0-SNAPSHOT"
+  classifier  = "linux-amd64"
+  upload_file = "./build/tool-linux-amd64.tar.gz"
+}
@@ -0,0 +1,26 @@
+resource "artifacts_upload" "release" {
+  upload_path     = "upload/1.0.0/artifact.txt"
+  upload_file     = "./artifact.txt"
+  delete_old_path = false
+}
+
+resource "artifacts_pointer" "latest" {
+  // a copy of the release, updated whenever the release is
+  path        = "upload/latest/artifact.txt"
+  target_path = artifacts_upload.release.upload_path
+}
+
+resource "artifacts_pointer" "latest_version" {
+  // a text file containing only the released version
+  path        = "upload/latest.txt"
+  target_path = artifacts_upload.release.upload_path
+  mode        = "file"
+  content     = "1.0.0"
+}
+
+resource "artifacts_pointer" "current" {
+  // an empty file whose pointer.target and pointer.url properties name the release
+  path        = "upload/current"
+  target_path = artifacts_upload.release.upload_path
+  mode        = "property"
+}
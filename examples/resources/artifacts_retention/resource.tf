@@ -0,0 +1,25 @@
+resource "artifacts_upload" "release" {
+  upload_path     = "upload/${var.version}/artifact.txt"
+  upload_file     = "./artifact.txt"
+  delete_old_path = false
+}
+
+resource "artifacts_retention" "releases" {
+  // versions are the first folder beneath upload, such as upload/1.0.0
+  path          = "upload"
+  version_regex = "^([^/]+)/"
+  // keep the five newest versions, deleting the rest on each apply
+  keep_latest = 5
+  // but never keep a version for more than a year
+  max_age = "365d"
+
+  depends_on = [artifacts_upload.release]
+}
+
+resource "artifacts_retention" "nightlies" {
+  path          = "nightly"
+  version_regex = "^nightly-(?P<version>\\d{8})/"
+  max_age       = "14d"
+  // report candidates for deletion without deleting anything
+  dry_run = true
+}
@@ -0,0 +1,8 @@
+resource "artifacts_rpm_package" "tool" {
+  // uploads to my-rpm-repo/el9/x86_64/tool-1.0.0-1.el9.x86_64.rpm, named from the package's header
+  repository  = "my-rpm-repo"
+  directory   = "el9/x86_64"
+  upload_file = "./build/tool-1.0.0-1.el9.x86_64.rpm"
+  // make the package installable as soon as it's applied
+  calculate_metadata = true
+}
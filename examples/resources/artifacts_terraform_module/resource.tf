@@ -0,0 +1,10 @@
+resource "artifacts_terraform_module" "network" {
+  // publishes to my-terraform-repo/my-team/network/aws/1.2.0.zip
+  repository = "my-terraform-repo"
+  namespace  = "my-team"
+  name       = "network"
+  system     = "aws"
+  version    = "1.2.0"
+  source_dir = "./modules/network"
+  excludes   = ["examples", "test"]
+}
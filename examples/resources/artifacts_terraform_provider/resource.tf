@@ -0,0 +1,49 @@
+locals {
+  platforms = {
+    linux_amd64  = { os = "linux", arch = "amd64" }
+    darwin_arm64 = { os = "darwin", arch = "arm64" }
+  }
+}
+
+resource "artifacts_terraform_provider" "tool" {
+  // publishes to my-terraform-repo/my-team/tool/terraform-provider-tool_1.2.0_<os>_<arch>.zip, along with
+  // terraform-provider-tool_1.2.0_SHA256SUMS and terraform-provider-tool_1.2.0_SHA256SUMS.sig
+  repository = "my-terraform-repo"
+  namespace  = "my-team"
+  name       = "tool"
+  version    = "1.2.0"
+
+  dynamic "platform" {
+    for_each = local.platforms
+    content {
+      os          = platform.value.os
+      arch        = platform.value.arch
+      upload_file = "./dist/terraform-provider-tool_1.2.0_${platform.key}.zip"
+    }
+  }
+
+  signing_key            = file("./signing-key.asc")
+  signing_key_passphrase = var.signing_key_passphrase
+}
+
+ephemeral "vault_kv_secret_v2" "signing" {
+  mount = "secret"
+  name  = "signing"
+}
+
+resource "artifacts_terraform_provider" "tool_write_only_key" {
+  repository = "my-terraform-repo"
+  namespace  = "my-team"
+  name       = "tool"
+  version    = "1.3.0"
+
+  platform {
+    os          = "linux"
+    arch        = "amd64"
+    upload_file = "./dist/terraform-provider-tool_1.3.0_linux_amd64.zip"
+  }
+
+  // the key is never stored in state; bump the version to sign again with a new key
+  signing_key_wo         = ephemeral.vault_kv_secret_v2.signing.data["key"]
+  signing_key_wo_version = 1
+}
@@ -11,3 +11,43 @@ resource "artifacts_upload" "retain_versions" {
   // previous versions even when a newer version is uploaded.
   delete_old_path = false
 }
+
+resource "artifacts_upload" "templated" {
+  // with the provider's base_path set to "my-repo/my-team", this uploads to my-repo/my-team/1.0.0/tool-linux-amd64.txt
+  upload_path = "{version}/tool-{os}-{arch}.txt"
+  upload_file = "./artifact.txt"
+  path_vars = {
+    version = "1.0.0"
+    os      = "linux"
+    arch    = "amd64"
+  }
+}
+
+resource "artifacts_upload" "verifiable" {
+  upload_path = "upload/artifact.txt"
+  upload_file = "./artifact.txt"
+  // also publishes upload/artifact.txt.sha256 and upload/artifact.txt.asc
+  checksum_sidecars      = ["sha256"]
+  signing_key            = file("./signing-key.asc")
+  signing_key_passphrase = var.signing_key_passphrase
+}
+
+ephemeral "vault_kv_secret_v2" "signing" {
+  mount = "secret"
+  name  = "signing"
+}
+
+resource "artifacts_upload" "signed" {
+  upload_path = "upload/release.tgz"
+  upload_file = "./release.tgz"
+  // the key is never stored in state; bump the version to sign again with a new key
+  signing_key_wo         = ephemeral.vault_kv_secret_v2.signing.data["key"]
+  signing_key_wo_version = 1
+}
+
+resource "artifacts_upload" "package" {
+  // uploads to my-maven-repo/com/example/tool/1.0.0/tool-1.0.0.jar, from the coordinates in the jar's pom.properties
+  upload_file  = "./build/tool-1.0.0.jar"
+  package_type = "auto"
+  repository   = "my-maven-repo"
+}
@@ -0,0 +1,170 @@
+// Copyright 2021 Splunk, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	ephemeralschema "github.com/hashicorp/terraform-plugin-framework/ephemeral/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"terraform-provider-artifacts/internal/provider/internal/client"
+)
+
+// accessTokenEphemeralResource is artifacts_access_token, which creates an access token for the duration of a
+// Terraform run, without it being stored in plan or state.
+type accessTokenEphemeralResource struct {
+	client *client.Client
+}
+
+// accessTokenModel is the data of artifacts_access_token.
+type accessTokenModel struct {
+	Scope       types.String `tfsdk:"scope"`
+	ExpiresIn   types.Int64  `tfsdk:"expires_in"`
+	Description types.String `tfsdk:"description"`
+	Token       types.String `tfsdk:"token"`
+	ExpiresAt   types.String `tfsdk:"expires_at"`
+}
+
+func newAccessTokenEphemeralResource() ephemeral.EphemeralResource {
+	return &accessTokenEphemeralResource{}
+}
+
+// Metadata implements ephemeral.EphemeralResource.
+func (r *accessTokenEphemeralResource) Metadata(ctx context.Context, req ephemeral.MetadataRequest, resp *ephemeral.MetadataResponse) {
+	resp.TypeName = accessTokenEphemeralResourceKey
+}
+
+// Schema implements ephemeral.EphemeralResource.
+func (r *accessTokenEphemeralResource) Schema(ctx context.Context, req ephemeral.SchemaRequest, resp *ephemeral.SchemaResponse) {
+	resp.Schema = ephemeralschema.Schema{
+		Description: "Short-lived access token for the duration of a Terraform run, which is revoked when Terraform is done with it",
+		Attributes: map[string]ephemeralschema.Attribute{
+			scopeKey: ephemeralschema.StringAttribute{
+				Description: fmt.Sprintf("Scope of the token, such as `applied-permissions/groups:readers` to limit the token to the permissions of a group. Defaults to `%s`, the permissions of the provider's user.", defaultTokenScope),
+				Optional:    true,
+			},
+			expiresInKey: ephemeralschema.Int64Attribute{
+				Description: fmt.Sprintf("Number of seconds the token is valid for, if it isn't revoked first. Defaults to %d.", defaultSignedURLExpiresIn),
+				Optional:    true,
+				Validators:  []validator.Int64{int64validator.AtLeast(1)},
+			},
+			descriptionKey: ephemeralschema.StringAttribute{
+				Description: fmt.Sprintf("Description of the token, shown in Artifactory's list of tokens. Defaults to `%s`.", defaultAccessTokenDescription),
+				Optional:    true,
+			},
+			tokenKey: ephemeralschema.StringAttribute{
+				Description: "Access token, to send as a bearer token",
+				Computed:    true,
+				Sensitive:   true,
+			},
+			expiresAtKey: ephemeralschema.StringAttribute{
+				Description: "Time the token expires, in RFC 3339 format",
+				Computed:    true,
+			},
+		},
+	}
+}
+
+// Configure implements ephemeral.EphemeralResourceWithConfigure.
+func (r *accessTokenEphemeralResource) Configure(ctx context.Context, req ephemeral.ConfigureRequest, resp *ephemeral.ConfigureResponse) {
+	// the provider isn't configured yet when configuration is validated
+	if req.ProviderData == nil {
+		return
+	}
+
+	c, ok := req.ProviderData.(*client.Client)
+	if !ok {
+		resp.Diagnostics.AddError("Unexpected provider data", fmt.Sprintf("Expected *client.Client, got %T.", req.ProviderData))
+		return
+	}
+
+	r.client = c
+}
+
+// Open implements ephemeral.EphemeralResource.
+func (r *accessTokenEphemeralResource) Open(ctx context.Context, req ephemeral.OpenRequest, resp *ephemeral.OpenResponse) {
+	data := accessTokenModel{}
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	validFor := time.Duration(int64OrDefault(data.ExpiresIn, defaultSignedURLExpiresIn)) * time.Second
+	token, err := r.client.CreateAccessToken(ctx,
+		stringOrDefault(data.Scope, defaultTokenScope),
+		validFor,
+		stringOrDefault(data.Description, defaultAccessTokenDescription),
+	)
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to create access token", err.Error())
+		return
+	}
+
+	data.Token = types.StringValue(token.Token)
+	data.ExpiresAt = types.StringValue("")
+	if !token.ExpiresAt.IsZero() {
+		data.ExpiresAt = types.StringValue(token.ExpiresAt.UTC().Format(time.RFC3339))
+	}
+	resp.Diagnostics.Append(resp.Result.Set(ctx, &data)...)
+
+	setAccessTokenID(ctx, resp, token)
+}
+
+// Close implements ephemeral.EphemeralResourceWithClose.
+func (r *accessTokenEphemeralResource) Close(ctx context.Context, req ephemeral.CloseRequest, resp *ephemeral.CloseResponse) {
+	revokeAccessToken(ctx, r.client, req, resp)
+}
+
+// setAccessTokenID stores the ID of a token created by Open, which isn't a secret, as it's all that
+// revokeAccessToken needs to revoke the token.
+func setAccessTokenID(ctx context.Context, resp *ephemeral.OpenResponse, token client.AccessToken) {
+	if token.ID == "" {
+		return
+	}
+
+	tokenID, err := json.Marshal(token.ID)
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to store access token ID", err.Error())
+		return
+	}
+	resp.Diagnostics.Append(resp.Private.SetKey(ctx, tokenIDPrivateKey, tokenID)...)
+}
+
+// revokeAccessToken revokes the token whose ID was stored by setAccessTokenID, if any. Tokens that can't be revoked
+// are left to expire, with a warning.
+func revokeAccessToken(ctx context.Context, c *client.Client, req ephemeral.CloseRequest, resp *ephemeral.CloseResponse) {
+	tokenIDJSON, diags := req.Private.GetKey(ctx, tokenIDPrivateKey)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() || tokenIDJSON == nil {
+		return
+	}
+
+	var tokenID string
+	if err := json.Unmarshal(tokenIDJSON, &tokenID); err != nil {
+		resp.Diagnostics.AddError("Unable to read access token ID", err.Error())
+		return
+	}
+
+	if err := c.RevokeAccessToken(ctx, tokenID); err != nil {
+		resp.Diagnostics.AddWarning("Unable to revoke access token", fmt.Sprintf("The token will remain valid until it expires: %s", err))
+	}
+}
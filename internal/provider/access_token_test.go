@@ -0,0 +1,99 @@
+// Copyright 2021 Splunk, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestAccessTokenEphemeralResource(t *testing.T) {
+	revoked := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/access/api/v1/tokens":
+			request := map[string]interface{}{}
+			if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+				t.Errorf("unable to decode request: %s", err)
+			}
+			if request["scope"] != "applied-permissions/groups:deployers" || request["expires_in"] != float64(600) || request["description"] != defaultAccessTokenDescription {
+				t.Errorf("unexpected request %v", request)
+			}
+			w.Write([]byte(`{"token_id":"token-1","access_token":"run-token","scope":"applied-permissions/groups:deployers","expires_in":600}`))
+		case r.Method == http.MethodDelete && r.URL.Path == "/access/api/v1/tokens/token-1":
+			revoked = true
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+		}
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	providerServer, schemas := configuredProviderServer(t, server.URL+"/artifactory")
+
+	tokenType := schemas.EphemeralResourceSchemas[accessTokenEphemeralResourceKey].ValueType().(tftypes.Object)
+	tokenConfig, err := tfprotov5.NewDynamicValue(tokenType, objectValue(tokenType, map[string]tftypes.Value{
+		scopeKey:     tftypes.NewValue(tftypes.String, "applied-permissions/groups:deployers"),
+		expiresInKey: tftypes.NewValue(tftypes.Number, 600),
+	}))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	openResp, err := providerServer.OpenEphemeralResource(ctx, &tfprotov5.OpenEphemeralResourceRequest{
+		TypeName: accessTokenEphemeralResourceKey,
+		Config:   &tokenConfig,
+	})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	checkDiagnostics(t, openResp.Diagnostics)
+
+	result, err := openResp.Result.Unmarshal(tokenType)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	attributes := map[string]tftypes.Value{}
+	if err := result.As(&attributes); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	var token, expiresAt string
+	if err := attributes[tokenKey].As(&token); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if err := attributes[expiresAtKey].As(&expiresAt); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if token != "run-token" || expiresAt == "" {
+		t.Errorf("got token %q expiring at %q", token, expiresAt)
+	}
+
+	closeResp, err := providerServer.CloseEphemeralResource(ctx, &tfprotov5.CloseEphemeralResourceRequest{
+		TypeName: accessTokenEphemeralResourceKey,
+		Private:  openResp.Private,
+	})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	checkDiagnostics(t, closeResp.Diagnostics)
+	if !revoked {
+		t.Error("token wasn't revoked")
+	}
+}
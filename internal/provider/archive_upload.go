@@ -0,0 +1,358 @@
+// Copyright 2021 Splunk, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+
+	"terraform-provider-artifacts/internal/provider/internal/archive"
+	"terraform-provider-artifacts/internal/provider/internal/client"
+)
+
+func resourceArchiveUpload() *schema.Resource {
+	formats := make([]string, 0, len(archive.Formats))
+	for _, format := range archive.Formats {
+		formats = append(formats, string(format))
+	}
+
+	archiveSchema := map[string]*schema.Schema{
+		sourceDirKey: {
+			Description: "Directory whose contents are archived",
+			Type:        schema.TypeString,
+			Required:    true,
+		},
+		archiveFormatKey: {
+			Description: fmt.Sprintf("Format of the archive, one of `%s`, `%s`, or `%s`. Defaults to the format implied by the extension of `%s`.",
+				archive.Zip, archive.TarGzip, archive.TarZstd, uploadPathKey),
+			Type:         schema.TypeString,
+			Optional:     true,
+			ValidateFunc: validation.StringInSlice(formats, false),
+		},
+		excludesKey: {
+			Description: fmt.Sprintf("Patterns of paths, relative to `%s`, to leave out of the archive, such as `.git` or `*/*.tmp`. Excluded directories are left out along with their contents.", sourceDirKey),
+			Type:        schema.TypeList,
+			Optional:    true,
+			Elem:        &schema.Schema{Type: schema.TypeString},
+		},
+		sourceHashKey: {
+			Description: "SHA256 of the archive's entries and their contents, which is computed when planning without building the archive. It changes, causing the archive to be uploaded again, only when the archive's contents change.",
+			Type:        schema.TypeString,
+			Computed:    true,
+		},
+		archiveSHA256Key: {
+			Description: "SHA256 of the archive as it was built and uploaded. The archive is uploaded again if the uploaded file's `sha256` no longer matches it.",
+			Type:        schema.TypeString,
+			Computed:    true,
+		},
+	}
+
+	// everything about where and how the archive is uploaded is the same as for artifacts_upload, except that archives
+	// aren't packages with a canonical path, so always need an upload_path
+	for key, value := range uploadSDKSchema() {
+		archiveSchema[key] = value
+	}
+	archiveSchema[uploadPathKey] = &schema.Schema{
+		Description:  fmt.Sprintf("Path to upload to, relative to the provider's URL and `%s`. May contain `{name}` tokens, which are replaced with values from `%s`. The full path must start with a repository key, and must not have leading or trailing slashes, empty, `.`, or `..` segments, or whitespace around segments.", basePathKey, pathVarsKey),
+		Type:         schema.TypeString,
+		Required:     true,
+		ValidateFunc: validateUploadPathFunc,
+	}
+
+	return &schema.Resource{
+		Description: "Upload a deterministic archive of a directory to Artifactory. Entries are sorted, and have fixed " +
+			"modification times and normalized permissions, so identical directory contents always give identical checksums " +
+			"with a given build of the provider. The compressors may change between provider versions, so the checksums of " +
+			"an archive built by another version can differ, though an archive is only uploaded again when its contents change.",
+		CreateContext: resourceArchiveUploadCreate,
+		ReadContext:   resourceArchiveUploadRead,
+		UpdateContext: resourceArchiveUploadUpdate,
+		DeleteContext: resourceArchiveUploadDelete,
+		CustomizeDiff: resourceArchiveUploadDiff,
+		Schema:        archiveSchema,
+	}
+}
+
+func resourceArchiveUploadCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*client.Client)
+
+	d.SetId(artifactIDValue)
+
+	uploadPath, err := resolveUploadPath(d, client)
+	if err != nil {
+		return diag.Errorf("unable to resolve %s: %s", uploadPathKey, err)
+	}
+	if err := d.Set(fullPathKey, uploadPath); err != nil {
+		return diag.FromErr(err)
+	}
+
+	options, err := archiveOptions(d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	sourceDir := d.Get(sourceDirKey).(string)
+	sourceHash, err := archive.Hash(sourceDir, options)
+	if err != nil {
+		return diag.Errorf("unable to hash contents of %s: %s", sourceDir, err)
+	}
+	if err := d.Set(sourceHashKey, sourceHash); err != nil {
+		return diag.FromErr(err)
+	}
+
+	file, err := os.CreateTemp("", "terraform-provider-artifacts-*."+string(options.Format))
+	if err != nil {
+		return diag.Errorf("unable to create temporary archive file: %s", err)
+	}
+	defer os.Remove(file.Name())
+
+	hash := sha256.New()
+	err = archive.Write(io.MultiWriter(file, hash), sourceDir, options)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return diag.Errorf("unable to archive %s: %s", sourceDir, err)
+	}
+
+	if err := d.Set(archiveSHA256Key, fmt.Sprintf("%x", hash.Sum(nil))); err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err := client.Upload(ctx, uploadPath, file.Name(), nil); err != nil {
+		return diag.Errorf("failure uploading archive of %s: %s", sourceDir, err)
+	}
+
+	if err := publishSidecars(ctx, d, client, uploadPath, file.Name()); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set(sidecarPathsKey, sidecarPaths(d, uploadPath)); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return resourceArchiveUploadRead(ctx, d, meta)
+}
+
+func resourceArchiveUploadRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	return diag.FromErr(readUpload(ctx, d, meta.(*client.Client)))
+}
+
+func resourceArchiveUploadUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*client.Client)
+
+	if err := deleteOldUpload(ctx, d, client); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := deleteOldSidecars(ctx, d, client); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return resourceArchiveUploadCreate(ctx, d, meta)
+}
+
+func resourceArchiveUploadDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	return diag.FromErr(deleteUpload(ctx, d, meta.(*client.Client)))
+}
+
+func resourceArchiveUploadDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if d.HasChange(triggersKey) {
+		if err := setNewComputedArchiveChecksums(d); err != nil {
+			return err
+		}
+	}
+
+	c, ok := meta.(*client.Client)
+	if !ok {
+		return nil
+	}
+
+	changed, err := diffFullPath(d, c)
+	if err != nil {
+		return err
+	}
+	if d.NewValueKnown(fullPathKey) {
+		if err := validateUploadFullPath(d.Get(fullPathKey).(string)); err != nil {
+			return fmt.Errorf("invalid %s: %s", uploadPathKey, err)
+		}
+	}
+	if err := diffSidecars(d); err != nil {
+		return err
+	}
+	if changed {
+		if err := setNewComputedArchiveChecksums(d); err != nil {
+			return err
+		}
+	}
+
+	if !d.NewValueKnown(sourceDirKey) || !d.NewValueKnown(archiveFormatKey) || !d.NewValueKnown(excludesKey) || !d.NewValueKnown(uploadPathKey) {
+		return setNewComputedArchive(d)
+	}
+
+	// directories that don't exist yet at plan time, such as those populated by other resources, are never
+	// considered drifted
+	sourceDir := d.Get(sourceDirKey).(string)
+	if _, err := os.Stat(sourceDir); err != nil {
+		return nil
+	}
+
+	options, err := archiveOptions(d)
+	if err != nil {
+		return err
+	}
+
+	// the contents are hashed without building and compressing the archive, which is only done when uploading it
+	sourceHash, err := archive.Hash(sourceDir, options)
+	if err != nil {
+		return fmt.Errorf("unable to hash contents of %s: %s", sourceDir, err)
+	}
+
+	if sourceHash != d.Get(sourceHashKey).(string) {
+		if err := d.SetNew(sourceHashKey, sourceHash); err != nil {
+			return err
+		}
+		return setNewComputedArchiveChecksums(d)
+	}
+
+	// the uploaded file's checksum differing from the archive's means it was changed remotely
+	if d.Id() != "" && d.Get(sha256Key).(string) != d.Get(archiveSHA256Key).(string) {
+		return setNewComputedArchiveChecksums(d)
+	}
+
+	return nil
+}
+
+// archiveOptions returns the archive.Options for d, with the format implied by upload_path if it isn't set.
+func archiveOptions(d resourceGetter) (archive.Options, error) {
+	options := archive.Options{
+		Format:   archive.Format(d.Get(archiveFormatKey).(string)),
+		Excludes: stringList(d.Get(excludesKey)),
+	}
+
+	if options.Format == "" {
+		format, ok := archive.FormatForPath(d.Get(uploadPathKey).(string))
+		if !ok {
+			return options, fmt.Errorf("unable to determine the archive format from the extension of %s, so %s must be set", uploadPathKey, archiveFormatKey)
+		}
+		options.Format = format
+	}
+
+	return options, nil
+}
+
+// uploadSDKSchema returns the SDKv2 schema of the attributes archives share with artifacts_upload, which is
+// implemented with the plugin framework. They're described by uploadDescriptions, and validated with the same
+// functions.
+func uploadSDKSchema() map[string]*schema.Schema {
+	uploadSchema := map[string]*schema.Schema{
+		pathVarsKey: {
+			Description: uploadDescriptions[pathVarsKey],
+			Type:        schema.TypeMap,
+			Optional:    true,
+			Elem:        &schema.Schema{Type: schema.TypeString},
+		},
+		fullPathKey: {
+			Description: fmt.Sprintf("Path the file is uploaded to, relative to the provider's URL, after applying the provider's `%s` and expanding `{name}` tokens.", basePathKey),
+			Type:        schema.TypeString,
+			Computed:    true,
+		},
+		deleteOldPath: {
+			Description: uploadDescriptions[deleteOldPath],
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Default:     true,
+		},
+		sha1Key: {
+			Description: uploadDescriptions[sha1Key],
+			Type:        schema.TypeString,
+			Computed:    true,
+		},
+		sha256Key: {
+			Description: uploadDescriptions[sha256Key],
+			Type:        schema.TypeString,
+			Computed:    true,
+		},
+		md5Key: {
+			Description: uploadDescriptions[md5Key],
+			Type:        schema.TypeString,
+			Computed:    true,
+		},
+		triggersKey: {
+			Description: uploadDescriptions[triggersKey],
+			Type:        schema.TypeMap,
+			Optional:    true,
+			Elem:        &schema.Schema{Type: schema.TypeString},
+		},
+		checksumSidecarsKey: {
+			Description: uploadDescriptions[checksumSidecarsKey],
+			Type:        schema.TypeList,
+			Optional:    true,
+			Elem: &schema.Schema{
+				Type:         schema.TypeString,
+				ValidateFunc: validation.StringInSlice(checksumTypeStrings(), false),
+			},
+		},
+		signingKeyKey: {
+			Description:  uploadDescriptions[signingKeyKey],
+			Type:         schema.TypeString,
+			Optional:     true,
+			Sensitive:    true,
+			ValidateFunc: validateSigningKey,
+		},
+		signingKeyPassphraseKey: {
+			Description: uploadDescriptions[signingKeyPassphraseKey],
+			Type:        schema.TypeString,
+			Optional:    true,
+			Sensitive:   true,
+		},
+		sidecarPathsKey: {
+			Description: uploadDescriptions[sidecarPathsKey],
+			Type:        schema.TypeList,
+			Computed:    true,
+			Elem:        &schema.Schema{Type: schema.TypeString},
+		},
+	}
+
+	for key, value := range signingKeyWOSDKSchema(false) {
+		uploadSchema[key] = value
+	}
+
+	return uploadSchema
+}
+
+// setNewComputedArchive marks the source hash, and the checksums of the archive and the uploaded file, as "known
+// after apply".
+func setNewComputedArchive(d *schema.ResourceDiff) error {
+	if err := d.SetNewComputed(sourceHashKey); err != nil {
+		return err
+	}
+
+	return setNewComputedArchiveChecksums(d)
+}
+
+// setNewComputedArchiveChecksums marks the checksums of the archive and the uploaded file as "known after apply".
+func setNewComputedArchiveChecksums(d *schema.ResourceDiff) error {
+	if err := d.SetNewComputed(archiveSHA256Key); err != nil {
+		return err
+	}
+
+	return setNewComputedChecksums(d)
+}
@@ -0,0 +1,120 @@
+// Copyright 2021 Splunk, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccResourceArchiveUpload(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV5ProviderFactories: protoV5ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testResourceArchiveUploadConfig,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("artifacts_archive_upload.test", "full_path", "sas-binary/terraform-provider-artifacts-test/archive.tar.gz"),
+					resource.TestCheckResourceAttrPair("artifacts_archive_upload.test", "sha256", "artifacts_archive_upload.test", "archive_sha256"),
+				),
+			},
+			{
+				// rebuilding the archive from the same directory gives the same checksums, so there's nothing to do
+				Config:   testResourceArchiveUploadConfig,
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
+// TestArchiveUploadSharesUploadSchema checks that the attributes artifacts_archive_upload shares with artifacts_upload,
+// whose schemas are implemented with SDKv2 and the plugin framework respectively, are the same.
+func TestArchiveUploadSharesUploadSchema(t *testing.T) {
+	providerServer, err := ProtoV5ProviderServer("dev")()
+	if err != nil {
+		t.Fatal(err)
+	}
+	schemas, err := providerServer.GetProviderSchema(context.Background(), &tfprotov5.GetProviderSchemaRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	attributes := func(resourceKey string) map[string]*tfprotov5.SchemaAttribute {
+		byName := map[string]*tfprotov5.SchemaAttribute{}
+		for _, attribute := range schemas.ResourceSchemas[resourceKey].Block.Attributes {
+			byName[attribute.Name] = attribute
+		}
+		return byName
+	}
+	uploadAttributes := attributes(uploadResourceKey)
+	archiveAttributes := attributes(archiveUploadResourceKey)
+
+	sdkSchema := uploadSDKSchema()
+	for key, sdkAttribute := range sdkSchema {
+		upload, archive := uploadAttributes[key], archiveAttributes[key]
+		if upload == nil || archive == nil {
+			t.Errorf("expected both resources to have %s", key)
+			continue
+		}
+
+		if !upload.Type.Equal(archive.Type) {
+			t.Errorf("%s: got type %s, want %s", key, archive.Type, upload.Type)
+		}
+		// the plugin framework marks attributes with defaults as computed, while SDKv2 doesn't
+		computed := upload.Computed == archive.Computed || sdkAttribute.Default != nil
+		if upload.Required != archive.Required || upload.Optional != archive.Optional || !computed || upload.Sensitive != archive.Sensitive || upload.WriteOnly != archive.WriteOnly {
+			t.Errorf("%s: got %+v, want %+v", key, archive, upload)
+		}
+		// the full path of an upload may also be the canonical path of its package
+		if key != fullPathKey && upload.Description != archive.Description {
+			t.Errorf("%s: got description %q, want %q", key, archive.Description, upload.Description)
+		}
+	}
+
+	// attributes added to artifacts_upload are shared, unless they're about the uploaded file or its package
+	uploadOnly := map[string]bool{
+		idKey: true, uploadPathKey: true, uploadFileKey: true, packageTypeKey: true, repositoryKey: true,
+		detectedPackageTypeKey: true, groupIDKey: true, packageNameKey: true, versionKey: true,
+	}
+	for key := range uploadAttributes {
+		if _, ok := sdkSchema[key]; !ok && !uploadOnly[key] {
+			t.Errorf("expected %s to be shared", key)
+		}
+	}
+	for key := range uploadDescriptions {
+		if _, ok := sdkSchema[key]; !ok {
+			t.Errorf("expected %s to be shared", key)
+		}
+	}
+
+	// only artifacts_upload may leave upload_path unset, to upload a package to its canonical path
+	if !uploadAttributes[uploadPathKey].Type.Equal(archiveAttributes[uploadPathKey].Type) {
+		t.Errorf("%s: got type %s, want %s", uploadPathKey, archiveAttributes[uploadPathKey].Type, uploadAttributes[uploadPathKey].Type)
+	}
+}
+
+const testResourceArchiveUploadConfig = `
+provider "artifacts" {
+  url = "https://repo.splunk.com/artifactory"
+}
+
+resource "artifacts_archive_upload" "test" {
+  upload_path = "sas-binary/terraform-provider-artifacts-test/archive.tar.gz"
+  source_dir  = "test_files/archive_source"
+}
+`
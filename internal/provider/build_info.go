@@ -0,0 +1,298 @@
+// Copyright 2021 Splunk, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	"terraform-provider-artifacts/internal/provider/internal/client"
+)
+
+// sensitiveEnvPatterns match the names of environment variables that are never captured in build info, as they
+// likely contain credentials.
+var sensitiveEnvPatterns = []string{"*password*", "*secret*", "*token*", "*key*"}
+
+func resourceBuildInfo() *schema.Resource {
+	return &schema.Resource{
+		Description:   "Publish build info to Artifactory, listing the artifacts a build produced, so that they're linked to the build in Artifactory's Builds view",
+		CreateContext: resourceBuildInfoPublish,
+		ReadContext:   resourceBuildInfoRead,
+		UpdateContext: resourceBuildInfoPublish,
+		DeleteContext: resourceBuildInfoDelete,
+		Schema: map[string]*schema.Schema{
+			buildNameKey: {
+				Description: "Name of the build, such as the name of the CI job",
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+			},
+			buildNumberKey: {
+				Description: "Number of the build, unique among builds with the same name",
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+			},
+			startedKey: {
+				Description:  "Time the build started, in RFC 3339 format. Defaults to the time the build info is first published.",
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validateBuildStarted,
+			},
+			vcsRevisionKey: {
+				Description: "Version control revision the build was built from, such as a git commit SHA",
+				Type:        schema.TypeString,
+				Optional:    true,
+			},
+			vcsURLKey: {
+				Description: "URL of the version control repository the build was built from",
+				Type:        schema.TypeString,
+				Optional:    true,
+			},
+			moduleKey: {
+				Description: "Module of the build, such as a component or package, with the artifacts it produced",
+				Type:        schema.TypeList,
+				Optional:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						moduleIDKey: {
+							Description: "ID of the module, such as `my-team:tool:1.0.0`",
+							Type:        schema.TypeString,
+							Required:    true,
+						},
+						artifactKey: {
+							Description: "Artifact produced by the module. The attributes of `artifacts_upload` of the same names may be given directly.",
+							Type:        schema.TypeList,
+							Optional:    true,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									fullPathKey: {
+										Description: "Path of the artifact, relative to the provider's URL, starting with its repository's key",
+										Type:        schema.TypeString,
+										Required:    true,
+									},
+									sha1Key: {
+										Description: "SHA1 of the artifact",
+										Type:        schema.TypeString,
+										Required:    true,
+									},
+									sha256Key: {
+										Description: "SHA256 of the artifact",
+										Type:        schema.TypeString,
+										Optional:    true,
+									},
+									md5Key: {
+										Description: "MD5 of the artifact",
+										Type:        schema.TypeString,
+										Optional:    true,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			envIncludeKey: {
+				Description: "Patterns, in the syntax of Go's `path.Match`, of the names of the provider's environment variables to capture in the build info, such as `CI_*`. Names are matched case-insensitively. No variables are captured by default.",
+				Type:        schema.TypeList,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			envExcludeKey: {
+				Description: fmt.Sprintf("Patterns of the names of environment variables matched by `%s` to leave out. Variables whose names contain `password`, `secret`, `token`, or `key` are always left out.", envIncludeKey),
+				Type:        schema.TypeList,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			setPropertiesKey: {
+				Description: fmt.Sprintf("Set to true to set the `%s` and `%s` properties on each artifact. Properties aren't removed when the build info is destroyed. Defaults to false.", buildNameProp, buildNumberProp),
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+			},
+		},
+	}
+}
+
+// validateBuildStarted is a schema.SchemaValidateFunc that checks a build's start time is in RFC 3339 format.
+func validateBuildStarted(value interface{}, key string) ([]string, []error) {
+	if _, err := time.Parse(time.RFC3339, value.(string)); err != nil {
+		return nil, []error{fmt.Errorf("%s must be in RFC 3339 format, such as 2021-11-29T15:04:05Z: %s", key, err)}
+	}
+
+	return nil, nil
+}
+
+// buildInfo returns the build info described by d, which started at started.
+func buildInfo(d resourceGetter, started time.Time) (client.BuildInfo, error) {
+	info := client.BuildInfo{
+		Name:    d.Get(buildNameKey).(string),
+		Number:  d.Get(buildNumberKey).(string),
+		Started: started.Format(client.BuildStartedLayout),
+		Agent:   &client.BuildAgent{Name: buildAgentName},
+		Modules: []client.BuildModule{},
+	}
+
+	if revision := d.Get(vcsRevisionKey).(string); revision != "" {
+		info.VCS = []client.BuildVCS{{Revision: revision, URL: d.Get(vcsURLKey).(string)}}
+	}
+
+	for _, module := range d.Get(moduleKey).([]interface{}) {
+		module := module.(map[string]interface{})
+		buildModule := client.BuildModule{ID: module[moduleIDKey].(string), Artifacts: []client.BuildArtifact{}}
+
+		for _, artifact := range module[artifactKey].([]interface{}) {
+			artifact := artifact.(map[string]interface{})
+			fullPath := artifact[fullPathKey].(string)
+			if !strings.Contains(strings.Trim(fullPath, "/"), "/") {
+				return info, fmt.Errorf("artifact path %q must start with its repository's key", fullPath)
+			}
+
+			buildModule.Artifacts = append(buildModule.Artifacts, client.NewBuildArtifact(fullPath, client.Checksums{
+				SHA1:   artifact[sha1Key].(string),
+				SHA256: artifact[sha256Key].(string),
+				MD5:    artifact[md5Key].(string),
+			}))
+		}
+
+		info.Modules = append(info.Modules, buildModule)
+	}
+
+	env, err := buildEnv(os.Environ(), stringList(d.Get(envIncludeKey)), stringList(d.Get(envExcludeKey)))
+	if err != nil {
+		return info, err
+	}
+	if len(env) > 0 {
+		info.Properties = map[string]string{}
+		for name, value := range env {
+			info.Properties[buildEnvPropPrefix+name] = value
+		}
+	}
+
+	return info, nil
+}
+
+// buildEnv returns the variables of environ, in the format of os.Environ, whose names match any of includes, and
+// none of excludes or sensitiveEnvPatterns. Names are matched case-insensitively.
+func buildEnv(environ []string, includes []string, excludes []string) (map[string]string, error) {
+	excludes = append(append([]string{}, excludes...), sensitiveEnvPatterns...)
+	for _, pattern := range append(append([]string{}, includes...), excludes...) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid environment variable pattern %q: %s", pattern, err)
+		}
+	}
+
+	matches := func(name string, patterns []string) bool {
+		for _, pattern := range patterns {
+			if matched, _ := path.Match(strings.ToLower(pattern), strings.ToLower(name)); matched {
+				return true
+			}
+		}
+		return false
+	}
+
+	env := map[string]string{}
+	for _, variable := range environ {
+		name, value, _ := strings.Cut(variable, "=")
+		if matches(name, includes) && !matches(name, excludes) {
+			env[name] = value
+		}
+	}
+
+	return env, nil
+}
+
+// resourceBuildInfoPublish publishes the build info, replacing any previously published for the same build.
+func resourceBuildInfoPublish(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*client.Client)
+
+	started := time.Now()
+	if value := d.Get(startedKey).(string); value != "" {
+		var err error
+		if started, err = time.Parse(time.RFC3339, value); err != nil {
+			return diag.Errorf("invalid %s: %s", startedKey, err)
+		}
+	}
+
+	info, err := buildInfo(d, started)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err := client.PublishBuildInfo(ctx, info); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(info.Name + "/" + info.Number)
+	if d.Get(startedKey).(string) == "" {
+		if err := d.Set(startedKey, started.Format(time.RFC3339)); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	if d.Get(setPropertiesKey).(bool) {
+		properties := map[string][]string{buildNameProp: {info.Name}, buildNumberProp: {info.Number}}
+
+		paths := []string{}
+		for _, module := range info.Modules {
+			for _, artifact := range module.Artifacts {
+				paths = append(paths, artifact.OriginalDeploymentRepo+"/"+artifact.Path)
+			}
+		}
+		sort.Strings(paths)
+
+		for _, artifactPath := range paths {
+			if err := client.SetProperties(ctx, artifactPath, properties); err != nil {
+				return diag.FromErr(err)
+			}
+		}
+	}
+
+	return resourceBuildInfoRead(ctx, d, meta)
+}
+
+func resourceBuildInfoRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*client.Client)
+
+	exists, err := client.BuildExists(ctx, d.Get(buildNameKey).(string), d.Get(buildNumberKey).(string))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if !exists {
+		d.SetId("")
+	}
+
+	return nil
+}
+
+func resourceBuildInfoDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*client.Client)
+
+	if err := client.DeleteBuild(ctx, d.Get(buildNameKey).(string), d.Get(buildNumberKey).(string)); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return nil
+}
@@ -0,0 +1,97 @@
+// Copyright 2021 Splunk, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccResourceBuildInfo(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV5ProviderFactories: protoV5ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testResourceBuildInfoConfig,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("artifacts_build_info.test", "id", "terraform-provider-artifacts-test/1"),
+					resource.TestCheckResourceAttr("artifacts_build_info.test", "started", "2021-11-29T15:04:05Z"),
+				),
+			},
+		},
+	})
+}
+
+const testResourceBuildInfoConfig = `
+provider "artifacts" {
+  url = "https://repo.splunk.com/artifactory"
+}
+
+resource "artifacts_upload" "test" {
+  upload_path = "sas-binary/terraform-provider-artifacts-test/1.0.0/test_file.txt"
+  upload_file = "test_files/source_file.txt"
+}
+
+resource "artifacts_build_info" "test" {
+  name    = "terraform-provider-artifacts-test"
+  number  = "1"
+  started = "2021-11-29T15:04:05Z"
+
+  module {
+    id = "terraform-provider-artifacts-test:1.0.0"
+
+    artifact {
+      full_path = artifacts_upload.test.full_path
+      sha1      = artifacts_upload.test.sha1
+      sha256    = artifacts_upload.test.sha256
+      md5       = artifacts_upload.test.md5
+    }
+  }
+
+  set_properties = true
+}
+`
+
+func TestBuildEnv(t *testing.T) {
+	environ := []string{
+		"CI_JOB_ID=1234",
+		"ci_pipeline_url=https://ci.example.com/1234",
+		"CI_JOB_TOKEN=secret",
+		"CI_DEPLOY_PASSWORD=secret",
+		"CI_RUNNER_ID=5",
+		"HOME=/root",
+		"CI_EMPTY=",
+	}
+
+	got, err := buildEnv(environ, []string{"CI_*"}, []string{"ci_runner_*"})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	want := map[string]string{
+		"CI_JOB_ID":       "1234",
+		"ci_pipeline_url": "https://ci.example.com/1234",
+		"CI_EMPTY":        "",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	if _, err := buildEnv(environ, []string{"["}, nil); err == nil {
+		t.Error("expected error for an invalid pattern")
+	}
+}
@@ -0,0 +1,176 @@
+// Copyright 2021 Splunk, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	"terraform-provider-artifacts/internal/provider/internal/client"
+)
+
+func resourceBuildPromotion() *schema.Resource {
+	return &schema.Resource{
+		Description:   "Promote a published build, copying or moving its artifacts to another repository, such as from a staging repository to a release repository",
+		CreateContext: resourceBuildPromotionCreate,
+		ReadContext:   resourceBuildPromotionRead,
+		DeleteContext: resourceBuildPromotionDelete,
+		Schema: map[string]*schema.Schema{
+			buildNameKey: {
+				Description: "Name of the build to promote",
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+			},
+			buildNumberKey: {
+				Description: "Number of the build to promote",
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+			},
+			targetRepositoryKey: {
+				Description: "Key of the repository to promote the build's artifacts to",
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+			},
+			sourceRepositoryKey: {
+				Description: "Key of the repository to promote the build's artifacts from. Defaults to any repository the artifacts are in.",
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+			},
+			promotionStatusKey: {
+				Description: "Status recorded on the build by the promotion, such as `released`",
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+			},
+			promotionCommentKey: {
+				Description: "Comment recorded on the build by the promotion",
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+			},
+			copyKey: {
+				Description: "Set to true to copy the build's artifacts to the target repository, leaving them in place. Defaults to false, which moves them.",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				ForceNew:    true,
+			},
+			dependenciesKey: {
+				Description: "Set to true to also promote the build's dependencies. Defaults to false.",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				ForceNew:    true,
+			},
+			scopesKey: {
+				Description: fmt.Sprintf("Scopes of the dependencies to promote, when %s is true. Defaults to all scopes.", dependenciesKey),
+				Type:        schema.TypeList,
+				Optional:    true,
+				ForceNew:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			triggersKey: {
+				Description: "Arbitrary map of values that, when changed, will trigger the build to be promoted again.",
+				Type:        schema.TypeMap,
+				Optional:    true,
+				ForceNew:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			promotedAtKey: {
+				Description: "Time the build was promoted, in RFC 3339 format",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			warningsKey: {
+				Description: "Warnings the service reported when promoting the build",
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+		},
+	}
+}
+
+// buildPromotion returns the promotion described by d, taking place at promotedAt.
+func buildPromotion(d resourceGetter, promotedAt time.Time) client.BuildPromotion {
+	return client.BuildPromotion{
+		Status:       d.Get(promotionStatusKey).(string),
+		Comment:      d.Get(promotionCommentKey).(string),
+		Timestamp:    promotedAt.Format(client.BuildStartedLayout),
+		SourceRepo:   d.Get(sourceRepositoryKey).(string),
+		TargetRepo:   d.Get(targetRepositoryKey).(string),
+		Copy:         d.Get(copyKey).(bool),
+		Artifacts:    true,
+		Dependencies: d.Get(dependenciesKey).(bool),
+		Scopes:       stringList(d.Get(scopesKey)),
+		// check every artifact can be promoted before promoting any
+		FailFast: true,
+	}
+}
+
+func resourceBuildPromotionCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*client.Client)
+
+	name := d.Get(buildNameKey).(string)
+	number := d.Get(buildNumberKey).(string)
+	promotedAt := time.Now()
+
+	warnings, err := client.PromoteBuild(ctx, name, number, buildPromotion(d, promotedAt))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(fmt.Sprintf("%s/%s/%s", name, number, d.Get(targetRepositoryKey).(string)))
+	if err := d.Set(promotedAtKey, promotedAt.UTC().Format(time.RFC3339)); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set(warningsKey, warnings); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return resourceBuildPromotionRead(ctx, d, meta)
+}
+
+// resourceBuildPromotionRead removes the promotion from state if its build has been deleted. The promotion itself
+// can't be read back, as builds only record their latest status.
+func resourceBuildPromotionRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*client.Client)
+
+	exists, err := client.BuildExists(ctx, d.Get(buildNameKey).(string), d.Get(buildNumberKey).(string))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if !exists {
+		d.SetId("")
+	}
+
+	return nil
+}
+
+// resourceBuildPromotionDelete only removes the promotion from state, as a promotion can't be undone.
+func resourceBuildPromotionDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	d.SetId("")
+
+	return nil
+}
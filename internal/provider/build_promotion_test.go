@@ -0,0 +1,88 @@
+// Copyright 2021 Splunk, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccResourceBuildPromotion(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV5ProviderFactories: protoV5ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testResourceBuildPromotionConfig,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("artifacts_build_promotion.test", "id", "terraform-provider-artifacts-test/2/sas-binary-release"),
+					resource.TestCheckResourceAttrSet("artifacts_build_promotion.test", "promoted_at"),
+				),
+			},
+			{
+				Config:      testResourceBuildPromotionMissingConfig,
+				ExpectError: regexp.MustCompile("unable to promote build terraform-provider-artifacts-test/missing"),
+			},
+		},
+	})
+}
+
+const testResourceBuildPromotionConfig = `
+provider "artifacts" {
+  url = "https://repo.splunk.com/artifactory"
+}
+
+resource "artifacts_upload" "test" {
+  upload_path = "sas-binary/terraform-provider-artifacts-test/1.0.0/test_file.txt"
+  upload_file = "test_files/source_file.txt"
+}
+
+resource "artifacts_build_info" "test" {
+  name   = "terraform-provider-artifacts-test"
+  number = "2"
+
+  module {
+    id = "terraform-provider-artifacts-test:1.0.0"
+
+    artifact {
+      full_path = artifacts_upload.test.full_path
+      sha1      = artifacts_upload.test.sha1
+    }
+  }
+}
+
+resource "artifacts_build_promotion" "test" {
+  name              = artifacts_build_info.test.name
+  number            = artifacts_build_info.test.number
+  source_repository = "sas-binary"
+  target_repository = "sas-binary-release"
+  status            = "released"
+  copy              = true
+}
+`
+
+const testResourceBuildPromotionMissingConfig = `
+provider "artifacts" {
+  url = "https://repo.splunk.com/artifactory"
+}
+
+resource "artifacts_build_promotion" "test" {
+  name              = "terraform-provider-artifacts-test"
+  number            = "missing"
+  target_repository = "sas-binary-release"
+  copy              = true
+}
+`
@@ -22,10 +22,267 @@ const (
 	usernameEnvKey    = "ARTIFACTORY_AUTH_USERNAME"
 	passwordKey       = "password"
 	passwordEnvKey    = "ARTIFACTORY_AUTH_PASSWORD"
+	checksumTypeKey   = "checksum_type"
 	uploadResourceKey = "artifacts_upload"
 	uploadPathKey     = "upload_path"
 	uploadFileKey     = "upload_file"
 	deleteOldPath     = "delete_old_path"
 	sha1Key           = "sha1"
+	sha256Key         = "sha256"
+	md5Key            = "md5"
 	triggersKey       = "triggers"
 )
+
+// multipart upload settings
+const (
+	multipartThresholdKey   = "multipart_threshold"
+	multipartPartSizeKey    = "multipart_part_size"
+	multipartConcurrencyKey = "multipart_concurrency"
+	multipartStateDirKey    = "multipart_state_dir"
+)
+
+// transfer limits
+const (
+	maxConcurrentTransfersKey = "max_concurrent_transfers"
+	maxBytesPerSecondKey      = "max_bytes_per_second"
+)
+
+// path resolution
+const (
+	basePathKey = "base_path"
+	pathVarsKey = "path_vars"
+	fullPathKey = "full_path"
+)
+
+// credential sources
+const (
+	accessTokenKey        = "access_token"
+	accessTokenEnvKey     = "ARTIFACTORY_ACCESS_TOKEN"
+	netrcKey              = "netrc"
+	netrcFileKey          = "netrc_file"
+	jfrogCLIServerIDKey   = "jfrog_cli_server_id"
+	jfrogCLIConfigFileKey = "jfrog_cli_config_file"
+	credentialHelperKey   = "credential_helper"
+)
+
+// preflight and system information
+const (
+	preflightKey        = "preflight"
+	systemDataSourceKey = "artifacts_system"
+	versionKey          = "version"
+	revisionKey         = "revision"
+	userKey             = "user"
+)
+
+// artifacts_folder
+const (
+	folderResourceKey = "artifacts_folder"
+	folderPathKey     = "path"
+	forceDestroyKey   = "force_destroy"
+	createdKey        = "created"
+)
+
+// artifacts_pointer
+const (
+	pointerResourceKey  = "artifacts_pointer"
+	pointerPathKey      = "path"
+	targetPathKey       = "target_path"
+	pointerModeKey      = "mode"
+	pointerContentKey   = "content"
+	targetFullPathKey   = "target_full_path"
+	targetURLKey        = "target_url"
+	targetSHA1Key       = "target_sha1"
+	pointerTargetProp   = "pointer.target"
+	pointerURLProp      = "pointer.url"
+	pointerModeCopy     = "copy"
+	pointerModeFile     = "file"
+	pointerModeProperty = "property"
+)
+
+// artifacts_retention
+const (
+	retentionResourceKey = "artifacts_retention"
+	retentionPathKey     = "path"
+	versionRegexKey      = "version_regex"
+	keepLatestKey        = "keep_latest"
+	maxAgeKey            = "max_age"
+	pinPropertyKey       = "pin_property"
+	dryRunKey            = "dry_run"
+	retainedVersionsKey  = "retained_versions"
+	candidatesKey        = "candidates"
+	deletedKey           = "deleted"
+	defaultPinProperty   = "retention.pinned"
+)
+
+// artifacts_archive_upload
+const (
+	archiveUploadResourceKey = "artifacts_archive_upload"
+	sourceDirKey             = "source_dir"
+	archiveFormatKey         = "format"
+	excludesKey              = "excludes"
+	sourceHashKey            = "source_hash"
+	archiveSHA256Key         = "archive_sha256"
+)
+
+// artifacts_upload sidecars
+const (
+	checksumSidecarsKey     = "checksum_sidecars"
+	signingKeyKey           = "signing_key"
+	signingKeyPassphraseKey = "signing_key_passphrase"
+	sidecarPathsKey         = "sidecar_paths"
+	signatureExtension      = "asc"
+)
+
+// artifacts_maven
+const (
+	mavenResourceKey  = "artifacts_maven"
+	repositoryKey     = "repository"
+	groupIDKey        = "group_id"
+	artifactIDKey     = "artifact_id"
+	classifierKey     = "classifier"
+	extensionKey      = "extension"
+	generatePOMKey    = "generate_pom"
+	packagingKey      = "packaging"
+	updateMetadataKey = "update_metadata"
+	pomPathKey        = "pom_path"
+	fileVersionKey    = "file_version"
+)
+
+// artifacts_debian_package
+const (
+	debianPackageResourceKey = "artifacts_debian_package"
+	distributionsKey         = "distributions"
+	componentKey             = "component"
+	architectureKey          = "architecture"
+	packageNameKey           = "package_name"
+	defaultDebianComponent   = "main"
+	debDistributionProp      = "deb.distribution"
+	debComponentProp         = "deb.component"
+	debArchitectureProp      = "deb.architecture"
+)
+
+// artifacts_rpm_package
+const (
+	rpmPackageResourceKey = "artifacts_rpm_package"
+	rpmDirectoryKey       = "directory"
+	calculateMetadataKey  = "calculate_metadata"
+	releaseKey            = "release"
+)
+
+// artifacts_helm_chart
+const (
+	helmChartResourceKey = "artifacts_helm_chart"
+	helmChartKey         = "chart"
+	helmDirectoryKey     = "directory"
+	reindexKey           = "reindex"
+	helmChartNameKey     = "name"
+	appVersionKey        = "app_version"
+	digestKey            = "digest"
+)
+
+// artifacts_upload package types
+const (
+	packageTypeKey         = "package_type"
+	detectedPackageTypeKey = "detected_package_type"
+)
+
+// artifacts_terraform_module
+const (
+	terraformModuleResourceKey = "artifacts_terraform_module"
+	namespaceKey               = "namespace"
+	terraformNameKey           = "name"
+	systemKey                  = "system"
+)
+
+// artifacts_terraform_provider
+const (
+	terraformProviderResourceKey = "artifacts_terraform_provider"
+	platformKey                  = "platform"
+	osKey                        = "os"
+	archKey                      = "arch"
+	packagePathsKey              = "package_paths"
+	shasumsKey                   = "shasums"
+	shasumsPathKey               = "shasums_path"
+	shasumsSignaturePathKey      = "shasums_signature_path"
+	keyIDKey                     = "key_id"
+	shasumsSignatureExtension    = "sig"
+)
+
+// artifacts_build_info
+const (
+	buildInfoResourceKey = "artifacts_build_info"
+	buildNameKey         = "name"
+	buildNumberKey       = "number"
+	startedKey           = "started"
+	vcsRevisionKey       = "vcs_revision"
+	vcsURLKey            = "vcs_url"
+	moduleKey            = "module"
+	moduleIDKey          = "id"
+	artifactKey          = "artifact"
+	envIncludeKey        = "env_include"
+	envExcludeKey        = "env_exclude"
+	setPropertiesKey     = "set_properties"
+	buildNameProp        = "build.name"
+	buildNumberProp      = "build.number"
+	buildEnvPropPrefix   = "buildInfo.env."
+	buildAgentName       = "terraform-provider-artifacts"
+)
+
+// artifacts_build_promotion
+const (
+	buildPromotionResourceKey = "artifacts_build_promotion"
+	targetRepositoryKey       = "target_repository"
+	sourceRepositoryKey       = "source_repository"
+	promotionStatusKey        = "status"
+	promotionCommentKey       = "comment"
+	copyKey                   = "copy"
+	dependenciesKey           = "dependencies"
+	scopesKey                 = "scopes"
+	promotedAtKey             = "promoted_at"
+	warningsKey               = "warnings"
+)
+
+// artifacts_signed_url
+const (
+	signedURLEphemeralResourceKey = "artifacts_signed_url"
+	signedURLPathKey              = "path"
+	expiresInKey                  = "expires_in"
+	signedURLMethodKey            = "method"
+	tokenScopeKey                 = "token_scope"
+	tokenKey                      = "token"
+	expiresAtKey                  = "expires_at"
+	signedURLMethodAuto           = "auto"
+	signedURLMethodSigned         = "signed_url"
+	signedURLMethodAccessToken    = "access_token"
+	defaultSignedURLExpiresIn     = 3600
+)
+
+// framework resources
+const (
+	idKey = "id"
+)
+
+// provider functions
+const (
+	pathJoinFunctionKey    = "path_join"
+	downloadURLFunctionKey = "download_url"
+	mavenPathFunctionKey   = "maven_path"
+	fileSHA256FunctionKey  = "file_sha256"
+)
+
+// artifacts_access_token
+const (
+	accessTokenEphemeralResourceKey = "artifacts_access_token"
+	scopeKey                        = "scope"
+	descriptionKey                  = "description"
+	tokenIDPrivateKey               = "token_id"
+	defaultAccessTokenDescription   = "Terraform run"
+	defaultTokenScope               = "applied-permissions/user"
+)
+
+// write-only signing keys
+const (
+	signingKeyWOKey           = "signing_key_wo"
+	signingKeyPassphraseWOKey = "signing_key_passphrase_wo"
+	signingKeyWOVersionKey    = "signing_key_wo_version"
+)
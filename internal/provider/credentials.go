@@ -0,0 +1,95 @@
+// Copyright 2021 Splunk, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+
+	"terraform-provider-artifacts/internal/provider/internal/client"
+)
+
+// configureCredentials sets the credentials of c from the first configured source, in order of precedence:
+// username and password, access_token, credential_helper, jfrog_cli_server_id, and netrc. It returns the key of the
+// attribute that configured the source used, or an empty string if there are no credentials. Unset username,
+// password, and access_token are read from their environment variables.
+func configureCredentials(ctx context.Context, config providerModel, c *client.Client) (string, diag.Diagnostics) {
+	if username := stringOrEnv(config.Username, usernameEnvKey); username != "" {
+		c.Username = username
+		// we're counting on AlsoRequires validation, so set Password if Username is given
+		c.Password = stringOrEnv(config.Password, passwordEnvKey)
+		return usernameKey, nil
+	}
+
+	if token := stringOrEnv(config.AccessToken, accessTokenEnvKey); token != "" {
+		c.Token = token
+		return accessTokenKey, nil
+	}
+
+	command := []string{}
+	if diags := config.CredentialHelper.ElementsAs(ctx, &command, false); diags.HasError() {
+		return "", diags
+	}
+	if len(command) > 0 {
+		// the helper isn't run until credentials are needed
+		c.CredentialHelper = client.NewCredentialHelper(command, c.URL)
+		return credentialHelperKey, nil
+	}
+
+	if serverID := config.JFrogCLIServerID.ValueString(); serverID != "" {
+		configFile := config.JFrogCLIConfigFile.ValueString()
+		if configFile == "" {
+			configFile = client.DefaultJFrogCLIConfigFile()
+		}
+
+		credentials, err := client.JFrogCLICredentials(configFile, serverID)
+		if err != nil {
+			return "", attributeError(jfrogCLIServerIDKey, "Unable to read JFrog CLI credentials", err.Error())
+		}
+		c.Username, c.Password, c.Token = credentials.Username, credentials.Password, credentials.Token
+
+		return jfrogCLIServerIDKey, nil
+	}
+
+	if config.Netrc.ValueBool() {
+		netrcFile := config.NetrcFile.ValueString()
+		if netrcFile == "" {
+			netrcFile = client.DefaultNetrcFile()
+		}
+
+		credentials, ok, err := client.NetrcCredentials(netrcFile, c.URL)
+		if err != nil {
+			return "", attributeError(netrcKey, "Unable to read netrc credentials", err.Error())
+		}
+		if !ok {
+			return "", attributeError(netrcKey, "No netrc credentials found", "The netrc file "+netrcFile+" has no entry for the host of "+c.URL+", and no default entry.")
+		}
+		c.Username, c.Password = credentials.Username, credentials.Password
+
+		return netrcKey, nil
+	}
+
+	return "", nil
+}
+
+// attributeError returns diagnostics with a single error about a provider attribute.
+func attributeError(key string, summary string, detail string) diag.Diagnostics {
+	diags := diag.Diagnostics{}
+	diags.AddAttributeError(path.Root(key), summary, detail)
+
+	return diags
+}
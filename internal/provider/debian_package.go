@@ -0,0 +1,311 @@
+// Copyright 2021 Splunk, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	"terraform-provider-artifacts/internal/provider/internal/client"
+	"terraform-provider-artifacts/internal/provider/internal/pkginfo"
+)
+
+func resourceDebianPackage() *schema.Resource {
+	return &schema.Resource{
+		Description:   "Upload a Debian package to a Debian repository in Artifactory, in the repository's pool, and index it for distributions",
+		CreateContext: resourceDebianPackageCreate,
+		ReadContext:   resourceDebianPackageRead,
+		UpdateContext: resourceDebianPackageUpdate,
+		DeleteContext: resourceDebianPackageDelete,
+		CustomizeDiff: resourceDebianPackageDiff,
+		Schema: map[string]*schema.Schema{
+			repositoryKey: {
+				Description: fmt.Sprintf("Key of the Debian repository to upload to. The provider's `%s` isn't applied, as packages are always in the repository's pool.", basePathKey),
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+			},
+			uploadFileKey: {
+				Description: "Debian package file to upload",
+				Type:        schema.TypeString,
+				Required:    true,
+			},
+			distributionsKey: {
+				Description: "Distributions to index the package for, such as `focal` and `jammy`",
+				Type:        schema.TypeList,
+				Required:    true,
+				MinItems:    1,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			componentKey: {
+				Description: fmt.Sprintf("Component to index the package for. Defaults to `%s`.", defaultDebianComponent),
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     defaultDebianComponent,
+			},
+			architectureKey: {
+				Description: "Architecture to index the package for, such as `amd64`. Defaults to the package's architecture.",
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+			},
+			deleteOldPath: {
+				Description: "Set to false if the uploaded package should be orphaned on destruction of the resource or change of its path. Defaults to true.",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+			},
+			triggersKey: {
+				Description: "Arbitrary map of values that, when changed, will trigger re-uploading of this resource's file.",
+				Type:        schema.TypeMap,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			packageNameKey: {
+				Description: "Name of the package, from its control file",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			versionKey: {
+				Description: "Version of the package, from its control file",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			fullPathKey: {
+				Description: "Path the package is uploaded to in the repository's pool, relative to the provider's URL",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			sha1Key: {
+				Description: "SHA1 of the uploaded package",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			sha256Key: {
+				Description: "SHA256 of the uploaded package",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			md5Key: {
+				Description: "MD5 of the uploaded package",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+		},
+	}
+}
+
+// debianPackage is a Debian package to upload, and where to upload it.
+type debianPackage struct {
+	control  pkginfo.DebianControl
+	fullPath string
+}
+
+// readDebianPackage reads the control file of the package in d, and determines its path in the repository's pool.
+// The architecture in d, when configured, overrides the package's own.
+func readDebianPackage(d interface {
+	resourceGetter
+	GetRawConfig() cty.Value
+}) (debianPackage, error) {
+	filename := d.Get(uploadFileKey).(string)
+	control, err := pkginfo.ReadDebian(filename)
+	if err != nil {
+		return debianPackage{}, err
+	}
+
+	if !d.GetRawConfig().GetAttr(architectureKey).IsNull() {
+		control.Architecture = d.Get(architectureKey).(string)
+	}
+
+	fullPath, err := client.JoinPath(d.Get(repositoryKey).(string), control.PoolPath(d.Get(componentKey).(string)))
+	if err != nil {
+		return debianPackage{}, err
+	}
+
+	return debianPackage{control: control, fullPath: fullPath}, nil
+}
+
+// debianProperties returns the properties that index a package in a Debian repository.
+func debianProperties(d resourceGetter, control pkginfo.DebianControl) client.Properties {
+	return client.Properties{
+		debDistributionProp: stringList(d.Get(distributionsKey)),
+		debComponentProp:    {d.Get(componentKey).(string)},
+		debArchitectureProp: {control.Architecture},
+	}
+}
+
+func resourceDebianPackageCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*client.Client)
+
+	pkg, err := readDebianPackage(d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	filePath, err := filepath.Abs(d.Get(uploadFileKey).(string))
+	if err != nil {
+		return diag.Errorf("unable to determine absolute path for file %s", d.Get(uploadFileKey).(string))
+	}
+
+	if err := client.Upload(ctx, pkg.fullPath, filePath, debianProperties(d, pkg.control)); err != nil {
+		return diag.Errorf("failure uploading package %s: %s", filePath, err)
+	}
+
+	d.SetId(pkg.fullPath)
+	if err := setDebianPackage(d, pkg); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return resourceDebianPackageRead(ctx, d, meta)
+}
+
+func resourceDebianPackageRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*client.Client)
+
+	checksums, err := client.Checksums(ctx, d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if checksums.Get(client.ChecksumType) == "" {
+		d.SetId("")
+		return nil
+	}
+
+	if err := setChecksums(d, checksums); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return nil
+}
+
+// resourceDebianPackageUpdate uploads the package again if it or its path changed, and otherwise only updates the
+// properties that index it.
+func resourceDebianPackageUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*client.Client)
+
+	pkg, err := readDebianPackage(d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if pkg.fullPath != d.Id() {
+		if d.Get(deleteOldPath).(bool) {
+			if err := deleteExisting(ctx, client, []string{d.Id()}); err != nil {
+				return diag.FromErr(err)
+			}
+		}
+
+		return resourceDebianPackageCreate(ctx, d, meta)
+	}
+
+	if d.HasChanges(uploadFileKey, triggersKey, sha1Key, sha256Key, md5Key) {
+		return resourceDebianPackageCreate(ctx, d, meta)
+	}
+
+	if err := client.SetProperties(ctx, pkg.fullPath, debianProperties(d, pkg.control)); err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err := setDebianPackage(d, pkg); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return resourceDebianPackageRead(ctx, d, meta)
+}
+
+func resourceDebianPackageDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*client.Client)
+
+	if d.Get(deleteOldPath).(bool) {
+		if err := deleteExisting(ctx, client, []string{d.Id()}); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	return nil
+}
+
+func resourceDebianPackageDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if d.HasChanges(uploadFileKey, triggersKey) {
+		if err := setNewComputedChecksums(d); err != nil {
+			return err
+		}
+	}
+
+	packageKeys := []string{packageNameKey, versionKey, fullPathKey}
+	if d.GetRawConfig().GetAttr(architectureKey).IsNull() {
+		packageKeys = append(packageKeys, architectureKey)
+	}
+
+	if known, err := diffPackageFile(d, uploadFileKey, []string{repositoryKey, componentKey, architectureKey}, packageKeys); !known || err != nil {
+		return err
+	}
+
+	pkg, err := readDebianPackage(d)
+	if err != nil {
+		return err
+	}
+
+	if pkg.fullPath != d.Get(fullPathKey).(string) && d.Id() != "" {
+		if err := setNewComputedChecksums(d); err != nil {
+			return err
+		}
+	}
+
+	for key, value := range map[string]string{
+		packageNameKey:  pkg.control.Package,
+		versionKey:      pkg.control.Version,
+		fullPathKey:     pkg.fullPath,
+		architectureKey: pkg.control.Architecture,
+	} {
+		if value != d.Get(key).(string) {
+			if err := d.SetNew(key, value); err != nil {
+				return err
+			}
+		}
+	}
+
+	drifted, err := uploadDrifted(d, meta)
+	if err != nil {
+		return err
+	}
+	if drifted {
+		return setNewComputedChecksums(d)
+	}
+
+	return nil
+}
+
+// setDebianPackage sets the attributes read from a package's control file.
+func setDebianPackage(d *schema.ResourceData, pkg debianPackage) error {
+	for key, value := range map[string]string{
+		packageNameKey:  pkg.control.Package,
+		versionKey:      pkg.control.Version,
+		architectureKey: pkg.control.Architecture,
+		fullPathKey:     pkg.fullPath,
+	} {
+		if err := d.Set(key, value); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
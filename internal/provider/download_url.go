@@ -0,0 +1,85 @@
+// Copyright 2021 Splunk, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+
+	"terraform-provider-artifacts/internal/provider/internal/client"
+)
+
+// downloadURLFunction is provider::artifacts::download_url, which returns the URL of a file the way uploads build it.
+type downloadURLFunction struct{}
+
+func newDownloadURLFunction() function.Function {
+	return downloadURLFunction{}
+}
+
+// Metadata implements function.Function.
+func (f downloadURLFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = downloadURLFunctionKey
+}
+
+// Definition implements function.Function.
+func (f downloadURLFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "URL to download a file from",
+		Description: fmt.Sprintf("Returns the URL of the file at `path`, relative to `url`, as the provider builds it to upload the file. "+
+			"Terraform doesn't give provider functions the provider's configuration, so `url` is the provider's `%s`, usually "+
+			"from the same variable. The provider's `%s` isn't applied, so `path` is usually a `%s`.", urlKey, basePathKey, fullPathKey),
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "url",
+				Description: "URL of the Artifactory server, such as `https://example.com/artifactory`",
+			},
+			function.StringParameter{
+				Name:        "path",
+				Description: "Path of the file, relative to `url`",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+// Run implements function.Function.
+func (f downloadURLFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var serverURL, path string
+	resp.Error = function.ConcatFuncErrors(resp.Error, req.Arguments.Get(ctx, &serverURL, &path))
+	if resp.Error != nil {
+		return
+	}
+
+	parsed, err := url.Parse(serverURL)
+	if err != nil || parsed.Scheme == "" || parsed.Host == "" {
+		resp.Error = function.NewArgumentFuncError(0, fmt.Sprintf("url %q must be an absolute URL", serverURL))
+		return
+	}
+
+	joined, err := client.JoinPath(path)
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(1, err.Error())
+		return
+	}
+	if joined == "" {
+		resp.Error = function.NewArgumentFuncError(1, "path must not be empty")
+		return
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Error, resp.Result.Set(ctx, client.Client{URL: serverURL}.FileURL(joined)))
+}
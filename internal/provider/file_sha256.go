@@ -0,0 +1,68 @@
+// Copyright 2021 Splunk, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+
+	"terraform-provider-artifacts/internal/provider/internal/client"
+)
+
+// fileSHA256Function is provider::artifacts::file_sha256, which returns a local file's SHA256 as uploads compute it.
+type fileSHA256Function struct{}
+
+func newFileSHA256Function() function.Function {
+	return fileSHA256Function{}
+}
+
+// Metadata implements function.Function.
+func (f fileSHA256Function) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = fileSHA256FunctionKey
+}
+
+// Definition implements function.Function.
+func (f fileSHA256Function) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "SHA256 of a local file",
+		Description: "Returns the hex-encoded SHA256 of a local file, as sent along with the file when it's uploaded. " +
+			"Unlike the built-in `filesha256`, the file isn't read into memory, so large files can be hashed.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "path",
+				Description: "Path of the local file",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+// Run implements function.Function.
+func (f fileSHA256Function) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var path string
+	resp.Error = function.ConcatFuncErrors(resp.Error, req.Arguments.Get(ctx, &path))
+	if resp.Error != nil {
+		return
+	}
+
+	checksums, err := client.Client{}.FileChecksums(path)
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(0, err.Error())
+		return
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Error, resp.Result.Set(ctx, checksums.SHA256))
+}
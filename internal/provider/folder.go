@@ -0,0 +1,165 @@
+// Copyright 2021 Splunk, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	"terraform-provider-artifacts/internal/provider/internal/client"
+)
+
+func resourceFolder() *schema.Resource {
+	return &schema.Resource{
+		Description:   "Create a folder in Artifactory. An existing empty folder is adopted, but one that already has content must be imported.",
+		CreateContext: resourceFolderCreate,
+		ReadContext:   resourceFolderRead,
+		// only force_destroy can change without replacing the folder, and it's only used during destroy
+		UpdateContext: resourceFolderRead,
+		DeleteContext: resourceFolderDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceFolderImport,
+		},
+		Schema: map[string]*schema.Schema{
+			folderPathKey: {
+				Description: fmt.Sprintf("Path of the folder, relative to the provider's URL and `%s`", basePathKey),
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+			},
+			forceDestroyKey: {
+				Description: "Set to true to delete the folder even if it isn't empty, along with everything in it. Defaults to false, which fails to destroy a folder that isn't empty.",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+			},
+			fullPathKey: {
+				Description: fmt.Sprintf("Path of the folder, relative to the provider's URL, after applying the provider's `%s`", basePathKey),
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			createdKey: {
+				Description: "Time the folder was created",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+		},
+	}
+}
+
+func resourceFolderCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*client.Client)
+
+	folderPath, err := client.ResolvePath(d.Get(folderPathKey).(string), nil)
+	if err != nil {
+		return diag.Errorf("unable to resolve %s: %s", folderPathKey, err)
+	}
+
+	// adopting a folder with content would let force_destroy delete content this resource didn't create
+	info, found, err := client.Folder(ctx, folderPath)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if found && len(info.Children) > 0 {
+		return diag.Errorf("folder %s already exists, containing %s. Import it to manage an existing folder.", folderPath, strings.Join(info.Children, ", "))
+	}
+
+	if err := client.CreateFolder(ctx, folderPath); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(folderPath)
+
+	return resourceFolderRead(ctx, d, meta)
+}
+
+func resourceFolderRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*client.Client)
+
+	info, found, err := client.Folder(ctx, d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if !found {
+		d.SetId("")
+		return nil
+	}
+
+	if err := d.Set(fullPathKey, d.Id()); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set(createdKey, info.Created); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return nil
+}
+
+func resourceFolderDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*client.Client)
+
+	info, found, err := client.Folder(ctx, d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if !found {
+		return nil
+	}
+
+	if len(info.Children) > 0 && !d.Get(forceDestroyKey).(bool) {
+		return diag.Errorf("folder %s isn't empty, containing %s. Set %s to true to delete it and everything in it.", d.Id(), strings.Join(info.Children, ", "), forceDestroyKey)
+	}
+
+	if err := client.Delete(ctx, d.Id()); err != nil {
+		return diag.Errorf("error attempting delete: %s", err)
+	}
+
+	return nil
+}
+
+// resourceFolderImport imports a folder by its full path, setting path relative to the provider's base_path.
+func resourceFolderImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	c := meta.(*client.Client)
+
+	fullPath, err := client.JoinPath(d.Id())
+	if err != nil {
+		return nil, err
+	}
+
+	relativePath := fullPath
+	if c.BasePath != "" {
+		basePath, _ := client.JoinPath(c.BasePath)
+		if !strings.HasPrefix(fullPath, basePath+"/") {
+			return nil, fmt.Errorf("folder %s isn't within the provider's %s %s", fullPath, basePathKey, basePath)
+		}
+		relativePath = strings.TrimPrefix(fullPath, basePath+"/")
+	}
+
+	d.SetId(fullPath)
+	if err := d.Set(folderPathKey, relativePath); err != nil {
+		return nil, err
+	}
+	if err := d.Set(forceDestroyKey, false); err != nil {
+		return nil, err
+	}
+
+	return []*schema.ResourceData{d}, nil
+}
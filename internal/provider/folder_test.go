@@ -0,0 +1,81 @@
+// Copyright 2021 Splunk, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccResourceFolder(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV5ProviderFactories: protoV5ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testResourceFolderConfig,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("artifacts_folder.test", "full_path", "sas-binary/terraform-provider-artifacts-test/folder"),
+					resource.TestCheckResourceAttrSet("artifacts_folder.test", "created"),
+				),
+			},
+			{
+				ResourceName:      "artifacts_folder.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestAccResourceFolderExisting(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV5ProviderFactories: protoV5ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testResourceFolderExistingConfig,
+				ExpectError: regexp.MustCompile(`already exists, containing source_file.txt`),
+			},
+		},
+	})
+}
+
+const testResourceFolderConfig = `
+provider "artifacts" {
+  url = "https://repo.splunk.com/artifactory"
+}
+
+resource "artifacts_folder" "test" {
+  path = "sas-binary/terraform-provider-artifacts-test/folder"
+}
+`
+
+const testResourceFolderExistingConfig = `
+provider "artifacts" {
+  url = "https://repo.splunk.com/artifactory"
+}
+
+resource "artifacts_upload" "test" {
+  upload_path = "sas-binary/terraform-provider-artifacts-test/existing-folder/source_file.txt"
+  upload_file = "test_files/source_file.txt"
+}
+
+resource "artifacts_folder" "test" {
+  path = "sas-binary/terraform-provider-artifacts-test/existing-folder"
+
+  depends_on = [artifacts_upload.test]
+}
+`
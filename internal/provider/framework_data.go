@@ -0,0 +1,234 @@
+// Copyright 2021 Splunk, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// frameworkGetter is implemented by tfsdk.Config, tfsdk.Plan, and tfsdk.State.
+type frameworkGetter interface {
+	GetAttribute(ctx context.Context, path path.Path, target interface{}) diag.Diagnostics
+}
+
+// frameworkValues is implemented by tfsdk.Plan and tfsdk.State.
+type frameworkValues interface {
+	frameworkGetter
+	SetAttribute(ctx context.Context, path path.Path, value interface{}) diag.Diagnostics
+}
+
+// frameworkData adapts the plan or state of a framework resource to the key-based getters and setters of
+// schema.ResourceData and schema.ResourceDiff, so that it can be read and written by logic shared with SDKv2
+// resources. Values are read as schema.ResourceData returns them, with null and unknown values read as the zero value
+// of their type. Errors reading values are collected in Diagnostics.
+type frameworkData struct {
+	ctx context.Context
+	// prior is the state before the change being planned or applied. Its Raw value is null for a new resource, but
+	// its Schema must be set.
+	prior tfsdk.State
+	// values are the plan being modified, or the state being written.
+	values frameworkValues
+	// config is the configuration write-only attributes are read from, which is nil when it isn't available, such as
+	// when reading or deleting a resource.
+	config      *tfsdk.Config
+	Diagnostics diag.Diagnostics
+}
+
+// newFrameworkData returns a frameworkData reading and writing values, and reading prior values from prior.
+func newFrameworkData(ctx context.Context, prior tfsdk.State, values frameworkValues) *frameworkData {
+	return &frameworkData{ctx: ctx, prior: prior, values: values}
+}
+
+// withConfig sets the configuration that write-only attributes are read from, returning d.
+func (d *frameworkData) withConfig(config tfsdk.Config) *frameworkData {
+	d.config = &config
+
+	return d
+}
+
+// value returns the value of the attribute named key in values.
+func (d *frameworkData) value(values frameworkGetter, key string) attr.Value {
+	var value attr.Value
+	d.Diagnostics.Append(values.GetAttribute(d.ctx, path.Root(key), &value)...)
+
+	return value
+}
+
+// priorValue returns the prior value of the attribute named key, which is null for a new resource.
+func (d *frameworkData) priorValue(key string) attr.Value {
+	return d.value(&d.prior, key)
+}
+
+// currentValue returns the value of the attribute named key in values, or in config for write-only attributes, which
+// are always null in plans and state.
+func (d *frameworkData) currentValue(key string) attr.Value {
+	if d.config != nil {
+		attribute, diags := d.prior.Schema.AttributeAtPath(d.ctx, path.Root(key))
+		if !diags.HasError() && attribute.IsWriteOnly() {
+			return d.value(d.config, key)
+		}
+	}
+
+	return d.value(d.values, key)
+}
+
+// set sets the attribute named key to value, which may be an attr.Value or a Go value of the attribute's type.
+func (d *frameworkData) set(key string, value interface{}) error {
+	return diagnosticsError(d.values.SetAttribute(d.ctx, path.Root(key), value))
+}
+
+// Get returns the value of the attribute named key.
+func (d *frameworkData) Get(key string) interface{} {
+	return sdkValue(d.currentValue(key))
+}
+
+// GetChange returns the prior and current values of the attribute named key.
+func (d *frameworkData) GetChange(key string) (interface{}, interface{}) {
+	return sdkValue(d.priorValue(key)), d.Get(key)
+}
+
+// HasChange returns true if the attribute named key differs from its prior value, or isn't known yet. Write-only
+// attributes have no prior value, so they have changed whenever they're set.
+func (d *frameworkData) HasChange(key string) bool {
+	value := d.currentValue(key)
+	if !valueKnown(d.ctx, value) {
+		return true
+	}
+
+	return !reflect.DeepEqual(sdkValue(d.priorValue(key)), sdkValue(value))
+}
+
+// HasChanges returns true if any of the attributes named keys have changed.
+func (d *frameworkData) HasChanges(keys ...string) bool {
+	for _, key := range keys {
+		if d.HasChange(key) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// NewValueKnown returns true if the attribute named key is known, including all of its elements.
+func (d *frameworkData) NewValueKnown(key string) bool {
+	return valueKnown(d.ctx, d.currentValue(key))
+}
+
+// Set sets the attribute named key to value.
+func (d *frameworkData) Set(key string, value interface{}) error {
+	return d.set(key, value)
+}
+
+// SetNew sets the planned value of the attribute named key.
+func (d *frameworkData) SetNew(key string, value interface{}) error {
+	return d.set(key, value)
+}
+
+// SetNewComputed marks the attribute named key as unknown until the plan is applied.
+func (d *frameworkData) SetNewComputed(key string) error {
+	attrType := d.value(d.values, key).Type(d.ctx)
+
+	unknown, err := attrType.ValueFromTerraform(d.ctx, tftypes.NewValue(attrType.TerraformType(d.ctx), tftypes.UnknownValue))
+	if err != nil {
+		return fmt.Errorf("unable to mark %s as unknown: %s", key, err)
+	}
+
+	return d.set(key, unknown)
+}
+
+// Id returns the value of the id attribute.
+func (d *frameworkData) Id() string {
+	return d.Get(idKey).(string)
+}
+
+// SetId sets the value of the id attribute. Errors are collected in Diagnostics.
+func (d *frameworkData) SetId(id string) {
+	d.Diagnostics.Append(d.values.SetAttribute(d.ctx, path.Root(idKey), id)...)
+}
+
+// copyPrior sets the attributes named keys to their prior values, if there are any. It gives computed attributes of a
+// planned update the values schema.ResourceDiff starts from, rather than the framework's unknown values.
+func (d *frameworkData) copyPrior(keys ...string) {
+	if d.prior.Raw.IsNull() {
+		return
+	}
+
+	for _, key := range keys {
+		if err := d.set(key, d.priorValue(key)); err != nil {
+			d.Diagnostics.AddAttributeError(path.Root(key), "Unable to plan attribute", err.Error())
+		}
+	}
+}
+
+// sdkValue converts a framework value to the value schema.ResourceData would return for it.
+func sdkValue(value attr.Value) interface{} {
+	switch value := value.(type) {
+	case types.String:
+		return value.ValueString()
+	case types.Bool:
+		return value.ValueBool()
+	case types.Int64:
+		return int(value.ValueInt64())
+	case types.List:
+		values := []interface{}{}
+		for _, element := range value.Elements() {
+			values = append(values, sdkValue(element))
+		}
+		return values
+	case types.Map:
+		values := map[string]interface{}{}
+		for key, element := range value.Elements() {
+			values[key] = sdkValue(element)
+		}
+		return values
+	}
+
+	return nil
+}
+
+// valueKnown returns true if value and all of its elements are known.
+func valueKnown(ctx context.Context, value attr.Value) bool {
+	if value == nil {
+		return true
+	}
+
+	terraformValue, err := value.ToTerraformValue(ctx)
+
+	return err == nil && terraformValue.IsFullyKnown()
+}
+
+// diagnosticsError returns an error describing the errors in diags, or nil if there are none.
+func diagnosticsError(diags diag.Diagnostics) error {
+	if !diags.HasError() {
+		return nil
+	}
+
+	messages := []string{}
+	for _, diagnostic := range diags.Errors() {
+		messages = append(messages, fmt.Sprintf("%s: %s", diagnostic.Summary(), diagnostic.Detail()))
+	}
+
+	return fmt.Errorf("%s", strings.Join(messages, "; "))
+}
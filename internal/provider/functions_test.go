@@ -0,0 +1,136 @@
+// Copyright 2021 Splunk, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// runFunction runs a function returning a string with args.
+func runFunction(f function.Function, args ...attr.Value) (string, *function.FuncError) {
+	resp := &function.RunResponse{Result: function.NewResultData(types.StringUnknown())}
+	f.Run(context.Background(), function.RunRequest{Arguments: function.NewArgumentsData(args)}, resp)
+
+	result, _ := resp.Result.Value().(types.String)
+
+	return result.ValueString(), resp.Error
+}
+
+func TestFunctions(t *testing.T) {
+	elements := func(values ...string) attr.Value {
+		elementTypes := make([]attr.Type, 0, len(values))
+		elementValues := make([]attr.Value, 0, len(values))
+		for _, value := range values {
+			elementTypes = append(elementTypes, types.StringType)
+			elementValues = append(elementValues, types.StringValue(value))
+		}
+		return types.TupleValueMust(elementTypes, elementValues)
+	}
+
+	tests := []struct {
+		name      string
+		function  function.Function
+		args      []attr.Value
+		want      string
+		wantError bool
+	}{
+		{
+			name:     "path_join",
+			function: newPathJoinFunction(),
+			args:     []attr.Value{elements("/repo/", "", "dir/file.txt")},
+			want:     "repo/dir/file.txt",
+		},
+		{
+			name:      "path_join escaping",
+			function:  newPathJoinFunction(),
+			args:      []attr.Value{elements("repo", "../other")},
+			wantError: true,
+		},
+		{
+			name:     "download_url",
+			function: newDownloadURLFunction(),
+			args:     []attr.Value{types.StringValue("https://example.com/artifactory/"), types.StringValue("/repo/file.txt")},
+			want:     "https://example.com/artifactory/repo/file.txt",
+		},
+		{
+			name:      "download_url relative url",
+			function:  newDownloadURLFunction(),
+			args:      []attr.Value{types.StringValue("artifactory"), types.StringValue("repo/file.txt")},
+			wantError: true,
+		},
+		{
+			name:      "download_url empty path",
+			function:  newDownloadURLFunction(),
+			args:      []attr.Value{types.StringValue("https://example.com/artifactory"), types.StringValue("/")},
+			wantError: true,
+		},
+		{
+			name:     "maven_path",
+			function: newMavenPathFunction(),
+			args: []attr.Value{types.StringValue("com.example"), types.StringValue("app"), types.StringValue("1.0.0"),
+				types.StringValue("sources"), types.StringValue("jar")},
+			want: "com/example/app/1.0.0/app-1.0.0-sources.jar",
+		},
+		{
+			name:     "maven_path without classifier",
+			function: newMavenPathFunction(),
+			args: []attr.Value{types.StringValue("com.example"), types.StringValue("app"), types.StringValue("1.0-SNAPSHOT"),
+				types.StringNull(), types.StringValue("pom")},
+			want: "com/example/app/1.0-SNAPSHOT/app-1.0-SNAPSHOT.pom",
+		},
+		{
+			name:     "maven_path invalid",
+			function: newMavenPathFunction(),
+			args: []attr.Value{types.StringValue("com/example"), types.StringValue("app"), types.StringValue("1.0.0"),
+				types.StringValue(""), types.StringValue("jar")},
+			wantError: true,
+		},
+		{
+			name:     "file_sha256",
+			function: newFileSHA256Function(),
+			args:     []attr.Value{types.StringValue("test_files/source_file.txt")},
+			want:     "8376f260fca20effdf3c7b66f2f56d7f118b532e6712af09a4f8922ccab58c5c",
+		},
+		{
+			name:      "file_sha256 missing",
+			function:  newFileSHA256Function(),
+			args:      []attr.Value{types.StringValue("test_files/missing.txt")},
+			wantError: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := runFunction(test.function, test.args...)
+			if test.wantError {
+				if err == nil {
+					t.Fatalf("got %q, want error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("got error: %s", err)
+			}
+			if got != test.want {
+				t.Errorf("got %q, want %q", got, test.want)
+			}
+		})
+	}
+}
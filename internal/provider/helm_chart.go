@@ -0,0 +1,415 @@
+// Copyright 2021 Splunk, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	"terraform-provider-artifacts/internal/provider/internal/archive"
+	"terraform-provider-artifacts/internal/provider/internal/client"
+	"terraform-provider-artifacts/internal/provider/internal/pkginfo"
+)
+
+func resourceHelmChart() *schema.Resource {
+	return &schema.Resource{
+		Description:   "Upload a Helm chart to a Helm repository in Artifactory, named from its Chart.yaml. Chart directories are packaged deterministically.",
+		CreateContext: resourceHelmChartCreate,
+		ReadContext:   resourceHelmChartRead,
+		UpdateContext: resourceHelmChartUpdate,
+		DeleteContext: resourceHelmChartDelete,
+		CustomizeDiff: resourceHelmChartDiff,
+		Schema: map[string]*schema.Schema{
+			repositoryKey: {
+				Description: fmt.Sprintf("Key of the Helm repository to upload to. The provider's `%s` isn't applied, as chart paths are relative to the repository.", basePathKey),
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+			},
+			helmChartKey: {
+				Description: "Chart directory, or packaged chart file, to upload. Directories are packaged with the patterns in their `.helmignore` excluded.",
+				Type:        schema.TypeString,
+				Required:    true,
+			},
+			helmDirectoryKey: {
+				Description: "Directory within the repository to upload the chart to. Defaults to the repository's root.",
+				Type:        schema.TypeString,
+				Optional:    true,
+			},
+			reindexKey: {
+				Description: "Set to true to reindex the repository after uploading or deleting the chart, for repositories that don't reindex automatically. Defaults to false.",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+			},
+			deleteOldPath: {
+				Description: "Set to false if the uploaded chart should be orphaned on destruction of the resource or change of its path. Defaults to true.",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+			},
+			triggersKey: {
+				Description: "Arbitrary map of values that, when changed, will trigger re-uploading of this resource's chart.",
+				Type:        schema.TypeMap,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			helmChartNameKey: {
+				Description: "Name of the chart, from its Chart.yaml",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			versionKey: {
+				Description: "Version of the chart, from its Chart.yaml",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			appVersionKey: {
+				Description: "Version of the app the chart deploys, from the `appVersion` of its Chart.yaml",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			sourceHashKey: {
+				Description: "SHA256 of the chart directory's entries and their contents, or of the packaged chart file, which is computed when planning without packaging the chart. It changes, causing the chart to be uploaded again, only when the chart's contents change.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			digestKey: {
+				Description: "SHA256 of the packaged chart as it was uploaded, as listed in the repository's index. The chart is uploaded again if the uploaded file's `sha256` no longer matches it.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			fullPathKey: {
+				Description: "Path the chart is uploaded to, relative to the provider's URL",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			sha1Key: {
+				Description: "SHA1 of the uploaded chart",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			sha256Key: {
+				Description: "SHA256 of the uploaded chart",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			md5Key: {
+				Description: "MD5 of the uploaded chart",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+		},
+	}
+}
+
+// helmChart is a Helm chart to upload, and where to upload it.
+type helmChart struct {
+	chart      pkginfo.HelmChart
+	fullPath   string
+	sourceHash string
+}
+
+// readHelmChart reads the Chart.yaml of the chart in d, determines its path in the repository, and hashes its
+// contents without packaging it.
+func readHelmChart(d resourceGetter) (helmChart, error) {
+	chartPath := d.Get(helmChartKey).(string)
+	chart, err := pkginfo.ReadHelmChart(chartPath)
+	if err != nil {
+		return helmChart{}, err
+	}
+
+	fullPath, err := client.JoinPath(d.Get(repositoryKey).(string), d.Get(helmDirectoryKey).(string), chart.FileName())
+	if err != nil {
+		return helmChart{}, err
+	}
+
+	sourceHash, err := hashHelmChart(chartPath, chart)
+	if err != nil {
+		return helmChart{}, err
+	}
+
+	return helmChart{chart: chart, fullPath: fullPath, sourceHash: sourceHash}, nil
+}
+
+// hashHelmChart returns the SHA256 of the entries that would be packaged from the chart directory at chartPath, or of
+// the packaged chart file at chartPath.
+func hashHelmChart(chartPath string, chart pkginfo.HelmChart) (string, error) {
+	info, err := os.Stat(chartPath)
+	if err != nil {
+		return "", fmt.Errorf("unable to read chart %s: %s", chartPath, err)
+	}
+
+	if !info.IsDir() {
+		sourceHash, err := client.Client{}.FileChecksum(chartPath, client.ChecksumSHA256)
+		if err != nil {
+			return "", fmt.Errorf("unable to read chart %s: %s", chartPath, err)
+		}
+		return sourceHash, nil
+	}
+
+	options, err := helmChartOptions(chartPath, chart)
+	if err != nil {
+		return "", err
+	}
+
+	sourceHash, err := archive.Hash(chartPath, options)
+	if err != nil {
+		return "", fmt.Errorf("unable to hash contents of chart %s: %s", chartPath, err)
+	}
+
+	return sourceHash, nil
+}
+
+// helmChartOptions returns the archive.Options of the package of the chart directory at chartPath.
+func helmChartOptions(chartPath string, chart pkginfo.HelmChart) (archive.Options, error) {
+	excludes, excludeNames, err := pkginfo.HelmIgnorePatterns(chartPath)
+	if err != nil {
+		return archive.Options{}, err
+	}
+
+	// packaged charts have their files in a directory named after the chart
+	return archive.Options{
+		Format:       archive.TarGzip,
+		Excludes:     excludes,
+		ExcludeNames: excludeNames,
+		Prefix:       chart.Name,
+	}, nil
+}
+
+// writeHelmChart writes the package of the chart at chartPath to w, packaging it if it's a directory, returning its
+// SHA256.
+func writeHelmChart(w io.Writer, chartPath string, chart pkginfo.HelmChart) (string, error) {
+	info, err := os.Stat(chartPath)
+	if err != nil {
+		return "", fmt.Errorf("unable to read chart %s: %s", chartPath, err)
+	}
+
+	hash := sha256.New()
+	w = io.MultiWriter(w, hash)
+
+	if !info.IsDir() {
+		file, err := os.Open(chartPath)
+		if err != nil {
+			return "", fmt.Errorf("unable to read chart %s: %s", chartPath, err)
+		}
+		defer file.Close()
+
+		if _, err := io.Copy(w, file); err != nil {
+			return "", fmt.Errorf("unable to read chart %s: %s", chartPath, err)
+		}
+		return fmt.Sprintf("%x", hash.Sum(nil)), nil
+	}
+
+	options, err := helmChartOptions(chartPath, chart)
+	if err != nil {
+		return "", err
+	}
+	if err := archive.Write(w, chartPath, options); err != nil {
+		return "", fmt.Errorf("unable to package chart %s: %s", chartPath, err)
+	}
+
+	return fmt.Sprintf("%x", hash.Sum(nil)), nil
+}
+
+// reindexHelmRepository reindexes the repository in d, if configured to.
+func reindexHelmRepository(ctx context.Context, d resourceGetter, c *client.Client) error {
+	if !d.Get(reindexKey).(bool) {
+		return nil
+	}
+
+	return c.ReindexHelmRepository(ctx, d.Get(repositoryKey).(string))
+}
+
+func resourceHelmChartCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*client.Client)
+
+	pkg, err := readHelmChart(d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	file, err := os.CreateTemp("", "terraform-provider-artifacts-*.tgz")
+	if err != nil {
+		return diag.Errorf("unable to create temporary chart file: %s", err)
+	}
+	defer os.Remove(file.Name())
+
+	digest, err := writeHelmChart(file, d.Get(helmChartKey).(string), pkg.chart)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err := client.Upload(ctx, pkg.fullPath, file.Name(), nil); err != nil {
+		return diag.Errorf("failure uploading chart %s: %s", d.Get(helmChartKey).(string), err)
+	}
+
+	d.SetId(pkg.fullPath)
+	if err := setHelmChart(d, pkg); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set(digestKey, digest); err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err := reindexHelmRepository(ctx, d, client); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return resourceHelmChartRead(ctx, d, meta)
+}
+
+func resourceHelmChartRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*client.Client)
+
+	checksums, err := client.Checksums(ctx, d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if checksums.Get(client.ChecksumType) == "" {
+		d.SetId("")
+		return nil
+	}
+
+	if err := setChecksums(d, checksums); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return nil
+}
+
+// resourceHelmChartUpdate uploads the chart again if it or its path changed. Other changes only affect later
+// operations.
+func resourceHelmChartUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*client.Client)
+
+	pkg, err := readHelmChart(d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if pkg.fullPath != d.Id() {
+		if d.Get(deleteOldPath).(bool) {
+			if err := deleteExisting(ctx, client, []string{d.Id()}); err != nil {
+				return diag.FromErr(err)
+			}
+		}
+
+		return resourceHelmChartCreate(ctx, d, meta)
+	}
+
+	if d.HasChanges(helmChartKey, triggersKey, sourceHashKey, sha1Key, sha256Key, md5Key) {
+		return resourceHelmChartCreate(ctx, d, meta)
+	}
+
+	return resourceHelmChartRead(ctx, d, meta)
+}
+
+func resourceHelmChartDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*client.Client)
+
+	if d.Get(deleteOldPath).(bool) {
+		if err := deleteExisting(ctx, client, []string{d.Id()}); err != nil {
+			return diag.FromErr(err)
+		}
+
+		if err := reindexHelmRepository(ctx, d, client); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	return nil
+}
+
+func resourceHelmChartDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if d.HasChanges(helmChartKey, triggersKey) {
+		if err := setNewComputedHelmChecksums(d); err != nil {
+			return err
+		}
+	}
+
+	chartKeys := []string{helmChartNameKey, versionKey, appVersionKey, sourceHashKey, digestKey, fullPathKey}
+	if known, err := diffPackageFile(d, helmChartKey, []string{repositoryKey, helmDirectoryKey}, chartKeys); !known || err != nil {
+		return err
+	}
+
+	// the chart's contents are hashed without packaging it, which is only done when uploading it
+
+	pkg, err := readHelmChart(d)
+	if err != nil {
+		return err
+	}
+
+	for key, value := range helmChartAttributes(pkg) {
+		if value != d.Get(key).(string) {
+			if err := d.SetNew(key, value); err != nil {
+				return err
+			}
+		}
+	}
+
+	if d.Id() == "" {
+		return nil
+	}
+
+	// the uploaded file's SHA256 differing from the digest of the uploaded package means it was changed remotely
+	if pkg.fullPath != d.Id() || d.HasChange(sourceHashKey) || d.Get(sha256Key).(string) != d.Get(digestKey).(string) {
+		return setNewComputedHelmChecksums(d)
+	}
+
+	return nil
+}
+
+// setNewComputedHelmChecksums marks the digest of the packaged chart and the checksums of the uploaded file as
+// "known after apply".
+func setNewComputedHelmChecksums(d *schema.ResourceDiff) error {
+	if err := d.SetNewComputed(digestKey); err != nil {
+		return err
+	}
+
+	return setNewComputedChecksums(d)
+}
+
+// helmChartAttributes returns the attributes read from a chart's Chart.yaml and contents.
+func helmChartAttributes(pkg helmChart) map[string]string {
+	return map[string]string{
+		helmChartNameKey: pkg.chart.Name,
+		versionKey:       pkg.chart.Version,
+		appVersionKey:    pkg.chart.AppVersion,
+		sourceHashKey:    pkg.sourceHash,
+		fullPathKey:      pkg.fullPath,
+	}
+}
+
+// setHelmChart sets the attributes read from a chart's Chart.yaml and contents.
+func setHelmChart(d *schema.ResourceData, pkg helmChart) error {
+	for key, value := range helmChartAttributes(pkg) {
+		if err := d.Set(key, value); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,118 @@
+// Copyright 2021 Splunk, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+
+	"terraform-provider-artifacts/internal/provider/internal/pkginfo"
+)
+
+func TestAccResourceHelmChart(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV5ProviderFactories: protoV5ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testResourceHelmChartConfig,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("artifacts_helm_chart.test", "full_path", "sas-helm/terraform-provider-artifacts-test/terraform-provider-artifacts-test-1.0.0.tgz"),
+					resource.TestCheckResourceAttr("artifacts_helm_chart.test", "name", "terraform-provider-artifacts-test"),
+					resource.TestCheckResourceAttr("artifacts_helm_chart.test", "version", "1.0.0"),
+					resource.TestCheckResourceAttr("artifacts_helm_chart.test", "app_version", "1.0.0"),
+					resource.TestCheckResourceAttrPair("artifacts_helm_chart.test", "digest", "artifacts_helm_chart.test", "sha256"),
+					resource.TestCheckResourceAttrSet("artifacts_helm_chart.test", "source_hash"),
+				),
+			},
+		},
+	})
+}
+
+func TestHashHelmChart(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{".helmignore", "Chart.yaml", "values.yaml", "templates/configmap.yaml"} {
+		content, err := os.ReadFile(filepath.Join("test_files/helm_chart", name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.MkdirAll(filepath.Dir(filepath.Join(dir, name)), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, name), content, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	chart, err := pkginfo.ReadHelmChart(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hash := func() string {
+		t.Helper()
+		sourceHash, err := hashHelmChart(dir, chart)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return sourceHash
+	}
+
+	original := hash()
+
+	// files left out by .helmignore don't change the hash
+	if err := os.WriteFile(filepath.Join(dir, "notes.tmp"), []byte("scratch"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if got := hash(); got != original {
+		t.Errorf("expected an ignored file to leave the hash unchanged, got %s, want %s", got, original)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "values.yaml"), []byte("replicas: 2\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if got := hash(); got == original {
+		t.Error("expected a changed file to change the hash")
+	}
+
+	// a packaged chart is hashed as it's uploaded, so its hash is its digest
+	packaged := filepath.Join(t.TempDir(), chart.FileName())
+	file, err := os.Create(packaged)
+	if err != nil {
+		t.Fatal(err)
+	}
+	digest, err := writeHelmChart(file, dir, chart)
+	file.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, err := hashHelmChart(packaged, chart); err != nil || got != digest {
+		t.Errorf("hashHelmChart(%s) = %s, %v, want %s", packaged, got, err, digest)
+	}
+}
+
+const testResourceHelmChartConfig = `
+provider "artifacts" {
+  url = "https://repo.splunk.com/artifactory"
+}
+
+resource "artifacts_helm_chart" "test" {
+  repository = "sas-helm"
+  directory  = "terraform-provider-artifacts-test"
+  chart      = "test_files/helm_chart"
+  reindex    = true
+}
+`
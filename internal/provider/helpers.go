@@ -0,0 +1,94 @@
+// Copyright 2021 Splunk, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"terraform-provider-artifacts/internal/provider/internal/client"
+)
+
+// resourceGetter is implemented by both schema.ResourceData and schema.ResourceDiff, allowing functions that only read
+// attributes to be shared between CRUD functions and CustomizeDiff.
+type resourceGetter interface {
+	Get(key string) interface{}
+}
+
+// resourceData is implemented by schema.ResourceData and frameworkData, allowing CRUD logic to be shared between SDKv2
+// resources and those ported to the plugin framework.
+type resourceData interface {
+	resourceGetter
+	Set(key string, value interface{}) error
+	GetChange(key string) (interface{}, interface{})
+	HasChanges(keys ...string) bool
+	Id() string
+	SetId(id string)
+}
+
+// resourceDiffer is implemented by schema.ResourceDiff and frameworkData, allowing plan logic to be shared between
+// SDKv2 resources and those ported to the plugin framework.
+type resourceDiffer interface {
+	resourceGetter
+	HasChange(key string) bool
+	HasChanges(keys ...string) bool
+	NewValueKnown(key string) bool
+	SetNew(key string, value interface{}) error
+	SetNewComputed(key string) error
+	Id() string
+}
+
+// stringMap converts a schema.TypeMap value with string elements to a map[string]string.
+func stringMap(value interface{}) map[string]string {
+	values, _ := value.(map[string]interface{})
+
+	result := make(map[string]string, len(values))
+	for key, value := range values {
+		result[key], _ = value.(string)
+	}
+
+	return result
+}
+
+// stringList converts a schema.TypeList value with string elements to a []string.
+func stringList(value interface{}) []string {
+	values, _ := value.([]interface{})
+
+	result := make([]string, 0, len(values))
+	for _, value := range values {
+		s, _ := value.(string)
+		result = append(result, s)
+	}
+
+	return result
+}
+
+// deleteExisting deletes the files at paths, skipping any that have already been deleted.
+func deleteExisting(ctx context.Context, c *client.Client, paths []string) error {
+	for _, path := range paths {
+		err := c.Delete(ctx, path)
+
+		var statusErr *client.StatusError
+		if errors.As(err, &statusErr) && statusErr.NotFound() {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failure deleting %s: %s", path, err)
+		}
+	}
+
+	return nil
+}
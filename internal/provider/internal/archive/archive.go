@@ -0,0 +1,361 @@
+// Copyright 2021 Splunk, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package archive builds deterministic archives of directories, so that identical directory contents always give
+// identical archive bytes, regardless of file timestamps, ownership, or the order entries are found in.
+package archive
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// Format is an archive format.
+type Format string
+
+const (
+	Zip     Format = "zip"
+	TarGzip Format = "tar.gz"
+	TarZstd Format = "tar.zst"
+)
+
+// Formats are the supported archive formats.
+var Formats = []Format{Zip, TarGzip, TarZstd}
+
+// formatExtensions are the file extensions that imply each Format.
+var formatExtensions = map[string]Format{
+	".zip":     Zip,
+	".tar.gz":  TarGzip,
+	".tgz":     TarGzip,
+	".tar.zst": TarZstd,
+	".tzst":    TarZstd,
+}
+
+// ModTime is the modification time of every entry. It is the earliest time zip archives can represent.
+var ModTime = time.Date(1980, 1, 1, 0, 0, 0, 0, time.UTC)
+
+const (
+	dirMode        fs.FileMode = 0755
+	fileMode       fs.FileMode = 0644
+	executableMode fs.FileMode = 0755
+)
+
+// FormatForPath returns the Format implied by the extension of filename. ok is false if no Format is.
+func FormatForPath(filename string) (format Format, ok bool) {
+	for extension, format := range formatExtensions {
+		if strings.HasSuffix(strings.ToLower(filename), extension) {
+			return format, true
+		}
+	}
+
+	return "", false
+}
+
+// Options control the contents of an archive.
+type Options struct {
+	Format Format
+	// Excludes are patterns, in the syntax of path.Match, matched against the slash-separated path of each entry
+	// relative to the directory. Excluded directories are excluded along with their contents.
+	Excludes []string
+	// ExcludeNames are patterns, in the syntax of path.Match, matched against the base name of each entry at any depth.
+	ExcludeNames []string
+	// Prefix is a directory that every entry is archived within, such as the name of the archived directory.
+	Prefix string
+}
+
+// entry is a file, directory, or symbolic link to be archived.
+type entry struct {
+	// name is the slash-separated path relative to the archived directory, with a trailing slash for directories.
+	name     string
+	filename string
+	mode     fs.FileMode
+	size     int64
+	// linkTarget is the target of a symbolic link.
+	linkTarget string
+}
+
+// Write writes an archive of the contents of dir to w. Entries are sorted by path, have ModTime as their modification
+// time and no owner, and have permissions normalized to 0755 for directories and executable files, and 0644 for
+// other files.
+//
+// The archive's bytes, and so its checksums, also depend on the compressors of the Go and klauspost/compress versions
+// it is built with, which may change between builds of the provider.
+func Write(w io.Writer, dir string, options Options) error {
+	entries, err := archiveEntries(dir, options)
+	if err != nil {
+		return err
+	}
+
+	switch options.Format {
+	case Zip:
+		return writeZip(w, entries)
+	case TarGzip:
+		gzipWriter, err := gzip.NewWriterLevel(w, gzip.BestCompression)
+		if err != nil {
+			return err
+		}
+		if err := writeTar(gzipWriter, entries); err != nil {
+			return err
+		}
+		return gzipWriter.Close()
+	case TarZstd:
+		// a single goroutine keeps the encoded frames independent of scheduling
+		zstdWriter, err := zstd.NewWriter(w, zstd.WithEncoderConcurrency(1))
+		if err != nil {
+			return err
+		}
+		if err := writeTar(zstdWriter, entries); err != nil {
+			zstdWriter.Close()
+			return err
+		}
+		return zstdWriter.Close()
+	}
+
+	return fmt.Errorf("unsupported archive format %q", options.Format)
+}
+
+// Hash returns the SHA256 of the entries that Write would archive from dir, and their contents, without building or
+// compressing the archive. It changes whenever the archive's contents do, but unlike the archive's own checksum, it
+// doesn't depend on the compressor's version.
+func Hash(dir string, options Options) (string, error) {
+	entries, err := archiveEntries(dir, options)
+	if err != nil {
+		return "", err
+	}
+
+	hash := sha256.New()
+	fmt.Fprintf(hash, "%s\x00", options.Format)
+	for _, entry := range entries {
+		fmt.Fprintf(hash, "%s\x00%o\x00%d\x00", entry.name, entry.mode, entry.size)
+		if err := writeContent(hash, entry); err != nil {
+			return "", err
+		}
+		hash.Write([]byte{0})
+	}
+
+	return fmt.Sprintf("%x", hash.Sum(nil)), nil
+}
+
+// archiveEntries returns the entries to archive from dir, sorted by name, within options.Prefix if it is set.
+func archiveEntries(dir string, options Options) ([]entry, error) {
+	entries, err := listEntries(dir, options.Excludes, options.ExcludeNames)
+	if err != nil {
+		return nil, err
+	}
+
+	if options.Prefix == "" {
+		return entries, nil
+	}
+
+	prefix := strings.Trim(path.Clean(filepath.ToSlash(options.Prefix)), "/")
+	if prefix == "." || prefix == ".." || strings.HasPrefix(prefix, "../") {
+		return nil, fmt.Errorf("invalid prefix %q", options.Prefix)
+	}
+
+	prefixed := make([]entry, 0, len(entries)+1)
+	prefixed = append(prefixed, entry{name: prefix + "/", mode: dirMode | fs.ModeDir})
+	for _, entry := range entries {
+		entry.name = prefix + "/" + entry.name
+		prefixed = append(prefixed, entry)
+	}
+
+	return prefixed, nil
+}
+
+// listEntries returns the entries to archive from dir, sorted by name.
+func listEntries(dir string, excludes []string, excludeNames []string) ([]entry, error) {
+	for _, pattern := range append(append([]string{}, excludes...), excludeNames...) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid exclude pattern %q: %s", pattern, err)
+		}
+	}
+
+	entries := []entry{}
+
+	// WalkDir visits entries in lexical order, so the entries are already sorted
+	err := filepath.WalkDir(dir, func(filename string, dirEntry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		relative, err := filepath.Rel(dir, filename)
+		if err != nil {
+			return err
+		}
+		if relative == "." {
+			return nil
+		}
+		name := filepath.ToSlash(relative)
+
+		if excluded(name, excludes, excludeNames) {
+			if dirEntry.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		info, err := dirEntry.Info()
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case info.Mode().IsDir():
+			entries = append(entries, entry{name: name + "/", filename: filename, mode: dirMode | fs.ModeDir})
+		case info.Mode().IsRegular():
+			mode := fileMode
+			if info.Mode().Perm()&0111 != 0 {
+				mode = executableMode
+			}
+			entries = append(entries, entry{name: name, filename: filename, mode: mode, size: info.Size()})
+		case info.Mode()&fs.ModeSymlink != 0:
+			target, err := os.Readlink(filename)
+			if err != nil {
+				return err
+			}
+			entries = append(entries, entry{name: name, filename: filename, mode: 0777 | fs.ModeSymlink, linkTarget: filepath.ToSlash(target)})
+		default:
+			return fmt.Errorf("unable to archive %s, which is neither a file, directory, nor symbolic link", filename)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to list files in %s: %s", dir, err)
+	}
+
+	return entries, nil
+}
+
+// excluded returns whether the entry with the slash-separated path name matches any of excludes, or its base name
+// matches any of excludeNames.
+func excluded(name string, excludes []string, excludeNames []string) bool {
+	for _, pattern := range excludes {
+		if matched, _ := path.Match(pattern, name); matched {
+			return true
+		}
+	}
+
+	for _, pattern := range excludeNames {
+		if matched, _ := path.Match(pattern, path.Base(name)); matched {
+			return true
+		}
+	}
+
+	return false
+}
+
+// writeZip writes entries as a zip archive.
+func writeZip(w io.Writer, entries []entry) error {
+	zipWriter := zip.NewWriter(w)
+
+	for _, entry := range entries {
+		header := &zip.FileHeader{
+			Name:     entry.name,
+			Method:   zip.Deflate,
+			Modified: ModTime,
+		}
+		header.SetMode(entry.mode)
+		if entry.mode.IsDir() {
+			header.Method = zip.Store
+		}
+
+		writer, err := zipWriter.CreateHeader(header)
+		if err != nil {
+			return err
+		}
+
+		if err := writeContent(writer, entry); err != nil {
+			return err
+		}
+	}
+
+	return zipWriter.Close()
+}
+
+// writeTar writes entries as a tar archive.
+func writeTar(w io.Writer, entries []entry) error {
+	tarWriter := tar.NewWriter(w)
+
+	for _, entry := range entries {
+		header := &tar.Header{
+			Name:    entry.name,
+			Mode:    int64(entry.mode.Perm()),
+			ModTime: ModTime,
+		}
+
+		switch {
+		case entry.mode.IsDir():
+			header.Typeflag = tar.TypeDir
+		case entry.mode&fs.ModeSymlink != 0:
+			header.Typeflag = tar.TypeSymlink
+			header.Linkname = entry.linkTarget
+		default:
+			header.Typeflag = tar.TypeReg
+			header.Size = entry.size
+		}
+
+		if err := tarWriter.WriteHeader(header); err != nil {
+			return err
+		}
+
+		if header.Typeflag == tar.TypeReg {
+			if err := writeContent(tarWriter, entry); err != nil {
+				return err
+			}
+		}
+	}
+
+	return tarWriter.Close()
+}
+
+// writeContent writes the content of an entry: a file's data, or a symbolic link's target.
+func writeContent(w io.Writer, entry entry) error {
+	switch {
+	case entry.mode.IsDir():
+		return nil
+	case entry.mode&fs.ModeSymlink != 0:
+		_, err := io.WriteString(w, entry.linkTarget)
+		return err
+	}
+
+	file, err := os.Open(entry.filename)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	// a file that changed size since it was listed would otherwise corrupt a tar archive
+	written, err := io.Copy(w, io.LimitReader(file, entry.size))
+	if err != nil {
+		return fmt.Errorf("unable to read %s: %s", entry.filename, err)
+	}
+	if written != entry.size {
+		return fmt.Errorf("%s changed while it was being archived", entry.filename)
+	}
+
+	return nil
+}
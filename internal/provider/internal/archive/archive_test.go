@@ -0,0 +1,238 @@
+// Copyright 2021 Splunk, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package archive
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// writeTree creates files in a new directory, with the given modification time and permission for files.
+func writeTree(t *testing.T, modTime time.Time, perm os.FileMode) string {
+	dir := t.TempDir()
+
+	files := map[string]string{
+		"b.txt":            "b",
+		"a/z.txt":          "z",
+		"a/bin/tool":       "#!/bin/sh\n",
+		"excluded/ignored": "ignored",
+		"a/skip.tmp":       "skipped",
+	}
+	for name, content := range files {
+		filename := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(filename), 0700); err != nil {
+			t.Fatal(err)
+		}
+		filePerm := perm
+		if name == "a/bin/tool" {
+			filePerm |= 0100
+		}
+		if err := os.WriteFile(filename, []byte(content), filePerm); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(filename, modTime, modTime); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return dir
+}
+
+func TestWriteIsDeterministic(t *testing.T) {
+	first := writeTree(t, time.Now(), 0600)
+	second := writeTree(t, time.Now().Add(-time.Hour), 0640)
+
+	for _, format := range Formats {
+		t.Run(string(format), func(t *testing.T) {
+			options := Options{Format: format, Excludes: []string{"excluded", "*/*.tmp"}}
+
+			firstArchive := &bytes.Buffer{}
+			if err := Write(firstArchive, first, options); err != nil {
+				t.Fatal(err)
+			}
+
+			secondArchive := &bytes.Buffer{}
+			if err := Write(secondArchive, second, options); err != nil {
+				t.Fatal(err)
+			}
+
+			if !bytes.Equal(firstArchive.Bytes(), secondArchive.Bytes()) {
+				t.Error("archives of identical contents differ")
+			}
+		})
+	}
+}
+
+func TestWriteTarGzip(t *testing.T) {
+	dir := writeTree(t, time.Now(), 0600)
+
+	archive := &bytes.Buffer{}
+	if err := Write(archive, dir, Options{Format: TarGzip, Excludes: []string{"excluded", "*/*.tmp"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	gzipReader, err := gzip.NewReader(archive)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type tarEntry struct {
+		name string
+		mode int64
+	}
+	entries := []tarEntry{}
+	tarReader := tar.NewReader(gzipReader)
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !header.ModTime.Equal(ModTime) {
+			t.Errorf("%s has modification time %s", header.Name, header.ModTime)
+		}
+		entries = append(entries, tarEntry{name: header.Name, mode: header.Mode})
+	}
+
+	expected := []tarEntry{
+		{name: "a/", mode: 0755},
+		{name: "a/bin/", mode: 0755},
+		{name: "a/bin/tool", mode: 0755},
+		{name: "a/z.txt", mode: 0644},
+		{name: "b.txt", mode: 0644},
+	}
+	if !reflect.DeepEqual(entries, expected) {
+		t.Errorf("got entries %v, want %v", entries, expected)
+	}
+}
+
+func TestWriteZip(t *testing.T) {
+	dir := writeTree(t, time.Now(), 0600)
+
+	archive := &bytes.Buffer{}
+	if err := Write(archive, dir, Options{Format: Zip, Excludes: []string{"excluded", "*/*.tmp"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	zipReader, err := zip.NewReader(bytes.NewReader(archive.Bytes()), int64(archive.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	names := []string{}
+	for _, file := range zipReader.File {
+		names = append(names, file.Name)
+	}
+
+	expected := []string{"a/", "a/bin/", "a/bin/tool", "a/z.txt", "b.txt"}
+	if !reflect.DeepEqual(names, expected) {
+		t.Errorf("got entries %v, want %v", names, expected)
+	}
+}
+
+func TestWritePrefixAndExcludeNames(t *testing.T) {
+	dir := writeTree(t, time.Now(), 0600)
+
+	archive := &bytes.Buffer{}
+	if err := Write(archive, dir, Options{Format: Zip, ExcludeNames: []string{"*.tmp", "bin", "excluded"}, Prefix: "chart"}); err != nil {
+		t.Fatal(err)
+	}
+
+	zipReader, err := zip.NewReader(bytes.NewReader(archive.Bytes()), int64(archive.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	names := []string{}
+	for _, file := range zipReader.File {
+		names = append(names, file.Name)
+	}
+
+	expected := []string{"chart/", "chart/a/", "chart/a/z.txt", "chart/b.txt"}
+	if !reflect.DeepEqual(names, expected) {
+		t.Errorf("got entries %v, want %v", names, expected)
+	}
+}
+
+func TestFormatForPath(t *testing.T) {
+	tests := map[string]Format{
+		"tool-1.0.0.zip":     Zip,
+		"tool-1.0.0.tar.gz":  TarGzip,
+		"tool-1.0.0.TGZ":     TarGzip,
+		"tool-1.0.0.tar.zst": TarZstd,
+	}
+
+	for filename, want := range tests {
+		if got, ok := FormatForPath(filename); !ok || got != want {
+			t.Errorf("FormatForPath(%q) = %q, %t, want %q", filename, got, ok, want)
+		}
+	}
+
+	if _, ok := FormatForPath("tool-1.0.0.tar"); ok {
+		t.Error("expected no format for .tar")
+	}
+}
+
+func TestHash(t *testing.T) {
+	options := Options{Format: TarGzip, Excludes: []string{"excluded", "*/*.tmp"}}
+
+	first := writeTree(t, time.Now(), 0600)
+	second := writeTree(t, time.Now().Add(-time.Hour), 0640)
+
+	firstHash, err := Hash(first, options)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	secondHash, err := Hash(second, options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if firstHash != secondHash {
+		t.Error("hashes of identical contents differ")
+	}
+
+	zipHash, err := Hash(first, Options{Format: Zip, Excludes: options.Excludes})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if zipHash == firstHash {
+		t.Error("expected hash to change with the format")
+	}
+
+	if err := os.WriteFile(filepath.Join(second, "excluded", "ignored"), []byte("changed"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if secondHash, err = Hash(second, options); err != nil || secondHash != firstHash {
+		t.Errorf("expected hash not to change with excluded content, got %s, %v", secondHash, err)
+	}
+
+	if err := os.WriteFile(filepath.Join(second, "b.txt"), []byte("changed"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if secondHash, err = Hash(second, options); err != nil || secondHash == firstHash {
+		t.Errorf("expected hash to change with content, got %s, %v", secondHash, err)
+	}
+}
@@ -0,0 +1,238 @@
+// Copyright 2021 Splunk, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+)
+
+const (
+	// BuildStartedLayout is the layout of the time a build started, in build info.
+	BuildStartedLayout = "2006-01-02T15:04:05.000-0700"
+
+	// buildInfoVersion is the version of the build info format.
+	buildInfoVersion = "1.0.1"
+)
+
+// BuildInfo describes a build and the artifacts it produced, for the builds API.
+type BuildInfo struct {
+	Version string        `json:"version"`
+	Name    string        `json:"name"`
+	Number  string        `json:"number"`
+	Started string        `json:"started"`
+	Agent   *BuildAgent   `json:"agent,omitempty"`
+	VCS     []BuildVCS    `json:"vcs,omitempty"`
+	Modules []BuildModule `json:"modules"`
+	// Properties include the environment variables captured by the build, with keys prefixed by "buildInfo.env.".
+	Properties map[string]string `json:"properties,omitempty"`
+}
+
+// BuildAgent is the tool that published build info.
+type BuildAgent struct {
+	Name    string `json:"name"`
+	Version string `json:"version,omitempty"`
+}
+
+// BuildVCS is the version control revision a build was built from.
+type BuildVCS struct {
+	Revision string `json:"revision"`
+	URL      string `json:"url,omitempty"`
+	Branch   string `json:"branch,omitempty"`
+}
+
+// BuildModule is a module of a build, such as a component or package, with the artifacts it produced.
+type BuildModule struct {
+	ID        string          `json:"id"`
+	Artifacts []BuildArtifact `json:"artifacts"`
+}
+
+// BuildArtifact is an artifact produced by a build.
+type BuildArtifact struct {
+	Name string `json:"name"`
+	Type string `json:"type,omitempty"`
+	// Path is the path of the artifact within OriginalDeploymentRepo.
+	Path                   string `json:"path"`
+	OriginalDeploymentRepo string `json:"originalDeploymentRepo"`
+	SHA1                   string `json:"sha1"`
+	SHA256                 string `json:"sha256,omitempty"`
+	MD5                    string `json:"md5,omitempty"`
+}
+
+// NewBuildArtifact returns a BuildArtifact for the file at a path relative to the client's URL, named and typed after
+// the file's name.
+func NewBuildArtifact(fullPath string, checksums Checksums) BuildArtifact {
+	repoKey, repoPath := splitRepoPath(fullPath)
+	name := path.Base(repoPath)
+
+	artifactType := path.Ext(name)
+	if len(artifactType) > 0 {
+		artifactType = artifactType[1:]
+	}
+
+	return BuildArtifact{
+		Name:                   name,
+		Type:                   artifactType,
+		Path:                   repoPath,
+		OriginalDeploymentRepo: repoKey,
+		SHA1:                   checksums.SHA1,
+		SHA256:                 checksums.SHA256,
+		MD5:                    checksums.MD5,
+	}
+}
+
+// PublishBuildInfo publishes build info, replacing any existing build with the same name and number.
+func (c Client) PublishBuildInfo(ctx context.Context, buildInfo BuildInfo) error {
+	if buildInfo.Version == "" {
+		buildInfo.Version = buildInfoVersion
+	}
+
+	if err := c.doAPI(ctx, http.MethodPut, c.URL+"/api/build", nil, buildInfo, nil); err != nil {
+		return fmt.Errorf("unable to publish build %s/%s: %s", buildInfo.Name, buildInfo.Number, err)
+	}
+
+	return nil
+}
+
+// BuildExists returns true if a build with name and number has been published.
+func (c Client) BuildExists(ctx context.Context, name string, number string) (bool, error) {
+	url := fmt.Sprintf("%s/api/build/%s/%s", c.URL, url.PathEscape(name), url.PathEscape(number))
+
+	if err := c.doAPI(ctx, http.MethodGet, url, nil, nil, nil); err != nil {
+		var statusErr *StatusError
+		if errors.As(err, &statusErr) && statusErr.NotFound() {
+			return false, nil
+		}
+
+		return false, fmt.Errorf("unable to read build %s/%s: %s", name, number, err)
+	}
+
+	return true, nil
+}
+
+// DeleteBuild deletes the build with name and number, leaving its artifacts in place. Deleting a build that doesn't
+// exist isn't an error.
+func (c Client) DeleteBuild(ctx context.Context, name string, number string) error {
+	url := fmt.Sprintf("%s/api/build/%s?buildNumbers=%s&artifacts=0", c.URL, url.PathEscape(name), url.QueryEscape(number))
+
+	if err := c.doAPI(ctx, http.MethodDelete, url, nil, nil, nil); err != nil {
+		var statusErr *StatusError
+		if errors.As(err, &statusErr) && statusErr.NotFound() {
+			return nil
+		}
+
+		return fmt.Errorf("unable to delete build %s/%s: %s", name, number, err)
+	}
+
+	return nil
+}
+
+// BuildPromotion describes the promotion of a build's artifacts to a repository, for the build promotion API.
+type BuildPromotion struct {
+	Status     string `json:"status,omitempty"`
+	Comment    string `json:"comment,omitempty"`
+	CIUser     string `json:"ciUser,omitempty"`
+	Timestamp  string `json:"timestamp,omitempty"`
+	SourceRepo string `json:"sourceRepo,omitempty"`
+	TargetRepo string `json:"targetRepo"`
+	// Copy is true to copy the artifacts to TargetRepo, or false to move them.
+	Copy         bool     `json:"copy"`
+	Artifacts    bool     `json:"artifacts"`
+	Dependencies bool     `json:"dependencies"`
+	Scopes       []string `json:"scopes,omitempty"`
+	FailFast     bool     `json:"failFast"`
+}
+
+// PromotionMessage is a message returned by the build promotion API, describing a problem with the promotion.
+type PromotionMessage struct {
+	Level   string `json:"level"`
+	Message string `json:"message"`
+}
+
+// promotionResult is the response body of the build promotion API. Errors are returned as either messages or, for
+// some failures, the service's generic errors.
+type promotionResult struct {
+	Messages []PromotionMessage `json:"messages"`
+	Errors   []struct {
+		Status  int    `json:"status"`
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+// PromotionError is returned when a build promotion fails, with the service's explanation of why.
+type PromotionError struct {
+	Name     string
+	Number   string
+	Messages []string
+}
+
+// Error implements the error interface.
+func (e *PromotionError) Error() string {
+	return fmt.Sprintf("unable to promote build %s/%s: %s", e.Name, e.Number, strings.Join(e.Messages, "; "))
+}
+
+// PromoteBuild promotes the build with name and number, returning any warnings the service reported. A promotion that
+// the service rejects, or that reports errors, returns a *PromotionError.
+func (c Client) PromoteBuild(ctx context.Context, name string, number string, promotion BuildPromotion) ([]string, error) {
+	url := fmt.Sprintf("%s/api/build/promote/%s/%s", c.URL, url.PathEscape(name), url.PathEscape(number))
+
+	result := promotionResult{}
+	if err := c.doAPI(ctx, http.MethodPost, url, nil, promotion, &result); err != nil {
+		var statusErr *StatusError
+		if !errors.As(err, &statusErr) || !statusErr.ClientError() {
+			return nil, fmt.Errorf("unable to promote build %s/%s: %s", name, number, err)
+		}
+
+		// the body may be truncated, in which case only the status error is reported
+		result = promotionResult{}
+		if json.Unmarshal([]byte(statusErr.Body), &result) != nil {
+			return nil, fmt.Errorf("unable to promote build %s/%s: %s", name, number, err)
+		}
+
+		promotionErr := &PromotionError{Name: name, Number: number}
+		for _, message := range result.Messages {
+			promotionErr.Messages = append(promotionErr.Messages, message.Message)
+		}
+		for _, resultErr := range result.Errors {
+			promotionErr.Messages = append(promotionErr.Messages, resultErr.Message)
+		}
+		if len(promotionErr.Messages) == 0 {
+			promotionErr.Messages = []string{statusErr.Status}
+		}
+
+		return nil, promotionErr
+	}
+
+	warnings := []string{}
+	promotionErr := &PromotionError{Name: name, Number: number}
+	for _, message := range result.Messages {
+		if strings.EqualFold(message.Level, "error") {
+			promotionErr.Messages = append(promotionErr.Messages, message.Message)
+		} else {
+			warnings = append(warnings, message.Message)
+		}
+	}
+	if len(promotionErr.Messages) > 0 {
+		return nil, promotionErr
+	}
+
+	return warnings, nil
+}
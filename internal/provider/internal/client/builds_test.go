@@ -0,0 +1,84 @@
+// Copyright 2021 Splunk, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestPromoteBuild(t *testing.T) {
+	tests := map[string]struct {
+		status       int
+		body         string
+		wantWarnings []string
+		wantMessages []string
+	}{
+		"success": {
+			status:       http.StatusOK,
+			body:         `{"messages":[{"level":"WARNING","message":"already promoted"}]}`,
+			wantWarnings: []string{"already promoted"},
+		},
+		"error messages": {
+			status:       http.StatusConflict,
+			body:         `{"messages":[{"level":"ERROR","message":"missing artifact a.txt"},{"level":"ERROR","message":"missing artifact b.txt"}]}`,
+			wantMessages: []string{"missing artifact a.txt", "missing artifact b.txt"},
+		},
+		"error messages with success status": {
+			status:       http.StatusOK,
+			body:         `{"messages":[{"level":"error","message":"missing artifact a.txt"}]}`,
+			wantMessages: []string{"missing artifact a.txt"},
+		},
+		"generic errors": {
+			status:       http.StatusNotFound,
+			body:         `{"errors":[{"status":404,"message":"Cannot find build"}]}`,
+			wantMessages: []string{"Cannot find build"},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodPost || r.URL.EscapedPath() != "/api/build/promote/my%20build/1" {
+					t.Errorf("unexpected request %s %s", r.Method, r.URL)
+				}
+				w.WriteHeader(test.status)
+				w.Write([]byte(test.body))
+			}))
+			defer server.Close()
+
+			c := Client{URL: server.URL}
+			warnings, err := c.PromoteBuild(context.Background(), "my build", "1", BuildPromotion{TargetRepo: "release"})
+
+			var promotionErr *PromotionError
+			if test.wantMessages == nil {
+				if err != nil {
+					t.Fatalf("unexpected error: %s", err)
+				}
+				if !reflect.DeepEqual(warnings, test.wantWarnings) {
+					t.Errorf("got warnings %q, want %q", warnings, test.wantWarnings)
+				}
+			} else if !errors.As(err, &promotionErr) {
+				t.Errorf("expected *PromotionError, got %v", err)
+			} else if !reflect.DeepEqual(promotionErr.Messages, test.wantMessages) {
+				t.Errorf("got messages %q, want %q", promotionErr.Messages, test.wantMessages)
+			}
+		})
+	}
+}
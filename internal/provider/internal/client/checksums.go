@@ -14,7 +14,83 @@
 
 package client
 
+import (
+	"bytes"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"fmt"
+	"hash"
+	"io"
+)
+
+// ChecksumType identifies one of the checksums stored in Checksums.
+type ChecksumType string
+
+const (
+	ChecksumSHA1   ChecksumType = "sha1"
+	ChecksumSHA256 ChecksumType = "sha256"
+	ChecksumMD5    ChecksumType = "md5"
+)
+
+// ChecksumTypes lists all supported ChecksumType values.
+var ChecksumTypes = []ChecksumType{ChecksumSHA1, ChecksumSHA256, ChecksumMD5}
+
 // Checksums represents the checksums returned from the file info endpoint.
 type Checksums struct {
-	SHA1 string
+	SHA1   string
+	SHA256 string
+	MD5    string
+}
+
+// Get returns the checksum of the given type, or an empty string if the type is unknown.
+func (c Checksums) Get(checksumType ChecksumType) string {
+	switch checksumType {
+	case ChecksumSHA1:
+		return c.SHA1
+	case ChecksumSHA256:
+		return c.SHA256
+	case ChecksumMD5:
+		return c.MD5
+	}
+
+	return ""
+}
+
+// newHash returns a hash.Hash computing the checksum of the given type, or nil if the type is unknown.
+func newHash(checksumType ChecksumType) hash.Hash {
+	switch checksumType {
+	case ChecksumSHA1:
+		return sha1.New()
+	case ChecksumSHA256:
+		return sha256.New()
+	case ChecksumMD5:
+		return md5.New()
+	}
+
+	return nil
+}
+
+// ContentChecksums returns the Checksums of content.
+func ContentChecksums(content []byte) Checksums {
+	// reading from memory can't fail
+	checksums, _ := readerChecksums(bytes.NewReader(content))
+
+	return checksums
+}
+
+// readerChecksums returns the Checksums of everything read from reader.
+func readerChecksums(reader io.Reader) (Checksums, error) {
+	sha1Hash := sha1.New()
+	sha256Hash := sha256.New()
+	md5Hash := md5.New()
+	if _, err := io.Copy(io.MultiWriter(sha1Hash, sha256Hash, md5Hash), reader); err != nil {
+		return Checksums{}, err
+	}
+
+	return Checksums{
+		SHA1:   fmt.Sprintf("%x", sha1Hash.Sum(nil)),
+		SHA256: fmt.Sprintf("%x", sha256Hash.Sum(nil)),
+		MD5:    fmt.Sprintf("%x", md5Hash.Sum(nil)),
+	}, nil
 }
@@ -0,0 +1,48 @@
+// Copyright 2021 Splunk, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+)
+
+func TestFileChecksum(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "file")
+	if err := ioutil.WriteFile(filename, []byte("content"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	checksums, err := Client{}.FileChecksums(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, checksumType := range ChecksumTypes {
+		checksum, err := Client{}.FileChecksum(filename, checksumType)
+		if err != nil {
+			t.Fatalf("%s: %s", checksumType, err)
+		}
+
+		if checksum != checksums.Get(checksumType) {
+			t.Errorf("%s: expected %s, got %s", checksumType, checksums.Get(checksumType), checksum)
+		}
+	}
+
+	if _, err := (Client{}).FileChecksum(filename, "crc32"); err == nil {
+		t.Error("expected an error for an unsupported checksum type")
+	}
+}
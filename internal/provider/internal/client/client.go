@@ -15,55 +15,199 @@
 package client
 
 import (
+	"bytes"
 	"context"
-	"crypto/sha1"
 	"encoding/json"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"net/http"
 	"os"
+	"strings"
+	"sync/atomic"
+	"time"
 )
 
 // Client represents an HTTP connection and credentials.
 type Client struct {
 	URL      string
-	Context  context.Context
 	Username string
 	Password string
+	// Token is sent as a bearer token, unless both Username and Password are set.
+	Token string
+	// CredentialHelper provides credentials when neither Username nor Token are set.
+	CredentialHelper *CredentialHelper
+	// ChecksumType is the checksum used to determine if a remote file exists and matches its local source.
+	ChecksumType ChecksumType
+	// Multipart configures when and how large files are uploaded in parts.
+	Multipart MultipartOptions
+	// Transfers limits the concurrency and bandwidth of file transfers. A nil Transfers doesn't limit them.
+	Transfers *TransferLimiter
+	// Locks serializes changes to files that are read and written back. A nil Locks doesn't serialize them.
+	Locks *PathLocks
+	// BasePath is prepended to paths by ResolvePath, typically a repository and a prefix within it.
+	BasePath string
+	// PathVars are the default values of {name} tokens expanded by ResolvePath.
+	PathVars map[string]string
 }
 
-// setBasicAuth adds basic auth username/password when Client has a Username set.
-func (c Client) setBasicAuth(request *http.Request) error {
-	if c.Username != "" {
-		if c.Password == "" {
-			return fmt.Errorf("username set in Client, but password unset")
+// setAuth adds authentication to a request. Basic auth is used when Client has a Username and Password set, otherwise
+// the Token is used as a bearer token. If neither is set, credentials are obtained from the CredentialHelper, if there
+// is one.
+func (c Client) setAuth(request *http.Request) error {
+	credentials := Credentials{Username: c.Username, Password: c.Password, Token: c.Token}
+
+	if credentials.Empty() && c.CredentialHelper != nil {
+		var err error
+		if credentials, err = c.CredentialHelper.Credentials(request.Context()); err != nil {
+			return err
 		}
+	}
 
-		request.SetBasicAuth(c.Username, c.Password)
+	switch {
+	case credentials.basic():
+		request.SetBasicAuth(credentials.Username, credentials.Password)
+	case credentials.Token != "":
+		// a username alongside a token, as JFrog CLI configurations and credential helpers may have, is ignored
+		request.Header.Set("Authorization", "Bearer "+credentials.Token)
+	case credentials.Username != "":
+		return fmt.Errorf("username set in Client, but password unset")
 	}
 
 	return nil
 }
 
 // Do performs a request against the service, returning an http.Response on success. As with http.Client.Do, it is the
-// responsibility of the calling function to close the resulting http.Response.body.
+// responsibility of the calling function to close the resulting http.Response.body. Requests are logged to the
+// LogSubsystem, with credentials masked.
 func (c Client) Do(request *http.Request) (response *http.Response, err error) {
+	ctx := c.LogContext(request.Context())
+
+	var body *countingReadCloser
+	if request.Body != nil {
+		body = &countingReadCloser{ReadCloser: request.Body}
+		request.Body = body
+	}
+
+	logRequest(ctx, request)
+	start := time.Now()
+
 	client := &http.Client{}
 	response, err = client.Do(request)
 
+	var bytesSent int64
+	if body != nil {
+		bytesSent = atomic.LoadInt64(&body.count)
+	}
+	logResponse(ctx, request, response, err, time.Since(start), bytesSent)
+
 	return
 }
 
+// FileURL returns the URL of a file at path, relative to the client's URL.
+func (c Client) FileURL(path string) string {
+	return fmt.Sprintf("%s/%s", strings.TrimSuffix(c.URL, "/"), path)
+}
+
+// doTransfer performs a request that transfers file contents. It waits for one of the Transfers slots to be free,
+// and paces the request and response bodies to the Transfers bandwidth limit. The slot is held until the
+// http.Response.Body is closed, which remains the responsibility of the calling function.
+func (c Client) doTransfer(request *http.Request) (*http.Response, error) {
+	ctx := request.Context()
+
+	if err := c.Transfers.acquire(ctx); err != nil {
+		return nil, err
+	}
+
+	if request.Body != nil {
+		request.Body = &throttledReadCloser{Reader: c.Transfers.reader(ctx, request.Body), closer: request.Body}
+		// the original body can't be replayed through the limiter
+		request.GetBody = nil
+	}
+
+	response, err := c.Do(request)
+	if err != nil {
+		c.Transfers.release()
+		return nil, err
+	}
+
+	response.Body = &throttledReadCloser{
+		Reader:  c.Transfers.reader(ctx, response.Body),
+		closer:  response.Body,
+		onClose: c.Transfers.release,
+	}
+
+	return response, nil
+}
+
+// doAPI performs a request against one of the service's REST API endpoints. If body is non-nil, its JSON encoding is
+// sent as the request body. If result is non-nil, the JSON body of a successful response is decoded into it. Any
+// response status other than 2xx is returned as a *StatusError.
+func (c Client) doAPI(ctx context.Context, method string, url string, header http.Header, body interface{}, result interface{}) error {
+	var bodyReader io.Reader
+	if body != nil {
+		bodyBytes, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("unable to serialize JSON body for %s %s: %s", method, url, err)
+		}
+		bodyReader = bytes.NewReader(bodyBytes)
+	}
+
+	request, err := http.NewRequestWithContext(ctx, method, url, bodyReader)
+	if err != nil {
+		return fmt.Errorf("unable to create %s request for url %s: %s", method, url, err)
+	}
+
+	for key, values := range header {
+		request.Header[key] = values
+	}
+	if body != nil {
+		request.Header.Set("Content-Type", "application/json")
+	}
+
+	if err := c.setAuth(request); err != nil {
+		return err
+	}
+
+	response, err := c.Do(request)
+	if err != nil {
+		return fmt.Errorf("unable to perform %s request for %s: %s", method, url, err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		return newStatusError(method, url, response)
+	}
+
+	if result != nil {
+		if err := json.NewDecoder(response.Body).Decode(result); err != nil {
+			return fmt.Errorf("unable to deserialize JSON response from %s %s: %s", method, url, err)
+		}
+	}
+
+	return nil
+}
+
+// splitRepoPath splits a path into its repository key and the path within that repository.
+func splitRepoPath(path string) (repoKey string, repoPath string) {
+	parts := strings.SplitN(strings.TrimPrefix(path, "/"), "/", 2)
+	if len(parts) < 2 {
+		return parts[0], ""
+	}
+
+	return parts[0], parts[1]
+}
+
 // Checksums returns the Checksums object from a remote path's file info endpoint.
-func (c Client) Checksums(path string) (checksums Checksums, err error) {
+func (c Client) Checksums(ctx context.Context, path string) (checksums Checksums, err error) {
 	url := fmt.Sprintf("%s/api/storage/%s", c.URL, path)
 
-	request, err := http.NewRequest(http.MethodGet, url, nil)
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
 		return checksums, fmt.Errorf("unable to create GET request for url %s", url)
 	}
 
-	if err := c.setBasicAuth(request); err != nil {
+	if err := c.setAuth(request); err != nil {
 		return Checksums{}, err
 	}
 
@@ -93,71 +237,170 @@ func (c Client) Checksums(path string) (checksums Checksums, err error) {
 	return info.Checksums, nil
 }
 
-// SHA1 returns the SHA1 checksum of a file at filename.
-func (c Client) SHA1(filename string) (sha1String string, err error) {
+// FileContent returns the content of a small file at a path relative to the client's URL, such as a metadata file.
+// found is false if the file doesn't exist.
+func (c Client) FileContent(ctx context.Context, path string) (content []byte, found bool, err error) {
+	url := c.FileURL(path)
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, false, fmt.Errorf("unable to create GET request for url %s: %s", url, err)
+	}
+
+	if err := c.setAuth(request); err != nil {
+		return nil, false, err
+	}
+
+	response, err := c.Do(request)
+	if err != nil {
+		return nil, false, fmt.Errorf("unable to perform GET request for %s: %s", url, err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode == http.StatusNotFound {
+		return nil, false, nil
+	}
+	if response.StatusCode != http.StatusOK {
+		return nil, false, newStatusError(http.MethodGet, url, response)
+	}
+
+	content, err = ioutil.ReadAll(response.Body)
+	if err != nil {
+		return nil, false, fmt.Errorf("unable to read %s: %s", url, err)
+	}
+
+	return content, true, nil
+}
+
+// FileChecksums returns the SHA1, SHA256, and MD5 checksums of a file at filename, reading it only once.
+func (c Client) FileChecksums(filename string) (checksums Checksums, err error) {
+	data, err := os.Open(filename)
+	if err != nil {
+		return checksums, fmt.Errorf("unable to read file %s: %s", filename, err)
+	}
+	defer data.Close()
+
+	checksums, err = readerChecksums(data)
+	if err != nil {
+		return checksums, fmt.Errorf("unable to compute checksums for %s: %s", filename, err)
+	}
+
+	return checksums, nil
+}
+
+// FileChecksum returns only the checksum of the given type of a file at filename, which is cheaper than FileChecksums
+// when a single checksum is compared.
+func (c Client) FileChecksum(filename string, checksumType ChecksumType) (string, error) {
+	hash := newHash(checksumType)
+	if hash == nil {
+		return "", fmt.Errorf("unsupported checksum type %q", checksumType)
+	}
+
 	data, err := os.Open(filename)
 	if err != nil {
 		return "", fmt.Errorf("unable to read file %s: %s", filename, err)
 	}
 	defer data.Close()
 
-	hash := sha1.New()
 	if _, err := io.Copy(hash, data); err != nil {
-		return "", fmt.Errorf("unable to compute sha1 for %s: %s", filename, err)
+		return "", fmt.Errorf("unable to compute %s checksum for %s: %s", checksumType, filename, err)
 	}
 
 	return fmt.Sprintf("%x", hash.Sum(nil)), nil
 }
 
-// Upload performs a PUT of a file's contents to a path relative to the client's URL.
-func (c Client) Upload(path string, filename string) error {
+// Upload performs a PUT of a file's contents to a path relative to the client's URL. Files at least as large as the
+// Multipart threshold are uploaded in parts when the service supports it. properties, which may be nil, are set on the
+// uploaded file.
+func (c Client) Upload(ctx context.Context, path string, filename string, properties Properties) error {
+	checksums, err := c.FileChecksums(filename)
+	if err != nil {
+		return fmt.Errorf("unable to get checksums for %s: %s", filename, err)
+	}
+
+	if c.Multipart.Threshold > 0 {
+		info, err := os.Stat(filename)
+		if err != nil {
+			return fmt.Errorf("unable to read file %s: %s", filename, err)
+		}
+
+		if info.Size() >= c.Multipart.Threshold {
+			supported, err := c.MultipartSupported(ctx)
+			if err != nil {
+				return err
+			}
+
+			if supported {
+				if err := c.uploadMultipart(ctx, path, filename, info.Size(), checksums); err != nil {
+					return err
+				}
+
+				// multipart uploads can't carry matrix parameters
+				return c.SetProperties(ctx, path, properties)
+			}
+		}
+	}
+
 	data, err := os.Open(filename)
 	if err != nil {
 		return fmt.Errorf("unable to read file %s: %s", filename, err)
 	}
 	defer data.Close()
 
-	url := fmt.Sprintf("%s/%s", c.URL, path)
+	if err := c.deploy(ctx, path, data, checksums, properties); err != nil {
+		return fmt.Errorf("failure uploading %s: %s", filename, err)
+	}
+
+	return nil
+}
+
+// UploadContent performs a PUT of content to a path relative to the client's URL, setting properties on the
+// uploaded file.
+func (c Client) UploadContent(ctx context.Context, path string, content []byte, properties Properties) error {
+	return c.deploy(ctx, path, bytes.NewReader(content), ContentChecksums(content), properties)
+}
+
+// deploy performs a PUT of body to a path relative to the client's URL, along with its checksums, and properties
+// as matrix parameters.
+func (c Client) deploy(ctx context.Context, path string, body io.Reader, checksums Checksums, properties Properties) error {
+	url := c.FileURL(path) + properties.matrixParams()
 
-	request, err := http.NewRequest(http.MethodPut, url, data)
+	request, err := http.NewRequestWithContext(ctx, http.MethodPut, url, body)
 	if err != nil {
-		return fmt.Errorf("unable to create PUT request for %s to %s: %s", filename, url, err)
+		return fmt.Errorf("unable to create PUT request for %s: %s", url, err)
 	}
 
-	if err := c.setBasicAuth(request); err != nil {
+	if err := c.setAuth(request); err != nil {
 		return err
 	}
 
-	sha1, err := c.SHA1(filename)
-	if err != nil {
-		return fmt.Errorf("unable to get sha1 for %s: %s", filename, err)
-	}
-
-	request.Header.Set("X-Checksum-Sha1", sha1)
+	request.Header.Set("X-Checksum-Sha1", checksums.SHA1)
+	request.Header.Set("X-Checksum-Sha256", checksums.SHA256)
+	request.Header.Set("X-Checksum", checksums.MD5)
 
-	response, err := c.Do(request)
+	response, err := c.doTransfer(request)
 	if err != nil {
-		return fmt.Errorf("unable to perform PUT request for %s to %s: %s", filename, url, err)
+		return fmt.Errorf("unable to perform PUT request for %s: %s", url, err)
 	}
-	response.Body.Close()
+	defer response.Body.Close()
 
 	if response.StatusCode != 201 {
-		return fmt.Errorf("response from PUT %s: %s", url, response.Status)
+		return newStatusError(http.MethodPut, url, response)
 	}
 
 	return nil
 }
 
 // Delete performs a DELETE of a path relative to the client's URL.
-func (c Client) Delete(path string) error {
-	url := fmt.Sprintf("%s/%s", c.URL, path)
+func (c Client) Delete(ctx context.Context, path string) error {
+	url := c.FileURL(path)
 
-	request, err := http.NewRequest(http.MethodDelete, url, nil)
+	request, err := http.NewRequestWithContext(ctx, http.MethodDelete, url, nil)
 	if err != nil {
 		return fmt.Errorf("unable to create DELETE request for %s: %s", url, err)
 	}
 
-	if err := c.setBasicAuth(request); err != nil {
+	if err := c.setAuth(request); err != nil {
 		return err
 	}
 
@@ -165,10 +408,10 @@ func (c Client) Delete(path string) error {
 	if err != nil {
 		return fmt.Errorf("unable to perform DELETE request for %s: %s", url, err)
 	}
-	response.Body.Close()
+	defer response.Body.Close()
 
 	if response.StatusCode != 204 {
-		return fmt.Errorf("response from DELETE %s: %s", url, response.Status)
+		return newStatusError(http.MethodDelete, url, response)
 	}
 
 	return nil
@@ -0,0 +1,34 @@
+// Copyright 2021 Splunk, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// Copy copies the file at sourcePath to targetPath on the service, without transferring its contents through the
+// client. An existing file at targetPath is replaced.
+func (c Client) Copy(ctx context.Context, sourcePath string, targetPath string) error {
+	copyURL := fmt.Sprintf("%s/api/copy/%s?to=/%s&suppressLayouts=1&failFast=1", c.URL, sourcePath, url.QueryEscape(targetPath))
+
+	if err := c.doAPI(ctx, http.MethodPost, copyURL, nil, nil, nil); err != nil {
+		return fmt.Errorf("unable to copy %s to %s: %s", sourcePath, targetPath, err)
+	}
+
+	return nil
+}
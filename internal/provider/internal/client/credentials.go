@@ -0,0 +1,286 @@
+// Copyright 2021 Splunk, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// CredentialHelperURLEnvVar is set in the environment of a credential helper to the URL credentials are needed for.
+const CredentialHelperURLEnvVar = "ARTIFACTORY_URL"
+
+// Credentials are used to authenticate requests, with basic authentication when Username and Password are set, or
+// otherwise by sending Token as a bearer token.
+type Credentials struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+	Token    string `json:"token"`
+}
+
+// Empty returns true if no credentials are set.
+func (c Credentials) Empty() bool {
+	return c.Username == "" && c.Token == ""
+}
+
+// basic returns true if the credentials are used for basic authentication.
+func (c Credentials) basic() bool {
+	return c.Username != "" && c.Password != ""
+}
+
+// CredentialHelper obtains Credentials by running an external command, whose standard output is a JSON object with
+// either "username" and "password", or "token". The command is run the first time credentials are needed, and its
+// result is reused for the lifetime of the CredentialHelper.
+type CredentialHelper struct {
+	command []string
+	url     string
+
+	mu          sync.Mutex
+	done        bool
+	credentials Credentials
+	err         error
+}
+
+// NewCredentialHelper returns a CredentialHelper that runs command, the first element of which is the program to
+// run and the rest its arguments, to obtain credentials for url.
+func NewCredentialHelper(command []string, url string) *CredentialHelper {
+	return &CredentialHelper{command: command, url: url}
+}
+
+// Credentials returns the credentials produced by the helper, running it if it hasn't been run yet. Concurrent
+// callers wait for a single run of the helper.
+func (h *CredentialHelper) Credentials(ctx context.Context) (Credentials, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if !h.done {
+		credentials, err := h.run(ctx)
+		if err != nil && ctx.Err() != nil {
+			// a cancelled run says nothing about the helper, so let the next caller try again
+			return Credentials{}, err
+		}
+
+		h.credentials, h.err, h.done = credentials, err, true
+	}
+
+	return h.credentials, h.err
+}
+
+// resolved returns the helper's credentials if it has already been run successfully, without running it.
+func (h *CredentialHelper) resolved() (Credentials, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return h.credentials, h.done && h.err == nil
+}
+
+// run executes the helper command and parses its output.
+func (h *CredentialHelper) run(ctx context.Context) (Credentials, error) {
+	if len(h.command) == 0 {
+		return Credentials{}, fmt.Errorf("credential helper command is empty")
+	}
+
+	cmd := exec.CommandContext(ctx, h.command[0], h.command[1:]...)
+	cmd.Env = append(os.Environ(), fmt.Sprintf("%s=%s", CredentialHelperURLEnvVar, h.url))
+
+	stderr := &bytes.Buffer{}
+	cmd.Stderr = stderr
+
+	output, err := cmd.Output()
+	if err != nil {
+		return Credentials{}, fmt.Errorf("credential helper %s failed: %s: %s", h.command[0], err, strings.TrimSpace(stderr.String()))
+	}
+
+	credentials := Credentials{}
+	if err := json.Unmarshal(output, &credentials); err != nil {
+		return Credentials{}, fmt.Errorf("unable to parse output of credential helper %s as JSON: %s", h.command[0], err)
+	}
+
+	if credentials.Empty() {
+		return Credentials{}, fmt.Errorf("credential helper %s returned neither a username nor a token", h.command[0])
+	}
+	if credentials.Token == "" && credentials.Password == "" {
+		return Credentials{}, fmt.Errorf("credential helper %s returned a username without a password", h.command[0])
+	}
+
+	return credentials, nil
+}
+
+// DefaultNetrcFile returns the netrc file used when none is given: the file named by the NETRC environment
+// variable, or .netrc in the user's home directory.
+func DefaultNetrcFile() string {
+	if netrc := os.Getenv("NETRC"); netrc != "" {
+		return netrc
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+
+	return filepath.Join(home, ".netrc")
+}
+
+// NetrcCredentials returns the login and password of the entry in the netrc file at filename for the host of
+// serviceURL, falling back to the file's default entry. ok is false if neither exists.
+func NetrcCredentials(filename string, serviceURL string) (credentials Credentials, ok bool, err error) {
+	parsedURL, err := url.Parse(serviceURL)
+	if err != nil {
+		return credentials, false, fmt.Errorf("unable to parse url %s: %s", serviceURL, err)
+	}
+	host := parsedURL.Hostname()
+
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return credentials, false, fmt.Errorf("unable to read netrc file %s: %s", filename, err)
+	}
+
+	// the default entry is stored under a key that can't be a hostname
+	const defaultKey = ""
+
+	// tokens may be separated by any whitespace, including newlines, except that macro definitions run from
+	// "macdef" until the next empty line
+	tokens := []string{}
+	inMacro := false
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		if inMacro {
+			inMacro = strings.TrimSpace(line) != ""
+			continue
+		}
+
+		for _, field := range strings.Fields(line) {
+			if field == "macdef" {
+				inMacro = true
+				break
+			}
+			tokens = append(tokens, field)
+		}
+	}
+
+	entries := map[string]*Credentials{}
+	var current *Credentials
+	for i := 0; i < len(tokens); i++ {
+		value := ""
+		if i+1 < len(tokens) {
+			value = tokens[i+1]
+		}
+
+		switch tokens[i] {
+		case "machine", "default":
+			key := defaultKey
+			if tokens[i] == "machine" {
+				key = value
+				i++
+			}
+
+			current = &Credentials{}
+			// as with other netrc readers, the first matching entry wins
+			if _, ok := entries[key]; !ok {
+				entries[key] = current
+			}
+		case "login":
+			if current != nil {
+				current.Username = value
+			}
+			i++
+		case "password":
+			if current != nil {
+				current.Password = value
+			}
+			i++
+		case "account":
+			i++
+		}
+	}
+
+	if credentials, ok := entries[host]; ok {
+		return *credentials, true, nil
+	}
+	if credentials, ok := entries[defaultKey]; ok {
+		return *credentials, true, nil
+	}
+
+	return Credentials{}, false, nil
+}
+
+// jfrogCLIConfig is the subset of the JFrog CLI configuration file used to find credentials.
+type jfrogCLIConfig struct {
+	Servers []struct {
+		ServerID    string `json:"serverId"`
+		User        string `json:"user"`
+		Password    string `json:"password"`
+		AccessToken string `json:"accessToken"`
+	} `json:"servers"`
+}
+
+// DefaultJFrogCLIConfigFile returns the JFrog CLI configuration file used when none is given: jfrog-cli.conf.v6 in
+// the directory named by the JFROG_CLI_HOME_DIR environment variable, or in .jfrog in the user's home directory.
+func DefaultJFrogCLIConfigFile() string {
+	homeDir := os.Getenv("JFROG_CLI_HOME_DIR")
+	if homeDir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return ""
+		}
+		homeDir = filepath.Join(home, ".jfrog")
+	}
+
+	return filepath.Join(homeDir, "jfrog-cli.conf.v6")
+}
+
+// JFrogCLICredentials returns the credentials of the server with serverID in the JFrog CLI configuration file at
+// filename.
+func JFrogCLICredentials(filename string, serverID string) (Credentials, error) {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return Credentials{}, fmt.Errorf("unable to read JFrog CLI configuration %s: %s", filename, err)
+	}
+
+	config := jfrogCLIConfig{}
+	if err := json.Unmarshal(data, &config); err != nil {
+		return Credentials{}, fmt.Errorf("unable to parse JFrog CLI configuration %s, which may be encrypted: %s", filename, err)
+	}
+
+	for _, server := range config.Servers {
+		if server.ServerID != serverID {
+			continue
+		}
+
+		// configurations made with an access token also have the user it belongs to, which isn't needed to use it
+		if server.AccessToken != "" {
+			return Credentials{Token: server.AccessToken}, nil
+		}
+		if server.Password == "" {
+			return Credentials{}, fmt.Errorf("server %s in JFrog CLI configuration %s has neither a password nor an access token", serverID, filename)
+		}
+
+		return Credentials{Username: server.User, Password: server.Password}, nil
+	}
+
+	return Credentials{}, fmt.Errorf("server %s not found in JFrog CLI configuration %s", serverID, filename)
+}
@@ -0,0 +1,159 @@
+// Copyright 2021 Splunk, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestNetrcCredentials(t *testing.T) {
+	netrcFile := filepath.Join(t.TempDir(), "netrc")
+	netrc := `machine other.example.com login other password other-password
+
+machine repo.example.com
+  login user
+  password secret
+macdef init
+  machine repo.example.com login macro password macro
+
+default login anonymous password anonymous
+`
+	if err := ioutil.WriteFile(netrcFile, []byte(netrc), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	credentials, ok, err := NetrcCredentials(netrcFile, "https://repo.example.com/artifactory")
+	if err != nil || !ok {
+		t.Fatalf("expected credentials, got ok=%v err=%v", ok, err)
+	}
+	if credentials != (Credentials{Username: "user", Password: "secret"}) {
+		t.Errorf("unexpected credentials for matching host: %+v", credentials)
+	}
+
+	credentials, ok, err = NetrcCredentials(netrcFile, "https://unknown.example.com")
+	if err != nil || !ok {
+		t.Fatalf("expected default credentials, got ok=%v err=%v", ok, err)
+	}
+	if credentials != (Credentials{Username: "anonymous", Password: "anonymous"}) {
+		t.Errorf("unexpected default credentials: %+v", credentials)
+	}
+}
+
+func TestCredentialHelperRunsOnce(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not available")
+	}
+
+	countFile := filepath.Join(t.TempDir(), "count")
+	helper := NewCredentialHelper([]string{"sh", "-c", `echo run >> "$0"; echo "{\"token\": \"token-for-$ARTIFACTORY_URL\"}"`, countFile}, "https://repo.example.com")
+
+	for i := 0; i < 3; i++ {
+		credentials, err := helper.Credentials(context.Background())
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if credentials.Token != "token-for-https://repo.example.com" {
+			t.Errorf("unexpected token: %q", credentials.Token)
+		}
+	}
+
+	runs, err := ioutil.ReadFile(countFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count := strings.Count(string(runs), "run"); count != 1 {
+		t.Errorf("expected helper to run once, ran %d times", count)
+	}
+}
+
+func TestJFrogCLICredentials(t *testing.T) {
+	configFile := filepath.Join(t.TempDir(), "jfrog-cli.conf.v6")
+	config := `{
+  "servers": [
+    {"serverId": "token", "url": "https://repo.example.com/", "user": "user", "accessToken": "access-token"},
+    {"serverId": "password", "url": "https://repo.example.com/", "user": "user", "password": "secret"},
+    {"serverId": "none", "url": "https://repo.example.com/", "user": "user"}
+  ]
+}`
+	if err := ioutil.WriteFile(configFile, []byte(config), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	for serverID, expected := range map[string]Credentials{
+		"token":    {Token: "access-token"},
+		"password": {Username: "user", Password: "secret"},
+	} {
+		credentials, err := JFrogCLICredentials(configFile, serverID)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %s", serverID, err)
+		}
+		if credentials != expected {
+			t.Errorf("%s: expected %+v, got %+v", serverID, expected, credentials)
+		}
+	}
+
+	for _, serverID := range []string{"none", "missing"} {
+		if _, err := JFrogCLICredentials(configFile, serverID); err == nil {
+			t.Errorf("%s: expected an error", serverID)
+		}
+	}
+}
+
+func TestSetAuth(t *testing.T) {
+	_, shErr := exec.LookPath("sh")
+
+	tests := []struct {
+		name          string
+		client        Client
+		authorization string
+		err           bool
+	}{
+		{name: "basic", client: Client{Username: "user", Password: "secret"}, authorization: "Basic dXNlcjpzZWNyZXQ="},
+		{name: "token", client: Client{Token: "token"}, authorization: "Bearer token"},
+		{name: "user and token", client: Client{Username: "user", Token: "token"}, authorization: "Bearer token"},
+		{name: "user without password", client: Client{Username: "user"}, err: true},
+		{
+			name:          "helper with user and token",
+			client:        Client{CredentialHelper: NewCredentialHelper([]string{"sh", "-c", `echo '{"username": "user", "token": "helper-token"}'`}, "https://repo.example.com")},
+			authorization: "Bearer helper-token",
+		},
+		{name: "none"},
+	}
+
+	for _, test := range tests {
+		if test.client.CredentialHelper != nil && shErr != nil {
+			continue
+		}
+
+		request, err := http.NewRequest(http.MethodGet, "https://repo.example.com/repo/file", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = test.client.setAuth(request)
+		if (err != nil) != test.err {
+			t.Errorf("%s: got error %v, want error %t", test.name, err, test.err)
+		}
+		if authorization := request.Header.Get("Authorization"); authorization != test.authorization {
+			t.Errorf("%s: expected Authorization %q, got %q", test.name, test.authorization, authorization)
+		}
+	}
+}
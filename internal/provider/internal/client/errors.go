@@ -0,0 +1,87 @@
+// Copyright 2021 Splunk, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+// maxErrorBodySize is the most of a response body that is included in a StatusError.
+const maxErrorBodySize = 4096
+
+// unsupportedRegex matches the messages of responses to requests for features that the service's edition or license
+// doesn't support.
+var unsupportedRegex = regexp.MustCompile(`(?i)licen[cs]e|not supported|unsupported|only (available|supported)`)
+
+// StatusError is returned when the service responds with an unexpected status.
+type StatusError struct {
+	Method     string
+	URL        string
+	Status     string
+	StatusCode int
+	// Body is the beginning of the response body, which often describes the problem.
+	Body string
+}
+
+// newStatusError returns a StatusError for a response, reading a limited amount of its body.
+func newStatusError(method string, url string, response *http.Response) *StatusError {
+	body, _ := ioutil.ReadAll(io.LimitReader(response.Body, maxErrorBodySize))
+
+	return &StatusError{
+		Method:     method,
+		URL:        url,
+		Status:     response.Status,
+		StatusCode: response.StatusCode,
+		Body:       strings.TrimSpace(string(body)),
+	}
+}
+
+// Error implements the error interface.
+func (e *StatusError) Error() string {
+	if e.Body == "" {
+		return fmt.Sprintf("response from %s %s: %s", e.Method, e.URL, e.Status)
+	}
+
+	return fmt.Sprintf("response from %s %s: %s: %s", e.Method, e.URL, e.Status, e.Body)
+}
+
+// NotFound returns true if the error is due to a 404 Not Found response.
+func (e *StatusError) NotFound() bool {
+	return e.StatusCode == http.StatusNotFound
+}
+
+// ClientError returns true if the error is due to a 4xx response, other than 429 Too Many Requests.
+func (e *StatusError) ClientError() bool {
+	return e.StatusCode >= 400 && e.StatusCode < 500 && e.StatusCode != http.StatusTooManyRequests
+}
+
+// Unsupported returns true if the error is due to the service not supporting the request's feature, in its edition or
+// with its license. Such responses are 501 Not Implemented, or a 400 Bad Request or 403 Forbidden saying so. A 404 Not
+// Found never is, as it can't be told apart from a missing path.
+func (e *StatusError) Unsupported() bool {
+	switch e.StatusCode {
+	case http.StatusNotImplemented:
+		return true
+	case http.StatusBadRequest, http.StatusForbidden:
+		return unsupportedRegex.MatchString(e.Body)
+	}
+
+	return false
+}
@@ -0,0 +1,147 @@
+// Copyright 2021 Splunk, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// FolderInfo describes a folder, as returned from the folder info endpoint.
+type FolderInfo struct {
+	Created string
+	// Children are the names of the files and folders directly within the folder.
+	Children []string
+}
+
+type folderInfo struct {
+	Created  string
+	Children *[]struct {
+		URI    string
+		Folder bool
+	}
+}
+
+// FileListing describes a file found by listing a folder.
+type FileListing struct {
+	// Path is the path of the file relative to the listed folder.
+	Path         string
+	Size         int64
+	LastModified time.Time
+	SHA1         string
+}
+
+type fileList struct {
+	Files []struct {
+		URI          string
+		Size         int64
+		LastModified string
+		Folder       bool
+		SHA1         string
+	}
+}
+
+// lastModifiedLayouts are the layouts timestamps in file listings may use.
+var lastModifiedLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.000-0700",
+}
+
+// CreateFolder creates a folder at a path relative to the client's URL. Any missing parent folders are also created.
+// Creating a folder that already exists isn't an error.
+func (c Client) CreateFolder(ctx context.Context, path string) error {
+	url := c.FileURL(strings.TrimSuffix(path, "/")) + "/"
+
+	if err := c.doAPI(ctx, http.MethodPut, url, nil, nil, nil); err != nil {
+		return fmt.Errorf("unable to create folder %s: %s", path, err)
+	}
+
+	return nil
+}
+
+// Folder returns the FolderInfo of a folder at a path relative to the client's URL. found is false if nothing exists
+// at the path, and it is an error for the path to be a file.
+func (c Client) Folder(ctx context.Context, path string) (info FolderInfo, found bool, err error) {
+	url := fmt.Sprintf("%s/api/storage/%s", c.URL, strings.TrimSuffix(path, "/"))
+
+	response := folderInfo{}
+	if err := c.doAPI(ctx, http.MethodGet, url, nil, nil, &response); err != nil {
+		var statusErr *StatusError
+		if errors.As(err, &statusErr) && statusErr.NotFound() {
+			return info, false, nil
+		}
+
+		return info, false, fmt.Errorf("unable to read folder info at %s: %s", url, err)
+	}
+
+	// only folders have children, even if there are none
+	if response.Children == nil {
+		return info, true, fmt.Errorf("%s is a file, not a folder", path)
+	}
+
+	info.Created = response.Created
+	info.Children = make([]string, 0, len(*response.Children))
+	for _, child := range *response.Children {
+		info.Children = append(info.Children, strings.TrimPrefix(child.URI, "/"))
+	}
+
+	return info, true, nil
+}
+
+// ListFiles returns every file within a folder at a path relative to the client's URL, including those in its
+// subfolders. found is false if nothing exists at the path.
+func (c Client) ListFiles(ctx context.Context, path string) (files []FileListing, found bool, err error) {
+	url := fmt.Sprintf("%s/api/storage/%s?list&deep=1&listFolders=0&mdTimestamps=1", c.URL, strings.TrimSuffix(path, "/"))
+
+	response := fileList{}
+	if err := c.doAPI(ctx, http.MethodGet, url, nil, nil, &response); err != nil {
+		var statusErr *StatusError
+		if errors.As(err, &statusErr) && statusErr.NotFound() {
+			return nil, false, nil
+		}
+
+		return nil, false, fmt.Errorf("unable to list files in %s: %s", path, err)
+	}
+
+	files = make([]FileListing, 0, len(response.Files))
+	for _, file := range response.Files {
+		if file.Folder {
+			continue
+		}
+
+		listing := FileListing{
+			Path: strings.TrimPrefix(file.URI, "/"),
+			Size: file.Size,
+			SHA1: file.SHA1,
+		}
+
+		for _, layout := range lastModifiedLayouts {
+			if listing.LastModified, err = time.Parse(layout, file.LastModified); err == nil {
+				break
+			}
+		}
+		if err != nil {
+			return nil, true, fmt.Errorf("unable to parse modification time %q of %s: %s", file.LastModified, listing.Path, err)
+		}
+
+		files = append(files, listing)
+	}
+
+	return files, true, nil
+}
@@ -0,0 +1,32 @@
+// Copyright 2021 Splunk, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// ReindexHelmRepository recalculates the index of a Helm repository, so that charts uploaded to it are listed.
+func (c Client) ReindexHelmRepository(ctx context.Context, repoKey string) error {
+	url := fmt.Sprintf("%s/api/helm/%s/reindex", c.URL, repoKey)
+
+	if err := c.doAPI(ctx, http.MethodPost, url, nil, nil, nil); err != nil {
+		return fmt.Errorf("unable to reindex Helm repository %s: %s", repoKey, err)
+	}
+
+	return nil
+}
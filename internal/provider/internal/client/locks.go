@@ -0,0 +1,52 @@
+// Copyright 2021 Splunk, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"sync"
+)
+
+// PathLocks serializes changes to files that are read, changed and written back, such as metadata listing the
+// versions uploaded by several resources. A single PathLocks is shared by every copy of a Client, so such changes by
+// resources applied in parallel don't overwrite each other. Changes made at the same time by other Terraform runs or
+// clients aren't serialized.
+type PathLocks struct {
+	mu    sync.Mutex
+	locks map[string]*sync.Mutex
+}
+
+// NewPathLocks returns a PathLocks without any locked paths.
+func NewPathLocks() *PathLocks {
+	return &PathLocks{locks: map[string]*sync.Mutex{}}
+}
+
+// Lock waits for path to be unlocked and locks it, returning a function that unlocks it. A nil PathLocks doesn't
+// lock anything.
+func (l *PathLocks) Lock(path string) func() {
+	if l == nil {
+		return func() {}
+	}
+
+	l.mu.Lock()
+	lock, ok := l.locks[path]
+	if !ok {
+		lock = &sync.Mutex{}
+		l.locks[path] = lock
+	}
+	l.mu.Unlock()
+
+	lock.Lock()
+	return lock.Unlock
+}
@@ -0,0 +1,53 @@
+// Copyright 2021 Splunk, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestPathLocks(t *testing.T) {
+	locks := NewPathLocks()
+
+	// concurrent changes to the same path are serialized, so none are lost
+	var wg sync.WaitGroup
+	versions := []int{}
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(version int) {
+			defer wg.Done()
+			defer locks.Lock("repo/com/example/tool/maven-metadata.xml")()
+
+			existing := append([]int{}, versions...)
+			versions = append(existing, version)
+		}(i)
+	}
+	wg.Wait()
+
+	if len(versions) != 20 {
+		t.Errorf("got %d versions, want 20", len(versions))
+	}
+
+	// other paths can be locked while one is locked
+	unlock := locks.Lock("repo/a")
+	locks.Lock("repo/b")()
+	unlock()
+
+	// a nil PathLocks doesn't lock
+	var none *PathLocks
+	none.Lock("repo/a")()
+	none.Lock("repo/a")()
+}
@@ -0,0 +1,199 @@
+// Copyright 2021 Splunk, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+const (
+	// LogSubsystem is the name of the tflog subsystem HTTP traffic is logged to.
+	LogSubsystem = "http"
+	// LogLevelEnvVar may be set to a log level to override TF_LOG_PROVIDER for only HTTP traffic.
+	LogLevelEnvVar = "TF_LOG_PROVIDER_ARTIFACTS_HTTP"
+
+	// requestHeaderFieldPrefix and responseHeaderFieldPrefix prefix the log fields of individual headers.
+	requestHeaderFieldPrefix  = "http_req_header_"
+	responseHeaderFieldPrefix = "http_resp_header_"
+)
+
+// sensitiveHeaders are headers whose values are never logged.
+var sensitiveHeaders = []string{
+	"Authorization",
+	"Proxy-Authorization",
+	"Cookie",
+	"Set-Cookie",
+	"X-JFrog-Art-Api",
+	multipartTokenHeader,
+}
+
+// sensitiveValueRegexes match credentials that may appear in URLs or other logged values.
+var sensitiveValueRegexes = []*regexp.Regexp{
+	regexp.MustCompile(`(?i)((?:token|signature|password|api_?key)=)[^&\s]+`),
+}
+
+type attemptContextKey struct{}
+
+// withAttempt returns ctx marked with the attempt number of the request made with it, for logging.
+func withAttempt(ctx context.Context, attempt int) context.Context {
+	return context.WithValue(ctx, attemptContextKey{}, attempt)
+}
+
+// LogContext returns ctx with the HTTP logging subsystem, configured to mask the client's credentials and any
+// sensitive headers.
+func (c Client) LogContext(ctx context.Context) context.Context {
+	ctx = tflog.NewSubsystem(ctx, LogSubsystem, tflog.WithLevelFromEnv(LogLevelEnvVar))
+
+	sensitiveFieldKeys := make([]string, 0, 2*len(sensitiveHeaders))
+	for _, header := range sensitiveHeaders {
+		sensitiveFieldKeys = append(sensitiveFieldKeys, headerFieldKey(requestHeaderFieldPrefix, header), headerFieldKey(responseHeaderFieldPrefix, header))
+	}
+	ctx = tflog.SubsystemMaskFieldValuesWithFieldKeys(ctx, LogSubsystem, sensitiveFieldKeys...)
+	ctx = tflog.SubsystemMaskAllFieldValuesRegexes(ctx, LogSubsystem, sensitiveValueRegexes...)
+	ctx = tflog.SubsystemMaskMessageRegexes(ctx, LogSubsystem, sensitiveValueRegexes...)
+
+	// an empty string would match, and mask, everything
+	candidates := []string{c.Password, c.Token}
+	if c.CredentialHelper != nil {
+		if credentials, ok := c.CredentialHelper.resolved(); ok {
+			candidates = append(candidates, credentials.Password, credentials.Token)
+		}
+	}
+
+	secrets := []string{}
+	for _, secret := range candidates {
+		if secret != "" {
+			secrets = append(secrets, secret)
+		}
+	}
+	if len(secrets) > 0 {
+		ctx = tflog.SubsystemMaskAllFieldValuesStrings(ctx, LogSubsystem, secrets...)
+		ctx = tflog.SubsystemMaskMessageStrings(ctx, LogSubsystem, secrets...)
+	}
+
+	return ctx
+}
+
+// LogSettings logs the client's settings to the HTTP logging subsystem, without its credentials.
+func (c Client) LogSettings(ctx context.Context) {
+	ctx = c.LogContext(ctx)
+
+	tflog.SubsystemDebug(ctx, LogSubsystem, "Configured HTTP client", map[string]interface{}{
+		"url":                 c.URL,
+		"auth":                c.authMethod(),
+		"checksum_type":       string(c.ChecksumType),
+		"multipart_threshold": c.Multipart.Threshold,
+	})
+}
+
+// authMethod describes how the client authenticates, for logging.
+func (c Client) authMethod() string {
+	switch {
+	case Credentials{Username: c.Username, Password: c.Password}.basic():
+		return "basic"
+	case c.Token != "":
+		return "token"
+	case c.CredentialHelper != nil:
+		return "credential_helper"
+	}
+
+	return "none"
+}
+
+// logRequest logs a request about to be sent.
+func logRequest(ctx context.Context, request *http.Request) {
+	fields := requestFields(ctx, request)
+
+	tflog.SubsystemDebug(ctx, LogSubsystem, "Sending HTTP request", fields)
+
+	if tflog.SubsystemIsTrace(ctx, LogSubsystem) {
+		tflog.SubsystemTrace(ctx, LogSubsystem, "HTTP request headers", fields, headerFields(requestHeaderFieldPrefix, request.Header))
+	}
+}
+
+// logResponse logs the outcome of a request.
+func logResponse(ctx context.Context, request *http.Request, response *http.Response, err error, duration time.Duration, bytesSent int64) {
+	fields := requestFields(ctx, request)
+	fields["http_duration_ms"] = duration.Milliseconds()
+	fields["http_req_bytes"] = bytesSent
+
+	if err != nil {
+		fields["error"] = err.Error()
+		tflog.SubsystemDebug(ctx, LogSubsystem, "HTTP request failed", fields)
+		return
+	}
+
+	fields["http_status_code"] = response.StatusCode
+	fields["http_resp_bytes"] = response.ContentLength
+
+	tflog.SubsystemDebug(ctx, LogSubsystem, "Received HTTP response", fields)
+
+	if tflog.SubsystemIsTrace(ctx, LogSubsystem) {
+		tflog.SubsystemTrace(ctx, LogSubsystem, "HTTP response headers", fields, headerFields(responseHeaderFieldPrefix, response.Header))
+	}
+}
+
+// requestFields returns the log fields identifying a request.
+func requestFields(ctx context.Context, request *http.Request) map[string]interface{} {
+	fields := map[string]interface{}{
+		"http_method": request.Method,
+		// Redacted removes any password embedded in the URL
+		"http_url": request.URL.Redacted(),
+	}
+
+	if attempt, ok := ctx.Value(attemptContextKey{}).(int); ok {
+		fields["http_attempt"] = attempt
+	}
+
+	return fields
+}
+
+// headerFields returns a log field for each header, with keys built from prefix and the lowercase header name.
+func headerFields(prefix string, header http.Header) map[string]interface{} {
+	fields := make(map[string]interface{}, len(header))
+	for name, values := range header {
+		fields[headerFieldKey(prefix, name)] = strings.Join(values, ", ")
+	}
+
+	return fields
+}
+
+// headerFieldKey returns the log field key for a header.
+func headerFieldKey(prefix string, header string) string {
+	return prefix + strings.ToLower(header)
+}
+
+// countingReadCloser counts the bytes read through it.
+type countingReadCloser struct {
+	io.ReadCloser
+	count int64
+}
+
+// Read implements io.Reader.
+func (r *countingReadCloser) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	// the transport may read the body from another goroutine
+	atomic.AddInt64(&r.count, int64(n))
+
+	return n, err
+}
@@ -0,0 +1,111 @@
+// Copyright 2021 Splunk, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-log/tflogtest"
+)
+
+func TestSensitiveValueRegexes(t *testing.T) {
+	tests := []struct {
+		value  string
+		masked bool
+	}{
+		{"https://repo.example.com/file?token=abc123", true},
+		{"https://repo.example.com/file?x=1&signature=abc123&y=2", true},
+		{"password=hunter2", true},
+		{"PASSWORD=hunter2", true},
+		{"api_key=abc123", true},
+		{"apikey=abc123", true},
+		{"https://repo.example.com/tokens/file?x=1", false},
+		{"token_scope=applied-permissions/user", false},
+		{"token=", false},
+	}
+
+	for _, test := range tests {
+		masked := false
+		for _, regex := range sensitiveValueRegexes {
+			if regex.MatchString(test.value) {
+				masked = true
+			}
+		}
+
+		if masked != test.masked {
+			t.Errorf("%q: expected masked to be %v, got %v", test.value, test.masked, masked)
+		}
+	}
+}
+
+func TestDoMasksCredentials(t *testing.T) {
+	t.Setenv(LogLevelEnvVar, "TRACE")
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Set-Cookie", "session=cookie-secret")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	var output bytes.Buffer
+	ctx := tflogtest.RootLogger(context.Background(), &output)
+
+	c := Client{URL: server.URL, Token: "token-secret"}
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, c.FileURL("repo/file?signature=signature-secret"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	request.Header.Set("X-JFrog-Art-Api", "api-key-secret")
+	if err := c.setAuth(request); err != nil {
+		t.Fatal(err)
+	}
+
+	response, err := c.Do(request)
+	if err != nil {
+		t.Fatal(err)
+	}
+	response.Body.Close()
+
+	logged := output.String()
+
+	entries, err := tflogtest.MultilineJSONDecode(&output)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	headersLogged := false
+	for _, entry := range entries {
+		if entry["@message"] == "HTTP request headers" {
+			headersLogged = true
+			if entry[headerFieldKey(requestHeaderFieldPrefix, "Authorization")] == nil {
+				t.Errorf("expected the Authorization header to be logged, masked: %v", entry)
+			}
+		}
+	}
+	if !headersLogged {
+		t.Fatalf("expected request headers to be logged:\n%s", logged)
+	}
+
+	for _, secret := range []string{"token-secret", "signature-secret", "api-key-secret", "cookie-secret"} {
+		if strings.Contains(logged, secret) {
+			t.Errorf("expected %q to be masked in log output:\n%s", secret, logged)
+		}
+	}
+}
@@ -0,0 +1,385 @@
+// Copyright 2021 Splunk, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	multipartTokenHeader = "X-JFrog-Upload-Token"
+	// multipartPartAttempts is how many times uploading a part is attempted before giving up.
+	multipartPartAttempts = 3
+	// MultipartPartSizeUnit is the unit of multipart part sizes, which the service accepts in whole MiB.
+	MultipartPartSizeUnit = 1 << 20
+)
+
+// variables, rather than constants, so that tests can shorten them
+var (
+	// multipartPollInterval is how often the status of a completing multipart upload is checked.
+	multipartPollInterval = 5 * time.Second
+	// multipartCompleteTimeout is how long to wait for the service to assemble the parts of a completed upload.
+	multipartCompleteTimeout = 30 * time.Minute
+)
+
+// MultipartOptions configures uploading large files in parts.
+type MultipartOptions struct {
+	// Threshold is the size, in bytes, at or above which files are uploaded in parts. Zero disables multipart uploads.
+	Threshold int64
+	// PartSize is the size, in bytes, of each uploaded part. It must be a multiple of MultipartPartSizeUnit.
+	PartSize int64
+	// Concurrency is the number of parts uploaded in parallel.
+	Concurrency int
+	// StateDir is the directory in which upload progress is stored, so that a failed upload can be resumed.
+	StateDir string
+}
+
+// multipartState is the persisted progress of a multipart upload.
+type multipartState struct {
+	Token     string
+	PartSize  int64
+	Completed map[int]bool
+}
+
+type multipartConfigResponse struct {
+	Supported bool `json:"supported"`
+}
+
+type multipartCreateResponse struct {
+	Token string `json:"token"`
+}
+
+type multipartURLResponse struct {
+	URL string `json:"url"`
+}
+
+type multipartStatusResponse struct {
+	Status   string `json:"status"`
+	Progress int    `json:"progress"`
+	Error    string `json:"error"`
+}
+
+// MultipartSupported returns true if the service supports multipart uploads.
+func (c Client) MultipartSupported(ctx context.Context) (bool, error) {
+	config := multipartConfigResponse{}
+
+	err := c.doAPI(ctx, http.MethodGet, fmt.Sprintf("%s/api/v1/uploads/config", c.URL), nil, nil, &config)
+	if err != nil {
+		var statusErr *StatusError
+		if errors.As(err, &statusErr) && statusErr.NotFound() {
+			// older services don't have the endpoint at all, which just means multipart isn't supported
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	return config.Supported, nil
+}
+
+// uploadMultipart uploads filename to path in parts, persisting progress so that a later attempt to upload the same
+// file to the same path resumes where this one stopped.
+func (c Client) uploadMultipart(ctx context.Context, path string, filename string, size int64, checksums Checksums) error {
+	partSize := c.Multipart.PartSize
+	if partSize <= 0 || partSize%MultipartPartSizeUnit != 0 {
+		return fmt.Errorf("invalid multipart part size %d, which must be a positive multiple of %d bytes (1 MiB)", partSize, MultipartPartSizeUnit)
+	}
+
+	repoKey, repoPath := splitRepoPath(path)
+	if repoPath == "" {
+		return fmt.Errorf("unable to determine repository for path %s", path)
+	}
+
+	statePath, err := c.multipartStatePath(path, checksums, partSize)
+	if err != nil {
+		return err
+	}
+
+	state, err := loadMultipartState(statePath)
+	if err != nil {
+		return err
+	}
+	resumed := state != nil
+
+	if state == nil {
+		query := url.Values{}
+		query.Set("repoKey", repoKey)
+		query.Set("repoPath", repoPath)
+		query.Set("partSizeMB", fmt.Sprintf("%d", partSize/MultipartPartSizeUnit))
+		query.Set("fileSize", fmt.Sprintf("%d", size))
+		query.Set("sha1", checksums.SHA1)
+
+		created := multipartCreateResponse{}
+		if err := c.doAPI(ctx, http.MethodPost, fmt.Sprintf("%s/api/v1/uploads/create?%s", c.URL, query.Encode()), nil, nil, &created); err != nil {
+			return fmt.Errorf("unable to create multipart upload for %s: %s", path, err)
+		}
+
+		state = &multipartState{
+			Token:     created.Token,
+			PartSize:  partSize,
+			Completed: map[int]bool{},
+		}
+		if err := state.save(statePath); err != nil {
+			return err
+		}
+	}
+
+	if err := c.uploadParts(ctx, filename, size, state, statePath); err != nil {
+		var statusErr *StatusError
+		if resumed && errors.As(err, &statusErr) && statusErr.ClientError() {
+			// the service no longer recognizes the saved upload, most likely because it expired, so start over
+			if err := os.Remove(statePath); err != nil {
+				return fmt.Errorf("unable to remove multipart upload state %s: %s", statePath, err)
+			}
+
+			return c.uploadMultipart(ctx, path, filename, size, checksums)
+		}
+
+		return fmt.Errorf("failure uploading parts of %s to %s, progress saved to %s: %s", filename, path, statePath, err)
+	}
+
+	if err := c.completeMultipart(ctx, state.Token, checksums); err != nil {
+		return fmt.Errorf("unable to complete multipart upload of %s to %s: %s", filename, path, err)
+	}
+
+	if err := os.Remove(statePath); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("unable to remove multipart upload state %s: %s", statePath, err)
+	}
+
+	return nil
+}
+
+// uploadParts uploads every part not already marked as completed in state, saving state after each completed part.
+func (c Client) uploadParts(ctx context.Context, filename string, size int64, state *multipartState, statePath string) error {
+	file, err := os.Open(filename)
+	if err != nil {
+		return fmt.Errorf("unable to read file %s: %s", filename, err)
+	}
+	defer file.Close()
+
+	partCount := int((size + state.PartSize - 1) / state.PartSize)
+	pending := make(chan int, partCount)
+	for partNumber := 1; partNumber <= partCount; partNumber++ {
+		if !state.Completed[partNumber] {
+			pending <- partNumber
+		}
+	}
+	close(pending)
+
+	concurrency := c.Multipart.Concurrency
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		firstErr error
+	)
+
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for partNumber := range pending {
+				if ctx.Err() != nil {
+					return
+				}
+
+				offset := int64(partNumber-1) * state.PartSize
+				length := state.PartSize
+				if offset+length > size {
+					length = size - offset
+				}
+
+				var err error
+				for attempt := 1; attempt <= multipartPartAttempts; attempt++ {
+					err = c.uploadPart(withAttempt(ctx, attempt), state.Token, partNumber, file, offset, length)
+					if err == nil || !retryable(err) || ctx.Err() != nil {
+						break
+					}
+				}
+
+				mu.Lock()
+				if err == nil {
+					state.Completed[partNumber] = true
+					err = state.save(statePath)
+				}
+				if err != nil && firstErr == nil {
+					firstErr = err
+					cancel()
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	return firstErr
+}
+
+// uploadPart uploads a single part of file to the URL the service provides for it.
+func (c Client) uploadPart(ctx context.Context, token string, partNumber int, file *os.File, offset int64, length int64) error {
+	header := http.Header{}
+	header.Set(multipartTokenHeader, token)
+
+	partURL := multipartURLResponse{}
+	if err := c.doAPI(ctx, http.MethodPost, fmt.Sprintf("%s/api/v1/uploads/urlPart?partNumber=%d", c.URL, partNumber), header, nil, &partURL); err != nil {
+		return fmt.Errorf("unable to get URL for part %d: %w", partNumber, err)
+	}
+
+	// part URLs are pre-signed, so no authentication is added to this request
+	request, err := http.NewRequestWithContext(ctx, http.MethodPut, partURL.URL, io.NewSectionReader(file, offset, length))
+	if err != nil {
+		return fmt.Errorf("unable to create PUT request for part %d: %s", partNumber, err)
+	}
+	request.ContentLength = length
+
+	response, err := c.doTransfer(request)
+	if err != nil {
+		return fmt.Errorf("unable to perform PUT request for part %d: %s", partNumber, err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		return fmt.Errorf("unable to upload part %d: %w", partNumber, newStatusError(http.MethodPut, request.URL.Redacted(), response))
+	}
+
+	return nil
+}
+
+// completeMultipart tells the service all parts have been uploaded, and waits for it to assemble them, for at most
+// multipartCompleteTimeout.
+func (c Client) completeMultipart(ctx context.Context, token string, checksums Checksums) error {
+	header := http.Header{}
+	header.Set(multipartTokenHeader, token)
+
+	completeURL := fmt.Sprintf("%s/api/v1/uploads/complete?sha1=%s", c.URL, url.QueryEscape(checksums.SHA1))
+	if err := c.doAPI(ctx, http.MethodPost, completeURL, header, nil, nil); err != nil {
+		return err
+	}
+
+	timeout := time.NewTimer(multipartCompleteTimeout)
+	defer timeout.Stop()
+
+	for {
+		status := multipartStatusResponse{}
+		if err := c.doAPI(ctx, http.MethodPost, fmt.Sprintf("%s/api/v1/uploads/status", c.URL), header, nil, &status); err != nil {
+			return err
+		}
+
+		switch strings.ToUpper(status.Status) {
+		case "FINISHED", "COMPLETED":
+			return nil
+		case "ABORTED", "FAILED":
+			return fmt.Errorf("multipart upload %s: %s", strings.ToLower(status.Status), status.Error)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timeout.C:
+			return fmt.Errorf("timed out after %s waiting for multipart upload to be assembled, last status %q", multipartCompleteTimeout, status.Status)
+		case <-time.After(multipartPollInterval):
+		}
+	}
+}
+
+// retryable returns true if err may succeed when attempted again. Only client errors, such as bad credentials or an
+// expired upload, are considered permanent.
+func retryable(err error) bool {
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		return !statusErr.ClientError()
+	}
+
+	return true
+}
+
+// multipartStatePath returns the path of the file used to persist progress of uploading a file with the given
+// checksums to path. Any change to the destination, contents, or part size results in a different state file.
+func (c Client) multipartStatePath(path string, checksums Checksums, partSize int64) (string, error) {
+	stateDir := c.Multipart.StateDir
+	if stateDir == "" {
+		cacheDir, err := os.UserCacheDir()
+		if err != nil {
+			cacheDir = os.TempDir()
+		}
+		stateDir = filepath.Join(cacheDir, "terraform-provider-artifacts", "multipart")
+	}
+
+	if err := os.MkdirAll(stateDir, 0700); err != nil {
+		return "", fmt.Errorf("unable to create multipart state directory %s: %s", stateDir, err)
+	}
+
+	key := sha256.Sum256([]byte(fmt.Sprintf("%s/%s:%s:%d", c.URL, path, checksums.SHA256, partSize)))
+
+	return filepath.Join(stateDir, fmt.Sprintf("%x.json", key)), nil
+}
+
+// loadMultipartState returns the multipartState stored at statePath, or nil if there is none.
+func loadMultipartState(statePath string) (*multipartState, error) {
+	data, err := ioutil.ReadFile(statePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("unable to read multipart upload state %s: %s", statePath, err)
+	}
+
+	state := &multipartState{}
+	if err := json.Unmarshal(data, state); err != nil {
+		// a corrupt state file can't be resumed, so start over
+		return nil, nil
+	}
+	if state.Completed == nil {
+		state.Completed = map[int]bool{}
+	}
+
+	return state, nil
+}
+
+// save writes the multipartState to statePath.
+func (s *multipartState) save(statePath string) error {
+	data, err := json.Marshal(s)
+	if err != nil {
+		return fmt.Errorf("unable to serialize multipart upload state: %s", err)
+	}
+
+	if err := ioutil.WriteFile(statePath, data, 0600); err != nil {
+		return fmt.Errorf("unable to write multipart upload state %s: %s", statePath, err)
+	}
+
+	return nil
+}
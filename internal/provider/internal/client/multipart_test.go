@@ -0,0 +1,190 @@
+// Copyright 2021 Splunk, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeMultipartService implements enough of the multipart upload API to exercise uploadMultipart.
+type fakeMultipartService struct {
+	mu       sync.Mutex
+	creates  int
+	parts    map[int]string
+	attempts []int
+	failPart int
+	failures int
+	complete bool
+	// partSizeMB is the part size the upload was created with
+	partSizeMB string
+	// status is the status reported for the completed upload, which defaults to FINISHED
+	status string
+}
+
+func (f *fakeMultipartService) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	switch {
+	case r.URL.Path == "/api/v1/uploads/config":
+		fmt.Fprint(w, `{"supported": true}`)
+	case r.URL.Path == "/api/v1/uploads/create":
+		f.creates++
+		f.partSizeMB = r.URL.Query().Get("partSizeMB")
+		fmt.Fprint(w, `{"token": "token"}`)
+	case r.URL.Path == "/api/v1/uploads/urlPart":
+		fmt.Fprintf(w, `{"url": "http://%s/part/%s"}`, r.Host, r.URL.Query().Get("partNumber"))
+	case strings.HasPrefix(r.URL.Path, "/part/"):
+		var partNumber int
+		fmt.Sscanf(strings.TrimPrefix(r.URL.Path, "/part/"), "%d", &partNumber)
+		f.attempts = append(f.attempts, partNumber)
+		if partNumber == f.failPart && f.failures > 0 {
+			f.failures--
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		body, _ := ioutil.ReadAll(r.Body)
+		f.parts[partNumber] = string(body)
+	case r.URL.Path == "/api/v1/uploads/complete":
+		f.complete = true
+	case r.URL.Path == "/api/v1/uploads/status":
+		status := f.status
+		if status == "" {
+			status = "FINISHED"
+		}
+		fmt.Fprintf(w, `{"status": %q}`, status)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func TestUploadMultipartResumes(t *testing.T) {
+	service := &fakeMultipartService{parts: map[int]string{}, failPart: 3, failures: multipartPartAttempts}
+	server := httptest.NewServer(service)
+	defer server.Close()
+
+	dir := t.TempDir()
+	filename := filepath.Join(dir, "artifact.bin")
+	content := bytes.Repeat([]byte("0123456789abcdef"), 7*MultipartPartSizeUnit/32)
+	if err := ioutil.WriteFile(filename, content, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	c := Client{
+		URL: server.URL,
+		Multipart: MultipartOptions{
+			Threshold:   1,
+			PartSize:    MultipartPartSizeUnit,
+			Concurrency: 1,
+			StateDir:    filepath.Join(dir, "state"),
+		},
+	}
+
+	if err := c.Upload(context.Background(), "repo/artifact.bin", filename, nil); err == nil {
+		t.Fatal("expected first upload to fail")
+	}
+
+	if err := c.Upload(context.Background(), "repo/artifact.bin", filename, nil); err != nil {
+		t.Fatalf("unexpected error resuming upload: %s", err)
+	}
+
+	if service.creates != 1 {
+		t.Errorf("expected a single multipart upload to be created, got %d", service.creates)
+	}
+
+	if !reflect.DeepEqual(service.attempts, []int{1, 2, 3, 3, 3, 3, 4}) {
+		t.Errorf("unexpected part upload attempts: %v", service.attempts)
+	}
+
+	if service.partSizeMB != "1" {
+		t.Errorf("expected upload to be created with 1 MiB parts, got %s", service.partSizeMB)
+	}
+
+	for partNumber := 1; partNumber <= 4; partNumber++ {
+		offset := (partNumber - 1) * MultipartPartSizeUnit
+		end := offset + MultipartPartSizeUnit
+		if end > len(content) {
+			end = len(content)
+		}
+
+		if service.parts[partNumber] != string(content[offset:end]) {
+			t.Errorf("unexpected content of part %d, with length %d", partNumber, len(service.parts[partNumber]))
+		}
+	}
+
+	if !service.complete {
+		t.Error("expected multipart upload to be completed")
+	}
+
+	stateFiles, _ := filepath.Glob(filepath.Join(dir, "state", "*.json"))
+	if len(stateFiles) != 0 {
+		t.Errorf("expected state to be removed after completion, found %v", stateFiles)
+	}
+}
+
+func TestUploadMultipartInvalidPartSize(t *testing.T) {
+	service := &fakeMultipartService{parts: map[int]string{}}
+	server := httptest.NewServer(service)
+	defer server.Close()
+
+	filename := filepath.Join(t.TempDir(), "artifact.bin")
+	if err := ioutil.WriteFile(filename, []byte("0123456789abcd"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	c := Client{
+		URL: server.URL,
+		Multipart: MultipartOptions{
+			Threshold: 1,
+			PartSize:  MultipartPartSizeUnit + 1,
+			StateDir:  t.TempDir(),
+		},
+	}
+
+	if err := c.Upload(context.Background(), "repo/artifact.bin", filename, nil); err == nil || !strings.Contains(err.Error(), "invalid multipart part size") {
+		t.Fatalf("expected invalid part size error, got %v", err)
+	}
+
+	if service.creates != 0 {
+		t.Errorf("expected no multipart upload to be created, got %d", service.creates)
+	}
+}
+
+func TestCompleteMultipartTimeout(t *testing.T) {
+	defer func(interval, timeout time.Duration) {
+		multipartPollInterval, multipartCompleteTimeout = interval, timeout
+	}(multipartPollInterval, multipartCompleteTimeout)
+	multipartPollInterval, multipartCompleteTimeout = time.Millisecond, 20*time.Millisecond
+
+	service := &fakeMultipartService{parts: map[int]string{}, status: "IN_PROGRESS"}
+	server := httptest.NewServer(service)
+	defer server.Close()
+
+	err := Client{URL: server.URL}.completeMultipart(context.Background(), "token", Checksums{SHA1: "sha1"})
+	if err == nil || !strings.Contains(err.Error(), "timed out") {
+		t.Fatalf("expected timeout error, got %v", err)
+	}
+}
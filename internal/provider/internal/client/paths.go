@@ -0,0 +1,116 @@
+// Copyright 2021 Splunk, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// pathVarRegex matches a {name} token in a path template.
+var pathVarRegex = regexp.MustCompile(`\{([^{}]*)\}`)
+
+// repositoryKeyRegex matches valid repository keys.
+var repositoryKeyRegex = regexp.MustCompile(`^[A-Za-z0-9_.\-]{1,64}$`)
+
+// JoinPath joins path elements with slashes. Leading and trailing slashes of each element are ignored, but empty
+// segments within an element (double slashes), and "." or ".." segments, are rejected so that a joined path can't
+// escape its base. Empty elements are skipped.
+func JoinPath(elements ...string) (string, error) {
+	segments := []string{}
+
+	for _, element := range elements {
+		trimmed := strings.TrimSuffix(strings.TrimPrefix(element, "/"), "/")
+		if trimmed == "" {
+			continue
+		}
+
+		for _, segment := range strings.Split(trimmed, "/") {
+			switch segment {
+			case "":
+				return "", fmt.Errorf("path %q must not contain double slashes", element)
+			case ".", "..":
+				return "", fmt.Errorf("path %q must not contain %q segments", element, segment)
+			}
+
+			segments = append(segments, segment)
+		}
+	}
+
+	return strings.Join(segments, "/"), nil
+}
+
+// ExpandPath replaces each {name} token in template with the value of name in vars. Unknown names are an error.
+func ExpandPath(template string, vars map[string]string) (string, error) {
+	missing := map[string]bool{}
+
+	expanded := pathVarRegex.ReplaceAllStringFunc(template, func(token string) string {
+		name := token[1 : len(token)-1]
+
+		value, ok := vars[name]
+		if !ok {
+			missing[name] = true
+			return token
+		}
+
+		return value
+	})
+
+	if len(missing) > 0 {
+		names := make([]string, 0, len(missing))
+		for name := range missing {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		return "", fmt.Errorf("path %q uses undefined path variables: %s", template, strings.Join(names, ", "))
+	}
+
+	return expanded, nil
+}
+
+// ResolvePath returns the repository-relative path for path, after expanding its {name} tokens and joining it to the
+// client's BasePath. Tokens are expanded from vars, falling back to the client's PathVars.
+func (c Client) ResolvePath(path string, vars map[string]string) (string, error) {
+	mergedVars := make(map[string]string, len(c.PathVars)+len(vars))
+	for name, value := range c.PathVars {
+		mergedVars[name] = value
+	}
+	for name, value := range vars {
+		mergedVars[name] = value
+	}
+
+	expanded, err := ExpandPath(path, mergedVars)
+	if err != nil {
+		return "", err
+	}
+
+	if strings.Trim(expanded, "/") == "" {
+		return "", fmt.Errorf("path %q resolves to an empty path", path)
+	}
+
+	return JoinPath(c.BasePath, expanded)
+}
+
+// ValidateRepositoryKey returns an error if key can't be the key of a repository, such as the first segment of a path.
+func ValidateRepositoryKey(key string) error {
+	if !repositoryKeyRegex.MatchString(key) || key == "." || key == ".." {
+		return fmt.Errorf("repository key %q must be 1 to 64 letters, digits, '_', '-', or '.'", key)
+	}
+
+	return nil
+}
@@ -0,0 +1,80 @@
+// Copyright 2021 Splunk, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestResolvePath(t *testing.T) {
+	c := Client{
+		BasePath: "sas-binary/team/",
+		PathVars: map[string]string{"os": "linux", "arch": "amd64"},
+	}
+
+	tests := []struct {
+		path    string
+		vars    map[string]string
+		want    string
+		wantErr bool
+	}{
+		{path: "tool.tgz", want: "sas-binary/team/tool.tgz"},
+		{path: "/tool.tgz", want: "sas-binary/team/tool.tgz"},
+		{path: "{version}/tool-{os}-{arch}.tgz", vars: map[string]string{"version": "1.0.0"}, want: "sas-binary/team/1.0.0/tool-linux-amd64.tgz"},
+		{path: "tool-{os}.tgz", vars: map[string]string{"os": "darwin"}, want: "sas-binary/team/tool-darwin.tgz"},
+		{path: "{version}/tool.tgz", wantErr: true},
+		{path: "../other-team/tool.tgz", wantErr: true},
+		{path: "{version}/tool.tgz", vars: map[string]string{"version": ".."}, wantErr: true},
+		{path: "sub//tool.tgz", wantErr: true},
+		{path: "./tool.tgz", wantErr: true},
+		{path: "", wantErr: true},
+	}
+
+	for _, test := range tests {
+		got, err := c.ResolvePath(test.path, test.vars)
+		if test.wantErr {
+			if err == nil {
+				t.Errorf("ResolvePath(%q, %v): expected error, got %q", test.path, test.vars, got)
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("ResolvePath(%q, %v): unexpected error: %s", test.path, test.vars, err)
+			continue
+		}
+
+		if got != test.want {
+			t.Errorf("ResolvePath(%q, %v): got %q, want %q", test.path, test.vars, got, test.want)
+		}
+	}
+}
+
+func TestValidateRepositoryKey(t *testing.T) {
+	for key, valid := range map[string]bool{
+		"sas-binary":            true,
+		"libs_release.2":        true,
+		"":                      false,
+		"..":                    false,
+		"my repo":               false,
+		"{repo}":                false,
+		strings.Repeat("a", 65): false,
+	} {
+		if err := ValidateRepositoryKey(key); (err == nil) != valid {
+			t.Errorf("ValidateRepositoryKey(%q): got error %v, want valid %t", key, err, valid)
+		}
+	}
+}
@@ -0,0 +1,118 @@
+// Copyright 2021 Splunk, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// Properties are the properties of a file, each of which may have multiple values.
+type Properties map[string][]string
+
+// matrixParams returns the properties encoded as matrix parameters, to be appended to a file's URL when deploying it.
+// Properties with multiple values repeat the key for each value.
+func (p Properties) matrixParams() string {
+	builder := strings.Builder{}
+
+	for _, key := range p.sortedKeys() {
+		for _, value := range p[key] {
+			builder.WriteString(";")
+			builder.WriteString(escapeMatrixParam(key))
+			builder.WriteString("=")
+			builder.WriteString(escapeMatrixParam(value))
+		}
+	}
+
+	return builder.String()
+}
+
+// String returns the properties in the format of the properties API, with special characters escaped.
+func (p Properties) String() string {
+	escape := strings.NewReplacer(`\`, `\\`, ",", `\,`, ";", `\;`, "=", `\=`, "|", `\|`).Replace
+
+	properties := make([]string, 0, len(p))
+	for _, key := range p.sortedKeys() {
+		values := make([]string, 0, len(p[key]))
+		for _, value := range p[key] {
+			values = append(values, escape(value))
+		}
+		properties = append(properties, escape(key)+"="+strings.Join(values, ","))
+	}
+
+	return strings.Join(properties, ";")
+}
+
+// sortedKeys returns the property keys in sorted order, so that encoded properties are stable.
+func (p Properties) sortedKeys() []string {
+	keys := make([]string, 0, len(p))
+	for key := range p {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+// escapeMatrixParam escapes a matrix parameter key or value, including the characters that separate parameters.
+func escapeMatrixParam(value string) string {
+	return strings.NewReplacer(";", "%3B", "=", "%3D", ",", "%2C").Replace(url.PathEscape(value))
+}
+
+// FileProperties returns the properties of a file at a path relative to the client's URL. A file without properties
+// has an empty Properties.
+func (c Client) FileProperties(ctx context.Context, path string) (Properties, error) {
+	url := fmt.Sprintf("%s/api/storage/%s?properties", c.URL, path)
+
+	response := struct {
+		Properties Properties
+	}{}
+	if err := c.doAPI(ctx, http.MethodGet, url, nil, nil, &response); err != nil {
+		// files without properties are reported as not found
+		var statusErr *StatusError
+		if errors.As(err, &statusErr) && statusErr.NotFound() {
+			return Properties{}, nil
+		}
+
+		return nil, fmt.Errorf("unable to read properties of %s: %s", path, err)
+	}
+
+	if response.Properties == nil {
+		return Properties{}, nil
+	}
+
+	return response.Properties, nil
+}
+
+// SetProperties sets properties on a file at a path relative to the client's URL, replacing the values of any
+// properties it already has with the same keys. Setting no properties does nothing.
+func (c Client) SetProperties(ctx context.Context, path string, properties Properties) error {
+	if len(properties) == 0 {
+		return nil
+	}
+
+	url := fmt.Sprintf("%s/api/storage/%s?properties=%s&recursive=0", c.URL, path, url.QueryEscape(properties.String()))
+
+	if err := c.doAPI(ctx, http.MethodPut, url, nil, nil, nil); err != nil {
+		return fmt.Errorf("unable to set properties of %s: %s", path, err)
+	}
+
+	return nil
+}
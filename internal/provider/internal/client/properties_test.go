@@ -0,0 +1,50 @@
+// Copyright 2021 Splunk, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"testing"
+)
+
+func TestPropertiesMatrixParams(t *testing.T) {
+	properties := Properties{
+		"version":  {"1.0.0"},
+		"os":       {"linux", "darwin"},
+		"pointer":  {"repo/a b;c=d,e"},
+		"no.value": {},
+	}
+
+	want := ";os=linux;os=darwin;pointer=repo%2Fa%20b%3Bc%3Dd%2Ce;version=1.0.0"
+	if got := properties.matrixParams(); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	if got := Properties(nil).matrixParams(); got != "" {
+		t.Errorf("expected no matrix params for no properties, got %q", got)
+	}
+}
+
+func TestPropertiesString(t *testing.T) {
+	properties := Properties{
+		"deb.distribution": {"focal", "jammy"},
+		"deb.component":    {"main"},
+		"note":             {`a=b;c,d|e\f`},
+	}
+
+	want := `deb.component=main;deb.distribution=focal,jammy;note=a\=b\;c\,d\|e\\f`
+	if got := properties.String(); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,98 @@
+// Copyright 2021 Splunk, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+type aqlResponse struct {
+	Results []struct {
+		Repo string
+		Path string
+		Name string
+	}
+}
+
+// FilesWithProperty returns the paths, relative to the folder at path, of every file within the folder or its
+// subfolders that has property, with any value. It makes a single AQL search, rather than reading the properties of
+// each file.
+func (c Client) FilesWithProperty(ctx context.Context, path string, property string) (map[string]bool, error) {
+	folderPath := strings.Trim(path, "/")
+	repoKey, repoPath := splitRepoPath(folderPath)
+
+	criteria := map[string]interface{}{
+		"repo":         repoKey,
+		"type":         "file",
+		"@" + property: map[string]string{"$match": "*"},
+	}
+	if repoPath != "" {
+		criteria["$or"] = []interface{}{
+			map[string]string{"path": repoPath},
+			map[string]interface{}{"path": map[string]string{"$match": repoPath + "/*"}},
+		}
+	}
+
+	criteriaJSON, err := json.Marshal(criteria)
+	if err != nil {
+		return nil, fmt.Errorf("unable to serialize search for files with property %s: %s", property, err)
+	}
+	query := fmt.Sprintf(`items.find(%s).include("repo","path","name")`, criteriaJSON)
+
+	url := fmt.Sprintf("%s/api/search/aql", c.URL)
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, url, strings.NewReader(query))
+	if err != nil {
+		return nil, fmt.Errorf("unable to create POST request for url %s: %s", url, err)
+	}
+	request.Header.Set("Content-Type", "text/plain")
+
+	if err := c.setAuth(request); err != nil {
+		return nil, err
+	}
+
+	response, err := c.Do(request)
+	if err != nil {
+		return nil, fmt.Errorf("unable to perform POST request for %s: %s", url, err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unable to search for files with property %s in %s: %s", property, path, newStatusError(http.MethodPost, url, response))
+	}
+
+	result := aqlResponse{}
+	if err := json.NewDecoder(response.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("unable to deserialize JSON response from POST %s: %s", url, err)
+	}
+
+	files := map[string]bool{}
+	for _, item := range result.Results {
+		// files at the root of a repository have a path of "."
+		itemPath := item.Repo + "/" + item.Name
+		if item.Path != "." && item.Path != "" {
+			itemPath = item.Repo + "/" + item.Path + "/" + item.Name
+		}
+
+		if relativePath := strings.TrimPrefix(itemPath, folderPath+"/"); relativePath != itemPath {
+			files[relativePath] = true
+		}
+	}
+
+	return files, nil
+}
@@ -0,0 +1,62 @@
+// Copyright 2021 Splunk, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestFilesWithProperty(t *testing.T) {
+	var query string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/api/search/aql" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		body, _ := ioutil.ReadAll(r.Body)
+		query = string(body)
+		fmt.Fprint(w, `{"results": [
+  {"repo": "repo", "path": "tools/tool/1.0.0", "name": "tool.tgz"},
+  {"repo": "repo", "path": "tools/tool", "name": "latest.txt"},
+  {"repo": "repo", "path": "tools/tool-extra/1.0.0", "name": "tool.tgz"}
+]}`)
+	}))
+	defer server.Close()
+
+	files, err := Client{URL: server.URL}.FilesWithProperty(context.Background(), "repo/tools/tool", "retention.pinned")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, expected := range []string{`"repo":"repo"`, `"@retention.pinned":{"$match":"*"}`, `{"path":"tools/tool"}`, `{"path":{"$match":"tools/tool/*"}}`} {
+		if !strings.Contains(query, expected) {
+			t.Errorf("expected query to contain %s, got %s", expected, query)
+		}
+	}
+
+	// a sibling folder sharing the folder's name as a prefix isn't within it
+	expected := map[string]bool{"1.0.0/tool.tgz": true, "latest.txt": true}
+	if !reflect.DeepEqual(files, expected) {
+		t.Errorf("expected %v, got %v", expected, files)
+	}
+}
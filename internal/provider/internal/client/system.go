@@ -0,0 +1,144 @@
+// Copyright 2021 Splunk, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+)
+
+// SystemInfo describes the service and the user the client authenticates as.
+type SystemInfo struct {
+	Version  string `json:"version"`
+	Revision string `json:"revision"`
+	// User is the name of the authenticated user, or empty if it can't be determined.
+	User string `json:"-"`
+}
+
+// AuthError is returned by Authenticate when the service rejects the client's credentials.
+type AuthError struct {
+	*StatusError
+}
+
+// Ping checks that the service is reachable, without authenticating.
+func (c Client) Ping(ctx context.Context) error {
+	url := fmt.Sprintf("%s/api/system/ping", c.URL)
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return fmt.Errorf("unable to create GET request for url %s: %s", url, err)
+	}
+
+	response, err := c.Do(request)
+	if err != nil {
+		return fmt.Errorf("unable to reach %s: %s", url, err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return newStatusError(http.MethodGet, url, response)
+	}
+
+	body, _ := ioutil.ReadAll(io.LimitReader(response.Body, maxErrorBodySize))
+	if strings.TrimSpace(string(body)) != "OK" {
+		return fmt.Errorf("unexpected response from %s, which may not be an Artifactory service: %q", url, strings.TrimSpace(string(body)))
+	}
+
+	return nil
+}
+
+// Authenticate checks that the service accepts the client's credentials, returning an *AuthError if it doesn't.
+// It returns the name of the authenticated user, when it can be determined.
+func (c Client) Authenticate(ctx context.Context) (string, error) {
+	err := c.doAPI(ctx, http.MethodGet, fmt.Sprintf("%s/api/security/apiKey", c.URL), nil, nil, nil)
+
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) && statusErr.NotFound() {
+		// services that no longer support API keys don't have the endpoint, but listing repositories also requires
+		// valid credentials, if any are given
+		err = c.doAPI(ctx, http.MethodGet, fmt.Sprintf("%s/api/repositories", c.URL), nil, nil, nil)
+	}
+
+	if errors.As(err, &statusErr) && (statusErr.StatusCode == http.StatusUnauthorized || statusErr.StatusCode == http.StatusForbidden) {
+		return "", &AuthError{StatusError: statusErr}
+	}
+	if err != nil {
+		return "", err
+	}
+
+	return c.userName(ctx), nil
+}
+
+// SystemInfo returns the service's version, and the user the client authenticates as.
+func (c Client) SystemInfo(ctx context.Context) (SystemInfo, error) {
+	info := SystemInfo{}
+
+	if err := c.doAPI(ctx, http.MethodGet, fmt.Sprintf("%s/api/system/version", c.URL), nil, nil, &info); err != nil {
+		return info, fmt.Errorf("unable to read service version: %s", err)
+	}
+
+	info.User = c.userName(ctx)
+
+	return info, nil
+}
+
+// userName returns the name of the user the client authenticates as, from its username or from the subject of a
+// JWT access token. It returns an empty string if neither is available.
+func (c Client) userName(ctx context.Context) string {
+	credentials := Credentials{Username: c.Username, Token: c.Token}
+	if credentials.Empty() && c.CredentialHelper != nil {
+		credentials, _ = c.CredentialHelper.Credentials(ctx)
+	}
+
+	if credentials.Username != "" {
+		return credentials.Username
+	}
+
+	return tokenSubjectUser(credentials.Token)
+}
+
+// tokenSubjectUser returns the user name from the subject of a JWT access token, which has the form
+// "<service id>/users/<name>". The token's signature isn't verified, as the name is only informational.
+func tokenSubjectUser(token string) string {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return ""
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return ""
+	}
+
+	claims := struct {
+		Subject string `json:"sub"`
+	}{}
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return ""
+	}
+
+	if index := strings.LastIndex(claims.Subject, "/users/"); index >= 0 {
+		return claims.Subject[index+len("/users/"):]
+	}
+
+	return ""
+}
@@ -0,0 +1,136 @@
+// Copyright 2021 Splunk, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// maxSignedURLSize is the largest signed URL response that is read.
+const maxSignedURLSize = 8192
+
+// AccessToken is a token issued by the service's Access API.
+type AccessToken struct {
+	// ID identifies the token to revoke it, without the token itself.
+	ID    string
+	Token string
+	Scope string
+	// ExpiresAt is the zero time for tokens that don't expire.
+	ExpiresAt time.Time
+}
+
+// SignedURL returns a URL that downloads the file at path, relative to the client's URL, without credentials until
+// validFor has passed. Signed URLs are only supported by some editions of the service, which otherwise respond with
+// a *StatusError that is Unsupported.
+func (c Client) SignedURL(ctx context.Context, path string, validFor time.Duration) (string, error) {
+	url := fmt.Sprintf("%s/api/signed/url", c.URL)
+
+	body, err := json.Marshal(map[string]interface{}{
+		"repo_path":      path,
+		"valid_for_secs": int64(validFor / time.Second),
+	})
+	if err != nil {
+		return "", fmt.Errorf("unable to serialize JSON body for POST %s: %s", url, err)
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return "", fmt.Errorf("unable to create POST request for url %s: %s", url, err)
+	}
+	request.Header.Set("Content-Type", "application/json")
+
+	if err := c.setAuth(request); err != nil {
+		return "", err
+	}
+
+	response, err := c.Do(request)
+	if err != nil {
+		return "", fmt.Errorf("unable to perform POST request for %s: %s", url, err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return "", newStatusError(http.MethodPost, url, response)
+	}
+
+	// the signed URL is returned as plain text
+	signedURL, err := ioutil.ReadAll(io.LimitReader(response.Body, maxSignedURLSize))
+	if err != nil {
+		return "", fmt.Errorf("unable to read response from POST %s: %s", url, err)
+	}
+
+	return strings.TrimSpace(string(signedURL)), nil
+}
+
+// CreateAccessToken returns a new access token with scope, such as "applied-permissions/user", that expires after
+// validFor. Tokens are issued by the Access API, which is served next to the client's URL, outside of its
+// "/artifactory" path.
+func (c Client) CreateAccessToken(ctx context.Context, scope string, validFor time.Duration, description string) (AccessToken, error) {
+	url := c.accessTokensURL()
+
+	request := struct {
+		Scope       string `json:"scope"`
+		ExpiresIn   int64  `json:"expires_in"`
+		Description string `json:"description,omitempty"`
+	}{
+		Scope:       scope,
+		ExpiresIn:   int64(validFor / time.Second),
+		Description: description,
+	}
+	result := struct {
+		TokenID     string `json:"token_id"`
+		AccessToken string `json:"access_token"`
+		Scope       string `json:"scope"`
+		ExpiresIn   int64  `json:"expires_in"`
+	}{}
+
+	issuedAt := time.Now()
+	if err := c.doAPI(ctx, http.MethodPost, url, nil, request, &result); err != nil {
+		return AccessToken{}, fmt.Errorf("unable to create access token: %s", err)
+	}
+
+	token := AccessToken{ID: result.TokenID, Token: result.AccessToken, Scope: result.Scope}
+	// tokens that don't expire have no expires_in
+	if result.ExpiresIn > 0 {
+		token.ExpiresAt = issuedAt.Add(time.Duration(result.ExpiresIn) * time.Second)
+	}
+
+	return token, nil
+}
+
+// RevokeAccessToken revokes the access token identified by id, as returned by CreateAccessToken.
+func (c Client) RevokeAccessToken(ctx context.Context, id string) error {
+	url := fmt.Sprintf("%s/%s", c.accessTokensURL(), id)
+
+	if err := c.doAPI(ctx, http.MethodDelete, url, nil, nil, nil); err != nil {
+		return fmt.Errorf("unable to revoke access token %s: %s", id, err)
+	}
+
+	return nil
+}
+
+// accessTokensURL returns the URL of the Access API's tokens, which is served next to the client's URL, outside of its
+// "/artifactory" path.
+func (c Client) accessTokensURL() string {
+	return fmt.Sprintf("%s/access/api/v1/tokens", strings.TrimSuffix(strings.TrimSuffix(c.URL, "/"), "/artifactory"))
+}
@@ -0,0 +1,146 @@
+// Copyright 2021 Splunk, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+	"io"
+	"sync"
+	"time"
+)
+
+// maxThrottledRead is the largest read performed at once by a throttled stream, keeping its rate smooth.
+const maxThrottledRead = 32 * 1024
+
+// TransferLimiter limits the number of concurrent file transfers, and optionally their combined bandwidth. A single
+// TransferLimiter is shared by every copy of a Client, so limits apply across all resources using the provider.
+// Metadata requests, such as those made by Checksums, aren't limited.
+type TransferLimiter struct {
+	slots          chan struct{}
+	bytesPerSecond int64
+
+	mu   sync.Mutex
+	next time.Time
+}
+
+// NewTransferLimiter returns a TransferLimiter allowing maxConcurrent transfers at once, with a combined rate of
+// bytesPerSecond. Zero for either value means that dimension is unlimited.
+func NewTransferLimiter(maxConcurrent int, bytesPerSecond int64) *TransferLimiter {
+	limiter := &TransferLimiter{bytesPerSecond: bytesPerSecond}
+	if maxConcurrent > 0 {
+		limiter.slots = make(chan struct{}, maxConcurrent)
+	}
+
+	return limiter
+}
+
+// acquire waits for a transfer slot to be available, or for ctx to be done.
+func (l *TransferLimiter) acquire(ctx context.Context) error {
+	if l == nil || l.slots == nil {
+		return nil
+	}
+
+	select {
+	case l.slots <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// release frees a transfer slot obtained with acquire.
+func (l *TransferLimiter) release() {
+	if l == nil || l.slots == nil {
+		return
+	}
+
+	<-l.slots
+}
+
+// reader returns an io.Reader that reads from r no faster than the limiter's bandwidth allows.
+func (l *TransferLimiter) reader(ctx context.Context, r io.Reader) io.Reader {
+	if l == nil || l.bytesPerSecond <= 0 {
+		return r
+	}
+
+	return &throttledReader{ctx: ctx, limiter: l, reader: r}
+}
+
+// wait reserves time for n bytes at the limiter's rate, and sleeps until the reserved time begins.
+func (l *TransferLimiter) wait(ctx context.Context, n int) error {
+	l.mu.Lock()
+	now := time.Now()
+	if l.next.Before(now) {
+		l.next = now
+	}
+	delay := l.next.Sub(now)
+	l.next = l.next.Add(time.Duration(float64(n) / float64(l.bytesPerSecond) * float64(time.Second)))
+	l.mu.Unlock()
+
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// throttledReader is an io.Reader whose reads are paced by a TransferLimiter.
+type throttledReader struct {
+	ctx     context.Context
+	limiter *TransferLimiter
+	reader  io.Reader
+}
+
+// Read implements io.Reader.
+func (r *throttledReader) Read(p []byte) (int, error) {
+	if len(p) > maxThrottledRead {
+		p = p[:maxThrottledRead]
+	}
+
+	n, err := r.reader.Read(p)
+	if n > 0 {
+		if waitErr := r.limiter.wait(r.ctx, n); waitErr != nil {
+			return n, waitErr
+		}
+	}
+
+	return n, err
+}
+
+// throttledReadCloser wraps a throttled stream, closing the underlying stream and running onClose, if set, on Close.
+type throttledReadCloser struct {
+	io.Reader
+	closer  io.Closer
+	once    sync.Once
+	onClose func()
+}
+
+// Close implements io.Closer.
+func (r *throttledReadCloser) Close() error {
+	err := r.closer.Close()
+	if r.onClose != nil {
+		r.once.Do(r.onClose)
+	}
+
+	return err
+}
@@ -0,0 +1,160 @@
+// Copyright 2021 Splunk, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestTransferLimiterConcurrency(t *testing.T) {
+	limiter := NewTransferLimiter(2, 0)
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		if err := limiter.acquire(ctx); err != nil {
+			t.Fatalf("unexpected error acquiring slot %d: %s", i, err)
+		}
+	}
+
+	timeoutCtx, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+	defer cancel()
+	if err := limiter.acquire(timeoutCtx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected acquiring a third slot to wait until the context is done, got %v", err)
+	}
+	if len(limiter.slots) != 2 {
+		t.Errorf("expected a cancelled acquire not to take a slot, %d slots in use", len(limiter.slots))
+	}
+
+	limiter.release()
+	if err := limiter.acquire(ctx); err != nil {
+		t.Fatalf("unexpected error acquiring released slot: %s", err)
+	}
+}
+
+func TestTransferLimiterUnlimited(t *testing.T) {
+	var limiter *TransferLimiter
+	if err := limiter.acquire(context.Background()); err != nil {
+		t.Fatalf("unexpected error from nil limiter: %s", err)
+	}
+	limiter.release()
+
+	reader := bytes.NewReader(nil)
+	if NewTransferLimiter(0, 0).reader(context.Background(), reader) != reader {
+		t.Error("expected reader without a bandwidth limit to be unwrapped")
+	}
+}
+
+func TestTransferLimiterPacing(t *testing.T) {
+	const bytesPerSecond = 6 * maxThrottledRead
+	limiter := NewTransferLimiter(0, bytesPerSecond)
+
+	// the first read isn't delayed, the second waits for the first's share of a second, and so on
+	reader := limiter.reader(context.Background(), bytes.NewReader(make([]byte, 3*maxThrottledRead)))
+	buffer := make([]byte, maxThrottledRead)
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		if n, err := reader.Read(buffer); n != maxThrottledRead || err != nil {
+			t.Fatalf("unexpected result of read %d: %d, %v", i, n, err)
+		}
+	}
+	elapsed := time.Since(start)
+	if expected := 2 * time.Second / 6; elapsed < expected-10*time.Millisecond || elapsed > expected+time.Second {
+		t.Errorf("expected reading to take about %s, took %s", expected, elapsed)
+	}
+}
+
+func TestTransferLimiterPacingCancelled(t *testing.T) {
+	limiter := NewTransferLimiter(0, 1)
+	ctx, cancel := context.WithCancel(context.Background())
+	reader := limiter.reader(ctx, bytes.NewReader(make([]byte, 2*maxThrottledRead)))
+
+	if _, err := reader.Read(make([]byte, maxThrottledRead)); err != nil {
+		t.Fatalf("unexpected error from first read: %s", err)
+	}
+
+	time.AfterFunc(20*time.Millisecond, cancel)
+	if _, err := reader.Read(make([]byte, maxThrottledRead)); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected paced read to stop when the context is cancelled, got %v", err)
+	}
+}
+
+func TestDoTransferReleasesSlot(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, "content")
+	}))
+	defer server.Close()
+
+	c := Client{URL: server.URL, Transfers: NewTransferLimiter(1, 0)}
+
+	request, err := http.NewRequest(http.MethodGet, c.FileURL("repo/file"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	response, err := c.doTransfer(request)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(c.Transfers.slots) != 1 {
+		t.Fatal("expected the slot to be held until the response body is closed")
+	}
+
+	// closing more than once must not release another transfer's slot
+	response.Body.Close()
+	response.Body.Close()
+	if len(c.Transfers.slots) != 0 {
+		t.Fatalf("expected the slot to be released when the response body is closed, %d slots in use", len(c.Transfers.slots))
+	}
+
+	// a failed request releases its slot immediately
+	server.Close()
+	request, err = http.NewRequest(http.MethodGet, c.FileURL("repo/file"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.doTransfer(request); err == nil {
+		t.Fatal("expected request to a closed server to fail")
+	}
+	if len(c.Transfers.slots) != 0 {
+		t.Errorf("expected the slot to be released when the request fails, %d slots in use", len(c.Transfers.slots))
+	}
+}
+
+func TestDoTransferCancelledWaiting(t *testing.T) {
+	c := Client{URL: "http://127.0.0.1:0", Transfers: NewTransferLimiter(1, 0)}
+	if err := c.Transfers.acquire(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, c.FileURL("repo/file"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	time.AfterFunc(20*time.Millisecond, cancel)
+	if _, err := c.doTransfer(request); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected waiting for a slot to stop when the context is cancelled, got %v", err)
+	}
+	if len(c.Transfers.slots) != 1 {
+		t.Errorf("expected only the held slot to be in use, %d slots in use", len(c.Transfers.slots))
+	}
+}
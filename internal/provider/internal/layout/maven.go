@@ -0,0 +1,358 @@
+// Copyright 2021 Splunk, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package layout computes the paths and metadata files of artifacts in the layouts of package formats.
+package layout
+
+import (
+	"encoding/xml"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// MavenMetadataFile is the name of Maven metadata files, in both artifact and version directories.
+	MavenMetadataFile = "maven-metadata.xml"
+
+	mavenSnapshotSuffix    = "-SNAPSHOT"
+	mavenTimestampLayout   = "20060102.150405"
+	mavenLastUpdatedLayout = "20060102150405"
+)
+
+// mavenCoordinateRegex matches valid Maven group IDs, artifact IDs, versions, classifiers, and extensions.
+var mavenCoordinateRegex = regexp.MustCompile(`^[A-Za-z0-9_.\-]+$`)
+
+// MavenCoordinates identify a Maven artifact.
+type MavenCoordinates struct {
+	GroupID    string
+	ArtifactID string
+	Version    string
+	Classifier string
+	Extension  string
+}
+
+// Validate returns an error if any of the coordinates can't be used in a path.
+func (m MavenCoordinates) Validate() error {
+	for name, value := range map[string]string{
+		"group ID":    m.GroupID,
+		"artifact ID": m.ArtifactID,
+		"version":     m.Version,
+		"extension":   m.Extension,
+	} {
+		if !mavenCoordinateRegex.MatchString(value) {
+			return fmt.Errorf("%s %q must be non-empty, and contain only letters, digits, '_', '-', or '.'", name, value)
+		}
+	}
+
+	if m.Classifier != "" && !mavenCoordinateRegex.MatchString(m.Classifier) {
+		return fmt.Errorf("classifier %q must contain only letters, digits, '_', '-', or '.'", m.Classifier)
+	}
+
+	return nil
+}
+
+// IsSnapshot returns true if the version is a SNAPSHOT version, whose files are deployed with unique timestamped
+// versions.
+func (m MavenCoordinates) IsSnapshot() bool {
+	return strings.HasSuffix(m.Version, mavenSnapshotSuffix)
+}
+
+// ArtifactDir returns the directory of all versions of the artifact, relative to the repository.
+func (m MavenCoordinates) ArtifactDir() string {
+	return strings.ReplaceAll(m.GroupID, ".", "/") + "/" + m.ArtifactID
+}
+
+// VersionDir returns the directory of the artifact's version, relative to the repository.
+func (m MavenCoordinates) VersionDir() string {
+	return m.ArtifactDir() + "/" + m.Version
+}
+
+// Path returns the path of the artifact's file, relative to the repository. fileVersion is the version in the file's
+// name, which is the version itself for releases, or a timestamped version for snapshots.
+func (m MavenCoordinates) Path(fileVersion string) string {
+	fileName := m.ArtifactID + "-" + fileVersion
+	if m.Classifier != "" {
+		fileName += "-" + m.Classifier
+	}
+
+	return m.VersionDir() + "/" + fileName + "." + m.Extension
+}
+
+// POMPath returns the path of the artifact's POM, relative to the repository.
+func (m MavenCoordinates) POMPath(fileVersion string) string {
+	return MavenCoordinates{GroupID: m.GroupID, ArtifactID: m.ArtifactID, Version: m.Version, Extension: "pom"}.Path(fileVersion)
+}
+
+// SnapshotVersion returns the timestamped version of a snapshot's files for a build deployed at timestamp.
+func (m MavenCoordinates) SnapshotVersion(timestamp time.Time, buildNumber int) string {
+	return fmt.Sprintf("%s-%s-%d", strings.TrimSuffix(m.Version, mavenSnapshotSuffix), timestamp.UTC().Format(mavenTimestampLayout), buildNumber)
+}
+
+// mavenPOM is a minimal POM.
+type mavenPOM struct {
+	XMLName           xml.Name `xml:"project"`
+	XMLNS             string   `xml:"xmlns,attr"`
+	XMLNSXSI          string   `xml:"xmlns:xsi,attr"`
+	XSISchemaLocation string   `xml:"xsi:schemaLocation,attr"`
+	ModelVersion      string   `xml:"modelVersion"`
+	GroupID           string   `xml:"groupId"`
+	ArtifactID        string   `xml:"artifactId"`
+	Version           string   `xml:"version"`
+	Packaging         string   `xml:"packaging"`
+}
+
+// POM returns a minimal POM for the artifact, without dependencies.
+func (m MavenCoordinates) POM(packaging string) ([]byte, error) {
+	return marshalXML(mavenPOM{
+		XMLNS:             "http://maven.apache.org/POM/4.0.0",
+		XMLNSXSI:          "http://www.w3.org/2001/XMLSchema-instance",
+		XSISchemaLocation: "http://maven.apache.org/POM/4.0.0 http://maven.apache.org/xsd/maven-4.0.0.xsd",
+		ModelVersion:      "4.0.0",
+		GroupID:           m.GroupID,
+		ArtifactID:        m.ArtifactID,
+		Version:           m.Version,
+		Packaging:         packaging,
+	})
+}
+
+// mavenMetadata is a Maven metadata file, of either an artifact or a snapshot version.
+type mavenMetadata struct {
+	XMLName      xml.Name        `xml:"metadata"`
+	ModelVersion string          `xml:"modelVersion,attr,omitempty"`
+	GroupID      string          `xml:"groupId"`
+	ArtifactID   string          `xml:"artifactId"`
+	Version      string          `xml:"version,omitempty"`
+	Versioning   mavenVersioning `xml:"versioning"`
+}
+
+type mavenVersioning struct {
+	Latest   string         `xml:"latest,omitempty"`
+	Release  string         `xml:"release,omitempty"`
+	Snapshot *mavenSnapshot `xml:"snapshot,omitempty"`
+	// nested elements are pointers, as encoding/xml can't omit empty "a>b" elements
+	Versions         *mavenVersions         `xml:"versions,omitempty"`
+	LastUpdated      string                 `xml:"lastUpdated"`
+	SnapshotVersions *mavenSnapshotVersions `xml:"snapshotVersions,omitempty"`
+}
+
+type mavenVersions struct {
+	Versions []string `xml:"version"`
+}
+
+type mavenSnapshotVersions struct {
+	SnapshotVersions []mavenSnapshotVersion `xml:"snapshotVersion"`
+}
+
+type mavenSnapshot struct {
+	Timestamp   string `xml:"timestamp"`
+	BuildNumber int    `xml:"buildNumber"`
+}
+
+type mavenSnapshotVersion struct {
+	Classifier string `xml:"classifier,omitempty"`
+	Extension  string `xml:"extension"`
+	Value      string `xml:"value"`
+	Updated    string `xml:"updated"`
+}
+
+// parseMavenMetadata parses existing metadata, which may be empty if there is none.
+func parseMavenMetadata(existing []byte) (mavenMetadata, error) {
+	metadata := mavenMetadata{}
+
+	if len(existing) > 0 {
+		if err := xml.Unmarshal(existing, &metadata); err != nil {
+			return metadata, fmt.Errorf("unable to parse %s: %s", MavenMetadataFile, err)
+		}
+	}
+
+	return metadata, nil
+}
+
+// NextSnapshotBuildNumber returns the build number of the next snapshot build, following the build in the version's
+// existing metadata, which may be empty if there is none.
+func NextSnapshotBuildNumber(existing []byte) (int, error) {
+	metadata, err := parseMavenMetadata(existing)
+	if err != nil {
+		return 0, err
+	}
+
+	if metadata.Versioning.Snapshot == nil {
+		return 1, nil
+	}
+
+	return metadata.Versioning.Snapshot.BuildNumber + 1, nil
+}
+
+// UpdateArtifactMetadata returns the artifact's metadata, listing all of its versions, after adding its version to
+// the existing metadata, which may be empty if there is none.
+func (m MavenCoordinates) UpdateArtifactMetadata(existing []byte, updated time.Time) ([]byte, error) {
+	metadata, err := parseMavenMetadata(existing)
+	if err != nil {
+		return nil, err
+	}
+
+	metadata.GroupID = m.GroupID
+	metadata.ArtifactID = m.ArtifactID
+	metadata.Versioning.LastUpdated = updated.UTC().Format(mavenLastUpdatedLayout)
+	metadata.Versioning.Latest = m.Version
+	if !m.IsSnapshot() {
+		metadata.Versioning.Release = m.Version
+	}
+
+	if metadata.Versioning.Versions == nil {
+		metadata.Versioning.Versions = &mavenVersions{}
+	}
+	found := false
+	for _, version := range metadata.Versioning.Versions.Versions {
+		found = found || version == m.Version
+	}
+	if !found {
+		metadata.Versioning.Versions.Versions = append(metadata.Versioning.Versions.Versions, m.Version)
+	}
+
+	return marshalXML(metadata)
+}
+
+// UpdateSnapshotMetadata returns the snapshot version's metadata after recording a build of the artifact's file, and
+// of its POM if withPOM is true, in the existing metadata, which may be empty if there is none.
+func (m MavenCoordinates) UpdateSnapshotMetadata(existing []byte, timestamp time.Time, buildNumber int, withPOM bool) ([]byte, error) {
+	metadata, err := parseMavenMetadata(existing)
+	if err != nil {
+		return nil, err
+	}
+
+	updated := timestamp.UTC().Format(mavenLastUpdatedLayout)
+	fileVersion := m.SnapshotVersion(timestamp, buildNumber)
+
+	metadata.ModelVersion = "1.1.0"
+	metadata.GroupID = m.GroupID
+	metadata.ArtifactID = m.ArtifactID
+	metadata.Version = m.Version
+	metadata.Versioning.LastUpdated = updated
+	metadata.Versioning.Snapshot = &mavenSnapshot{
+		Timestamp:   timestamp.UTC().Format(mavenTimestampLayout),
+		BuildNumber: buildNumber,
+	}
+
+	files := []mavenSnapshotVersion{{Classifier: m.Classifier, Extension: m.Extension, Value: fileVersion, Updated: updated}}
+	if withPOM {
+		files = append(files, mavenSnapshotVersion{Extension: "pom", Value: fileVersion, Updated: updated})
+	}
+
+	if metadata.Versioning.SnapshotVersions == nil {
+		metadata.Versioning.SnapshotVersions = &mavenSnapshotVersions{}
+	}
+	snapshotVersions := metadata.Versioning.SnapshotVersions.SnapshotVersions
+
+	// each classifier and extension lists only its latest build
+	for _, file := range files {
+		replaced := false
+		for i, existing := range snapshotVersions {
+			if existing.Classifier == file.Classifier && existing.Extension == file.Extension {
+				snapshotVersions[i] = file
+				replaced = true
+			}
+		}
+		if !replaced {
+			snapshotVersions = append(snapshotVersions, file)
+		}
+	}
+	sort.SliceStable(snapshotVersions, func(i, j int) bool {
+		a, b := snapshotVersions[i], snapshotVersions[j]
+		if a.Extension != b.Extension {
+			return a.Extension < b.Extension
+		}
+		return a.Classifier < b.Classifier
+	})
+	metadata.Versioning.SnapshotVersions.SnapshotVersions = snapshotVersions
+
+	return marshalXML(metadata)
+}
+
+// RemoveArtifactVersion returns the artifact's metadata after removing its version from the existing metadata, or
+// nil if no versions remain.
+func (m MavenCoordinates) RemoveArtifactVersion(existing []byte, updated time.Time) ([]byte, error) {
+	metadata, err := parseMavenMetadata(existing)
+	if err != nil {
+		return nil, err
+	}
+
+	var versions []string
+	if metadata.Versioning.Versions != nil {
+		for _, version := range metadata.Versioning.Versions.Versions {
+			if version != m.Version {
+				versions = append(versions, version)
+			}
+		}
+	}
+	if len(versions) == 0 {
+		return nil, nil
+	}
+
+	// versions are listed in the order they were deployed
+	metadata.Versioning.Versions.Versions = versions
+	metadata.Versioning.LastUpdated = updated.UTC().Format(mavenLastUpdatedLayout)
+	metadata.Versioning.Latest = versions[len(versions)-1]
+	metadata.Versioning.Release = ""
+	for _, version := range versions {
+		if !strings.HasSuffix(version, mavenSnapshotSuffix) {
+			metadata.Versioning.Release = version
+		}
+	}
+
+	return marshalXML(metadata)
+}
+
+// RemoveSnapshotBuild returns the snapshot version's metadata after removing the build of the artifact's file, and
+// of a POM, with fileVersion from the existing metadata.
+func (m MavenCoordinates) RemoveSnapshotBuild(existing []byte, fileVersion string, updated time.Time) ([]byte, error) {
+	metadata, err := parseMavenMetadata(existing)
+	if err != nil {
+		return nil, err
+	}
+
+	if metadata.Versioning.SnapshotVersions == nil {
+		return existing, nil
+	}
+
+	var snapshotVersions []mavenSnapshotVersion
+	for _, file := range metadata.Versioning.SnapshotVersions.SnapshotVersions {
+		isArtifact := file.Classifier == m.Classifier && file.Extension == m.Extension
+		isPOM := file.Classifier == "" && file.Extension == "pom"
+		if file.Value == fileVersion && (isArtifact || isPOM) {
+			continue
+		}
+		snapshotVersions = append(snapshotVersions, file)
+	}
+	metadata.Versioning.SnapshotVersions = nil
+	if len(snapshotVersions) > 0 {
+		metadata.Versioning.SnapshotVersions = &mavenSnapshotVersions{SnapshotVersions: snapshotVersions}
+	}
+	metadata.Versioning.LastUpdated = updated.UTC().Format(mavenLastUpdatedLayout)
+
+	return marshalXML(metadata)
+}
+
+// marshalXML returns v as an indented XML document.
+func marshalXML(v interface{}) ([]byte, error) {
+	data, err := xml.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]byte(xml.Header), append(data, '\n')...), nil
+}
@@ -0,0 +1,232 @@
+// Copyright 2021 Splunk, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package layout
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMavenPaths(t *testing.T) {
+	coordinates := MavenCoordinates{GroupID: "com.splunk.tools", ArtifactID: "tool", Version: "1.0.0", Classifier: "linux", Extension: "tar.gz"}
+
+	if err := coordinates.Validate(); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if got, want := coordinates.Path(coordinates.Version), "com/splunk/tools/tool/1.0.0/tool-1.0.0-linux.tar.gz"; got != want {
+		t.Errorf("Path got %q, want %q", got, want)
+	}
+	if got, want := coordinates.POMPath(coordinates.Version), "com/splunk/tools/tool/1.0.0/tool-1.0.0.pom"; got != want {
+		t.Errorf("POMPath got %q, want %q", got, want)
+	}
+
+	if err := (MavenCoordinates{GroupID: "com/splunk", ArtifactID: "tool", Version: "1.0.0", Extension: "jar"}).Validate(); err == nil {
+		t.Error("expected error for group ID containing a slash")
+	}
+}
+
+func TestMavenSnapshotMetadata(t *testing.T) {
+	coordinates := MavenCoordinates{GroupID: "com.splunk", ArtifactID: "tool", Version: "1.1.0-SNAPSHOT", Extension: "jar"}
+	timestamp := time.Date(2021, 12, 1, 12, 34, 56, 0, time.UTC)
+
+	if !coordinates.IsSnapshot() {
+		t.Fatal("expected a snapshot")
+	}
+
+	buildNumber, err := NextSnapshotBuildNumber(nil)
+	if err != nil || buildNumber != 1 {
+		t.Fatalf("expected first build number 1, got %d, %v", buildNumber, err)
+	}
+
+	first, err := coordinates.UpdateSnapshotMetadata(nil, timestamp, buildNumber, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buildNumber, err = NextSnapshotBuildNumber(first)
+	if err != nil || buildNumber != 2 {
+		t.Fatalf("expected next build number 2, got %d, %v", buildNumber, err)
+	}
+
+	sources := coordinates
+	sources.Classifier = "sources"
+	second, err := sources.UpdateSnapshotMetadata(first, timestamp.Add(time.Minute), buildNumber, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := coordinates.SnapshotVersion(timestamp, 1), "1.1.0-20211201.123456-1"; got != want {
+		t.Errorf("SnapshotVersion got %q, want %q", got, want)
+	}
+
+	want := `<?xml version="1.0" encoding="UTF-8"?>
+<metadata modelVersion="1.1.0">
+  <groupId>com.splunk</groupId>
+  <artifactId>tool</artifactId>
+  <version>1.1.0-SNAPSHOT</version>
+  <versioning>
+    <snapshot>
+      <timestamp>20211201.123556</timestamp>
+      <buildNumber>2</buildNumber>
+    </snapshot>
+    <lastUpdated>20211201123556</lastUpdated>
+    <snapshotVersions>
+      <snapshotVersion>
+        <extension>jar</extension>
+        <value>1.1.0-20211201.123456-1</value>
+        <updated>20211201123456</updated>
+      </snapshotVersion>
+      <snapshotVersion>
+        <classifier>sources</classifier>
+        <extension>jar</extension>
+        <value>1.1.0-20211201.123556-2</value>
+        <updated>20211201123556</updated>
+      </snapshotVersion>
+      <snapshotVersion>
+        <extension>pom</extension>
+        <value>1.1.0-20211201.123456-1</value>
+        <updated>20211201123456</updated>
+      </snapshotVersion>
+    </snapshotVersions>
+  </versioning>
+</metadata>
+`
+	if string(second) != want {
+		t.Errorf("got metadata:\n%s\nwant:\n%s", second, want)
+	}
+}
+
+func TestMavenArtifactMetadata(t *testing.T) {
+	updated := time.Date(2021, 12, 1, 12, 34, 56, 0, time.UTC)
+
+	metadata, err := MavenCoordinates{GroupID: "com.splunk", ArtifactID: "tool", Version: "1.0.0", Extension: "jar"}.UpdateArtifactMetadata(nil, updated)
+	if err != nil {
+		t.Fatal(err)
+	}
+	metadata, err = MavenCoordinates{GroupID: "com.splunk", ArtifactID: "tool", Version: "1.1.0-SNAPSHOT", Extension: "jar"}.UpdateArtifactMetadata(metadata, updated)
+	if err != nil {
+		t.Fatal(err)
+	}
+	metadata, err = MavenCoordinates{GroupID: "com.splunk", ArtifactID: "tool", Version: "1.0.0", Extension: "jar"}.UpdateArtifactMetadata(metadata, updated)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, expected := range []string{
+		"<latest>1.0.0</latest>",
+		"<release>1.0.0</release>",
+		"<versions>\n      <version>1.0.0</version>\n      <version>1.1.0-SNAPSHOT</version>\n    </versions>",
+	} {
+		if !strings.Contains(string(metadata), expected) {
+			t.Errorf("expected metadata to contain %q, got:\n%s", expected, metadata)
+		}
+	}
+}
+
+func TestMavenPOM(t *testing.T) {
+	pom, err := MavenCoordinates{GroupID: "com.splunk", ArtifactID: "tool", Version: "1.0.0", Extension: "jar"}.POM("jar")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, expected := range []string{
+		`<project xmlns="http://maven.apache.org/POM/4.0.0" xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance"`,
+		"<groupId>com.splunk</groupId>",
+		"<packaging>jar</packaging>",
+	} {
+		if !strings.Contains(string(pom), expected) {
+			t.Errorf("expected POM to contain %q, got:\n%s", expected, pom)
+		}
+	}
+}
+
+func TestMavenRemoveArtifactVersion(t *testing.T) {
+	updated := time.Date(2021, 12, 1, 12, 34, 56, 0, time.UTC)
+
+	var metadata []byte
+	var err error
+	for _, version := range []string{"1.0.0", "1.1.0", "1.2.0-SNAPSHOT"} {
+		if metadata, err = (MavenCoordinates{GroupID: "com.splunk", ArtifactID: "tool", Version: version}).UpdateArtifactMetadata(metadata, updated); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	metadata, err = MavenCoordinates{GroupID: "com.splunk", ArtifactID: "tool", Version: "1.1.0"}.RemoveArtifactVersion(metadata, updated)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, expected := range []string{
+		"<latest>1.2.0-SNAPSHOT</latest>",
+		"<release>1.0.0</release>",
+		"<versions>\n      <version>1.0.0</version>\n      <version>1.2.0-SNAPSHOT</version>\n    </versions>",
+	} {
+		if !strings.Contains(string(metadata), expected) {
+			t.Errorf("expected metadata to contain %q, got:\n%s", expected, metadata)
+		}
+	}
+
+	metadata, err = MavenCoordinates{GroupID: "com.splunk", ArtifactID: "tool", Version: "1.0.0"}.RemoveArtifactVersion(metadata, updated)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(metadata), "<release>") {
+		t.Errorf("expected no release, got:\n%s", metadata)
+	}
+
+	metadata, err = MavenCoordinates{GroupID: "com.splunk", ArtifactID: "tool", Version: "1.2.0-SNAPSHOT"}.RemoveArtifactVersion(metadata, updated)
+	if err != nil || metadata != nil {
+		t.Errorf("expected no metadata once no versions remain, got %q, %v", metadata, err)
+	}
+}
+
+func TestMavenRemoveSnapshotBuild(t *testing.T) {
+	coordinates := MavenCoordinates{GroupID: "com.splunk", ArtifactID: "tool", Version: "1.1.0-SNAPSHOT", Extension: "jar"}
+	sources := coordinates
+	sources.Classifier = "sources"
+	timestamp := time.Date(2021, 12, 1, 12, 34, 56, 0, time.UTC)
+
+	metadata, err := coordinates.UpdateSnapshotMetadata(nil, timestamp, 1, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if metadata, err = sources.UpdateSnapshotMetadata(metadata, timestamp.Add(time.Minute), 2, false); err != nil {
+		t.Fatal(err)
+	}
+
+	// removes the jar and POM of build 1, but not the sources of build 2
+	metadata, err = coordinates.RemoveSnapshotBuild(metadata, "1.1.0-20211201.123456-1", timestamp.Add(2*time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for expected, present := range map[string]bool{
+		"<value>1.1.0-20211201.123456-1</value>":    false,
+		"<value>1.1.0-20211201.123556-2</value>":    true,
+		"<buildNumber>2</buildNumber>":              true,
+		"<lastUpdated>20211201123656</lastUpdated>": true,
+	} {
+		if strings.Contains(string(metadata), expected) != present {
+			t.Errorf("expected metadata containing %q to be %t, got:\n%s", expected, present, metadata)
+		}
+	}
+
+	metadata, err = sources.RemoveSnapshotBuild(metadata, "1.1.0-20211201.123556-2", timestamp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(metadata), "snapshotVersions") {
+		t.Errorf("expected no snapshot versions, got:\n%s", metadata)
+	}
+}
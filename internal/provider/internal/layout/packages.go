@@ -0,0 +1,42 @@
+// Copyright 2021 Splunk, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package layout
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// pypiNameSeparatorRegex matches the runs of separators that are equivalent in PyPI project names.
+var pypiNameSeparatorRegex = regexp.MustCompile(`[-_.]+`)
+
+// NPMPath returns the path of an npm package's tarball, relative to the repository. Scoped packages, named like
+// `@scope/name`, keep their scope in both the directory and file names.
+func NPMPath(name string, version string) string {
+	return fmt.Sprintf("%s/-/%s-%s.tgz", name, name, version)
+}
+
+// NormalizePyPIName returns the normalized form of a PyPI project name, which is lowercase with runs of separators
+// replaced by a single hyphen.
+func NormalizePyPIName(name string) string {
+	return strings.ToLower(pypiNameSeparatorRegex.ReplaceAllString(name, "-"))
+}
+
+// PyPIPath returns the path of a PyPI distribution file, relative to the repository. Distributions are grouped by
+// normalized project name and version, and keep their file names, which describe their compatibility.
+func PyPIPath(name string, version string, fileName string) string {
+	return fmt.Sprintf("%s/%s/%s", NormalizePyPIName(name), version, fileName)
+}
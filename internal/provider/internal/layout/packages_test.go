@@ -0,0 +1,44 @@
+// Copyright 2021 Splunk, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package layout
+
+import (
+	"testing"
+)
+
+func TestNPMPath(t *testing.T) {
+	tests := []struct {
+		name    string
+		version string
+		want    string
+	}{
+		{name: "example", version: "1.2.3", want: "example/-/example-1.2.3.tgz"},
+		{name: "@splunk/example", version: "1.2.3-beta.1", want: "@splunk/example/-/@splunk/example-1.2.3-beta.1.tgz"},
+	}
+
+	for _, test := range tests {
+		if got := NPMPath(test.name, test.version); got != test.want {
+			t.Errorf("NPMPath(%q, %q): got %q, want %q", test.name, test.version, got, test.want)
+		}
+	}
+}
+
+func TestPyPIPath(t *testing.T) {
+	got := PyPIPath("Example_Package.Name", "1.2.3", "Example_Package.Name-1.2.3-py3-none-any.whl")
+	want := "example-package-name/1.2.3/Example_Package.Name-1.2.3-py3-none-any.whl"
+	if got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
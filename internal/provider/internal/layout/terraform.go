@@ -0,0 +1,158 @@
+// Copyright 2021 Splunk, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package layout
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+var (
+	// terraformNameRegex matches valid Terraform registry namespaces, names, systems, operating systems, and
+	// architectures.
+	terraformNameRegex = regexp.MustCompile(`^[0-9A-Za-z][0-9A-Za-z_-]*$`)
+	// terraformVersionRegex matches semantic versions, which the registry protocols require.
+	terraformVersionRegex = regexp.MustCompile(`^[0-9]+\.[0-9]+\.[0-9]+(-[0-9A-Za-z.-]+)?(\+[0-9A-Za-z.-]+)?$`)
+)
+
+// validateTerraformNames returns an error if any of the names, given as pairs of description and value, isn't valid
+// in the Terraform registry protocols.
+func validateTerraformNames(names ...string) error {
+	for i := 0; i+1 < len(names); i += 2 {
+		if !terraformNameRegex.MatchString(names[i+1]) {
+			return fmt.Errorf("%s %q must be non-empty, start with a letter or digit, and contain only letters, digits, '_', or '-'", names[i], names[i+1])
+		}
+	}
+
+	return nil
+}
+
+// validateTerraformVersion returns an error if version isn't a semantic version.
+func validateTerraformVersion(version string) error {
+	if !terraformVersionRegex.MatchString(version) {
+		return fmt.Errorf("version %q must be a semantic version, such as 1.2.3, without a leading 'v'", version)
+	}
+
+	return nil
+}
+
+// TerraformModule identifies a version of a module in a Terraform registry.
+type TerraformModule struct {
+	Namespace string
+	Name      string
+	// System is the remote system the module targets, such as aws, which the registry calls its provider.
+	System  string
+	Version string
+}
+
+// Validate returns an error if any of the module's coordinates aren't valid in the module registry protocol.
+func (m TerraformModule) Validate() error {
+	if err := validateTerraformNames("namespace", m.Namespace, "name", m.Name, "system", m.System); err != nil {
+		return err
+	}
+
+	return validateTerraformVersion(m.Version)
+}
+
+// Path returns the path of the module's package, with file extension extension, relative to the repository.
+func (m TerraformModule) Path(extension string) string {
+	return fmt.Sprintf("%s/%s/%s/%s.%s", m.Namespace, m.Name, m.System, m.Version, extension)
+}
+
+// TerraformProvider identifies a version of a provider in a Terraform registry.
+type TerraformProvider struct {
+	Namespace string
+	// Type is the provider's name, such as aws.
+	Type    string
+	Version string
+}
+
+// TerraformPlatform is an operating system and architecture that a provider is built for.
+type TerraformPlatform struct {
+	OS   string
+	Arch string
+}
+
+// Validate returns an error if any of the provider's coordinates, or platforms, aren't valid in the provider registry
+// protocol.
+func (p TerraformProvider) Validate(platforms []TerraformPlatform) error {
+	if err := validateTerraformNames("namespace", p.Namespace, "type", p.Type); err != nil {
+		return err
+	}
+	if err := validateTerraformVersion(p.Version); err != nil {
+		return err
+	}
+
+	seen := map[TerraformPlatform]bool{}
+	for _, platform := range platforms {
+		if err := validateTerraformNames("os", platform.OS, "arch", platform.Arch); err != nil {
+			return err
+		}
+		if seen[platform] {
+			return fmt.Errorf("platform %s_%s is given more than once", platform.OS, platform.Arch)
+		}
+		seen[platform] = true
+	}
+
+	return nil
+}
+
+// Dir returns the directory of the provider's files, relative to the repository.
+func (p TerraformProvider) Dir() string {
+	return p.Namespace + "/" + p.Type
+}
+
+// fileNamePrefix returns the prefix of the names of all of the files of the provider's version.
+func (p TerraformProvider) fileNamePrefix() string {
+	return fmt.Sprintf("terraform-provider-%s_%s", p.Type, p.Version)
+}
+
+// PackageFileName returns the name of the provider's zip package for platform.
+func (p TerraformProvider) PackageFileName(platform TerraformPlatform) string {
+	return fmt.Sprintf("%s_%s_%s.zip", p.fileNamePrefix(), platform.OS, platform.Arch)
+}
+
+// PackagePath returns the path of the provider's zip package for platform, relative to the repository.
+func (p TerraformProvider) PackagePath(platform TerraformPlatform) string {
+	return p.Dir() + "/" + p.PackageFileName(platform)
+}
+
+// SHA256SumsPath returns the path of the provider's SHA256SUMS file, relative to the repository. Its detached
+// signature is at the same path with ".sig" appended.
+func (p TerraformProvider) SHA256SumsPath() string {
+	return p.Dir() + "/" + p.fileNamePrefix() + "_SHA256SUMS"
+}
+
+// SHA256Sums returns the content of the provider's SHA256SUMS file, listing the SHA256 of each of its packages by
+// file name, in the format of sha256sum. packageSHA256s are the hex-encoded SHA256s of the packages, by platform.
+func (p TerraformProvider) SHA256Sums(packageSHA256s map[TerraformPlatform]string) string {
+	sha256s := make(map[string]string, len(packageSHA256s))
+	fileNames := make([]string, 0, len(packageSHA256s))
+	for platform, sha256 := range packageSHA256s {
+		fileName := p.PackageFileName(platform)
+		sha256s[fileName] = sha256
+		fileNames = append(fileNames, fileName)
+	}
+	sort.Strings(fileNames)
+
+	builder := strings.Builder{}
+	for _, fileName := range fileNames {
+		fmt.Fprintf(&builder, "%s  %s\n", sha256s[fileName], fileName)
+	}
+
+	return builder.String()
+}
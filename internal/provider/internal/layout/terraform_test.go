@@ -0,0 +1,66 @@
+// Copyright 2021 Splunk, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package layout
+
+import (
+	"testing"
+)
+
+func TestTerraformModule(t *testing.T) {
+	module := TerraformModule{Namespace: "splunk", Name: "network", System: "aws", Version: "1.2.3"}
+	if err := module.Validate(); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if got, want := module.Path("tar.gz"), "splunk/network/aws/1.2.3.tar.gz"; got != want {
+		t.Errorf("got path %q, want %q", got, want)
+	}
+
+	for _, invalid := range []TerraformModule{
+		{Namespace: "splunk", Name: "network", System: "aws", Version: "v1.2.3"},
+		{Namespace: "splunk", Name: "network/vpc", System: "aws", Version: "1.2.3"},
+		{Namespace: "", Name: "network", System: "aws", Version: "1.2.3"},
+	} {
+		if err := invalid.Validate(); err == nil {
+			t.Errorf("expected error validating %+v", invalid)
+		}
+	}
+}
+
+func TestTerraformProvider(t *testing.T) {
+	provider := TerraformProvider{Namespace: "splunk", Type: "artifacts", Version: "1.2.3-rc.1"}
+	linux := TerraformPlatform{OS: "linux", Arch: "amd64"}
+	darwin := TerraformPlatform{OS: "darwin", Arch: "arm64"}
+
+	if err := provider.Validate([]TerraformPlatform{linux, darwin}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if err := provider.Validate([]TerraformPlatform{linux, linux}); err == nil {
+		t.Error("expected error validating a duplicated platform")
+	}
+
+	if got, want := provider.PackagePath(linux), "splunk/artifacts/terraform-provider-artifacts_1.2.3-rc.1_linux_amd64.zip"; got != want {
+		t.Errorf("got package path %q, want %q", got, want)
+	}
+	if got, want := provider.SHA256SumsPath(), "splunk/artifacts/terraform-provider-artifacts_1.2.3-rc.1_SHA256SUMS"; got != want {
+		t.Errorf("got SHA256SUMS path %q, want %q", got, want)
+	}
+
+	got := provider.SHA256Sums(map[TerraformPlatform]string{linux: "aaaa", darwin: "bbbb"})
+	want := "bbbb  terraform-provider-artifacts_1.2.3-rc.1_darwin_arm64.zip\n" +
+		"aaaa  terraform-provider-artifacts_1.2.3-rc.1_linux_amd64.zip\n"
+	if got != want {
+		t.Errorf("got SHA256SUMS %q, want %q", got, want)
+	}
+}
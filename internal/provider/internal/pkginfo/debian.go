@@ -0,0 +1,207 @@
+// Copyright 2021 Splunk, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package pkginfo reads the metadata embedded in package files, such as their name, version, and architecture.
+package pkginfo
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/ulikunitz/xz"
+)
+
+const (
+	arMagic      = "!<arch>\n"
+	arHeaderSize = 60
+)
+
+// DebianControl is the subset of a Debian package's control file needed to publish it.
+type DebianControl struct {
+	Package      string
+	Version      string
+	Architecture string
+	// Fields are all of the control file's fields, by name.
+	Fields map[string]string
+}
+
+// FileName returns the canonical file name of the package, without the version's epoch.
+func (c DebianControl) FileName() string {
+	version := c.Version
+	if index := strings.Index(version, ":"); index >= 0 {
+		version = version[index+1:]
+	}
+
+	return fmt.Sprintf("%s_%s_%s.deb", c.Package, version, c.Architecture)
+}
+
+// PoolPath returns the path of the package within a Debian repository's pool, for component.
+func (c DebianControl) PoolPath(component string) string {
+	// packages are grouped by their first letter, or by their first four letters for libraries
+	prefix := c.Package[:1]
+	if strings.HasPrefix(c.Package, "lib") && len(c.Package) > 3 {
+		prefix = c.Package[:4]
+	}
+
+	return path.Join("pool", component, prefix, c.Package, c.FileName())
+}
+
+// ReadDebian reads the control file of the Debian package at filename, which is an ar archive containing a
+// control.tar, which may be compressed with gzip, xz, or zstd.
+func ReadDebian(filename string) (DebianControl, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return DebianControl{}, fmt.Errorf("unable to read package %s: %s", filename, err)
+	}
+	defer file.Close()
+
+	control, err := readDebian(bufio.NewReader(file))
+	if err != nil {
+		return DebianControl{}, fmt.Errorf("unable to read control file of package %s: %s", filename, err)
+	}
+
+	return control, nil
+}
+
+// readDebian reads the control file from a Debian package.
+func readDebian(reader io.Reader) (DebianControl, error) {
+	magic := make([]byte, len(arMagic))
+	if _, err := io.ReadFull(reader, magic); err != nil || string(magic) != arMagic {
+		return DebianControl{}, fmt.Errorf("not an ar archive")
+	}
+
+	header := make([]byte, arHeaderSize)
+	for {
+		if _, err := io.ReadFull(reader, header); err != nil {
+			if err == io.EOF {
+				return DebianControl{}, fmt.Errorf("no control.tar member")
+			}
+			return DebianControl{}, err
+		}
+
+		// GNU ar terminates names with a slash
+		name := strings.TrimSuffix(strings.TrimSpace(string(header[0:16])), "/")
+		size, err := strconv.ParseInt(strings.TrimSpace(string(header[48:58])), 10, 64)
+		if err != nil {
+			return DebianControl{}, fmt.Errorf("invalid size of ar member %s", name)
+		}
+
+		member := io.LimitReader(reader, size)
+		if strings.HasPrefix(name, "control.tar") {
+			decompressed, err := decompress(name, member)
+			if err != nil {
+				return DebianControl{}, err
+			}
+
+			return readControlTar(decompressed)
+		}
+
+		// members are padded to an even size
+		if _, err := io.CopyN(io.Discard, reader, size+size%2); err != nil {
+			return DebianControl{}, err
+		}
+	}
+}
+
+// decompress returns a reader of the decompressed contents of an ar member, according to its name's extension.
+func decompress(name string, reader io.Reader) (io.Reader, error) {
+	switch path.Ext(name) {
+	case ".tar":
+		return reader, nil
+	case ".gz":
+		return gzip.NewReader(reader)
+	case ".xz":
+		return xz.NewReader(reader)
+	case ".zst":
+		decoder, err := zstd.NewReader(reader)
+		if err != nil {
+			return nil, err
+		}
+		return decoder.IOReadCloser(), nil
+	}
+
+	return nil, fmt.Errorf("unsupported compression of %s", name)
+}
+
+// readControlTar reads the control file from a control.tar.
+func readControlTar(reader io.Reader) (DebianControl, error) {
+	tarReader := tar.NewReader(reader)
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			return DebianControl{}, fmt.Errorf("no control file in control.tar")
+		}
+		if err != nil {
+			return DebianControl{}, err
+		}
+
+		if path.Clean(header.Name) == "control" {
+			return parseControl(tarReader)
+		}
+	}
+}
+
+// parseControl parses a control file, whose fields are "Name: value" lines, continued by lines beginning with
+// whitespace.
+func parseControl(reader io.Reader) (DebianControl, error) {
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return DebianControl{}, err
+	}
+
+	fields := map[string]string{}
+	current := ""
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		if strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t") {
+			if current != "" {
+				fields[current] += "\n" + strings.TrimSpace(line)
+			}
+			continue
+		}
+
+		name, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		current = strings.TrimSpace(name)
+		fields[current] = strings.TrimSpace(value)
+	}
+
+	control := DebianControl{
+		Package:      fields["Package"],
+		Version:      fields["Version"],
+		Architecture: fields["Architecture"],
+		Fields:       fields,
+	}
+
+	for name, value := range map[string]string{"Package": control.Package, "Version": control.Version, "Architecture": control.Architecture} {
+		if value == "" {
+			return control, fmt.Errorf("control file has no %s field", name)
+		}
+	}
+
+	return control, nil
+}
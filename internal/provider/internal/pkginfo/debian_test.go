@@ -0,0 +1,131 @@
+// Copyright 2021 Splunk, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pkginfo
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/ulikunitz/xz"
+)
+
+const testControl = `Package: libexample1
+Version: 1:1.2.3-1
+Architecture: amd64
+Maintainer: Example <example@example.com>
+Description: an example
+ with a continued description
+`
+
+// writeDebian writes a Debian package whose control.tar is compressed with compression, to a file in a new directory.
+func writeDebian(t *testing.T, compression string) string {
+	control := &bytes.Buffer{}
+	tarWriter := tar.NewWriter(control)
+	for name, content := range map[string]string{"./": "", "./control": testControl} {
+		header := &tar.Header{Name: name, Mode: 0644, Size: int64(len(content)), Typeflag: tar.TypeReg}
+		if content == "" {
+			header.Typeflag = tar.TypeDir
+		}
+		if err := tarWriter.WriteHeader(header); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := io.WriteString(tarWriter, content); err != nil {
+			t.Fatal(err)
+		}
+	}
+	tarWriter.Close()
+
+	compressed := &bytes.Buffer{}
+	var writer io.WriteCloser
+	var err error
+	switch compression {
+	case "gz":
+		writer = gzip.NewWriter(compressed)
+	case "xz":
+		writer, err = xz.NewWriter(compressed)
+	case "zst":
+		writer, err = zstd.NewWriter(compressed)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := io.Copy(writer, control); err != nil {
+		t.Fatal(err)
+	}
+	writer.Close()
+
+	deb := &bytes.Buffer{}
+	deb.WriteString(arMagic)
+	for _, member := range []struct {
+		name    string
+		content []byte
+	}{
+		{name: "debian-binary", content: []byte("2.0\n")},
+		{name: "control.tar." + compression, content: compressed.Bytes()},
+		{name: "data.tar.gz", content: []byte("not read")},
+	} {
+		fmt.Fprintf(deb, "%-16s%-12d%-6d%-6d%-8s%-10d\x60\n", member.name+"/", 0, 0, 0, "100644", len(member.content))
+		deb.Write(member.content)
+		if len(member.content)%2 == 1 {
+			deb.WriteString("\n")
+		}
+	}
+
+	filename := filepath.Join(t.TempDir(), "package.deb")
+	if err := os.WriteFile(filename, deb.Bytes(), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	return filename
+}
+
+func TestReadDebian(t *testing.T) {
+	for _, compression := range []string{"gz", "xz", "zst"} {
+		t.Run(compression, func(t *testing.T) {
+			control, err := ReadDebian(writeDebian(t, compression))
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if control.Package != "libexample1" || control.Version != "1:1.2.3-1" || control.Architecture != "amd64" {
+				t.Errorf("unexpected control %+v", control)
+			}
+			if got, want := control.Fields["Description"], "an example\nwith a continued description"; got != want {
+				t.Errorf("got description %q, want %q", got, want)
+			}
+			if got, want := control.PoolPath("main"), "pool/main/libe/libexample1/libexample1_1.2.3-1_amd64.deb"; got != want {
+				t.Errorf("got pool path %q, want %q", got, want)
+			}
+		})
+	}
+}
+
+func TestReadDebianNotAPackage(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "package.deb")
+	if err := os.WriteFile(filename, []byte("not a package"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := ReadDebian(filename); err == nil {
+		t.Error("expected error reading a file that isn't a package")
+	}
+}
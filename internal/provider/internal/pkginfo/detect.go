@@ -0,0 +1,427 @@
+// Copyright 2021 Splunk, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pkginfo
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/textproto"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// PackageType is a type of package, whose metadata identifies it and determines where it's published.
+type PackageType string
+
+const (
+	// AutoPackage detects the type of a package from its file name and contents.
+	AutoPackage  PackageType = "auto"
+	MavenPackage PackageType = "maven"
+	NPMPackage   PackageType = "npm"
+	PyPIPackage  PackageType = "pypi"
+	// GenericPackage is a file without package metadata.
+	GenericPackage PackageType = "generic"
+)
+
+// PackageTypes are the supported package types.
+var PackageTypes = []PackageType{AutoPackage, MavenPackage, NPMPackage, PyPIPackage, GenericPackage}
+
+// maxMetadataSize limits the size of metadata files read from packages.
+const maxMetadataSize = 1 << 20
+
+// errEntryFound stops iterating over the entries of an archive once the wanted entry is found.
+var errEntryFound = errors.New("entry found")
+
+// Package identifies a package by the coordinates in its metadata.
+type Package struct {
+	Type PackageType
+	// GroupID is only set for Maven packages.
+	GroupID string
+	Name    string
+	Version string
+}
+
+// DetectPackage reads the metadata of the package in filename, of packageType. Maven packages are read from a POM, or
+// from the pom.properties in a jar, war, ear, or aar. npm packages are read from the package.json in a tarball. PyPI
+// packages are read from the METADATA of a wheel, or the PKG-INFO of a source distribution.
+//
+// AutoPackage detects the type, giving GenericPackage for files that aren't recognized as another type. Generic
+// packages have no coordinates.
+func DetectPackage(filename string, packageType PackageType) (Package, error) {
+	var pkg Package
+	var err error
+
+	switch packageType {
+	case AutoPackage:
+		pkg, err = detectPackage(filename)
+	case MavenPackage:
+		pkg, err = readMavenPackage(filename)
+	case NPMPackage:
+		pkg, err = readNPMPackage(filename)
+	case PyPIPackage:
+		pkg, err = readPyPIPackage(filename)
+	case GenericPackage, "":
+		return Package{Type: GenericPackage}, nil
+	default:
+		return Package{}, fmt.Errorf("unsupported package type %q", packageType)
+	}
+	if err != nil {
+		return Package{}, fmt.Errorf("unable to read %s package %s: %s", packageType, filename, err)
+	}
+
+	return pkg, nil
+}
+
+// detectPackage detects the type of the package in filename from its extension and, for archives shared by several
+// types, its contents.
+func detectPackage(filename string) (Package, error) {
+	lower := strings.ToLower(filename)
+
+	switch {
+	case strings.HasSuffix(lower, ".pom"):
+		return readMavenPackage(filename)
+	case hasAnySuffix(lower, ".jar", ".war", ".ear", ".aar"):
+		// archives not built by Maven don't have its metadata
+		_, _, found, err := readZipEntry(filename, isPOMProperties)
+		if err != nil || !found {
+			return Package{Type: GenericPackage}, err
+		}
+		return readMavenPackage(filename)
+	case strings.HasSuffix(lower, ".whl"):
+		return readPyPIPackage(filename)
+	case hasAnySuffix(lower, ".tgz", ".tar.gz"):
+		name, _, found, err := readTarGzipEntry(filename, topLevelFile("package.json", "PKG-INFO"))
+		if err != nil || !found {
+			return Package{Type: GenericPackage}, err
+		}
+		if path.Base(name) == "package.json" {
+			return readNPMPackage(filename)
+		}
+		return readPyPIPackage(filename)
+	case strings.HasSuffix(lower, ".zip"):
+		_, _, found, err := readZipEntry(filename, topLevelFile("PKG-INFO"))
+		if err != nil || !found {
+			return Package{Type: GenericPackage}, err
+		}
+		return readPyPIPackage(filename)
+	}
+
+	return Package{Type: GenericPackage}, nil
+}
+
+// readMavenPackage reads the coordinates of a POM, or of the pom.properties in an archive built by Maven.
+func readMavenPackage(filename string) (Package, error) {
+	if strings.HasSuffix(strings.ToLower(filename), ".pom") {
+		file, err := os.Open(filename)
+		if err != nil {
+			return Package{}, err
+		}
+		defer file.Close()
+
+		return parsePOM(io.LimitReader(file, maxMetadataSize))
+	}
+
+	// archives that bundle their dependencies have the pom.properties of each, so the file's name picks between them
+	candidates := []Package{}
+	err := forEachZipEntry(filename, func(name string, reader io.Reader) error {
+		if !isPOMProperties(name) {
+			return nil
+		}
+
+		pkg, err := parsePOMProperties(reader)
+		if err != nil {
+			return fmt.Errorf("unable to parse %s: %s", name, err)
+		}
+		candidates = append(candidates, pkg)
+
+		return nil
+	})
+	if err != nil {
+		return Package{}, err
+	}
+
+	base := filepath.Base(filename)
+	for _, candidate := range candidates {
+		if len(candidates) == 1 || strings.HasPrefix(base, candidate.Name+"-"+candidate.Version) {
+			return candidate, nil
+		}
+	}
+
+	if len(candidates) == 0 {
+		return Package{}, fmt.Errorf("no META-INF/maven/*/*/pom.properties in archive")
+	}
+
+	return Package{}, fmt.Errorf("none of the %d pom.properties in archive match its file name", len(candidates))
+}
+
+// isPOMProperties returns true if name is the path of a pom.properties written by Maven into an archive it built.
+func isPOMProperties(name string) bool {
+	matched, _ := path.Match("META-INF/maven/*/*/pom.properties", name)
+	return matched
+}
+
+// parsePOM parses the coordinates of a POM, inheriting the group ID and version from its parent if it doesn't have
+// its own.
+func parsePOM(reader io.Reader) (Package, error) {
+	pom := struct {
+		GroupID    string `xml:"groupId"`
+		ArtifactID string `xml:"artifactId"`
+		Version    string `xml:"version"`
+		Parent     struct {
+			GroupID string `xml:"groupId"`
+			Version string `xml:"version"`
+		} `xml:"parent"`
+	}{}
+	if err := xml.NewDecoder(reader).Decode(&pom); err != nil {
+		return Package{}, err
+	}
+
+	pkg := Package{Type: MavenPackage, GroupID: pom.GroupID, Name: pom.ArtifactID, Version: pom.Version}
+	if pkg.GroupID == "" {
+		pkg.GroupID = pom.Parent.GroupID
+	}
+	if pkg.Version == "" {
+		pkg.Version = pom.Parent.Version
+	}
+
+	// properties are only interpolated by Maven itself
+	for _, value := range []string{pkg.GroupID, pkg.Name, pkg.Version} {
+		if strings.Contains(value, "${") {
+			return Package{}, fmt.Errorf("POM coordinate %q refers to a property", value)
+		}
+	}
+
+	return pkg, validatePackage(pkg, "groupId", "artifactId", "version")
+}
+
+// parsePOMProperties parses the coordinates in a pom.properties file.
+func parsePOMProperties(reader io.Reader) (Package, error) {
+	properties := map[string]string{}
+
+	scanner := bufio.NewScanner(io.LimitReader(reader, maxMetadataSize))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "!") {
+			continue
+		}
+		if key, value, found := strings.Cut(line, "="); found {
+			properties[strings.TrimSpace(key)] = strings.TrimSpace(value)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return Package{}, err
+	}
+
+	pkg := Package{Type: MavenPackage, GroupID: properties["groupId"], Name: properties["artifactId"], Version: properties["version"]}
+
+	return pkg, validatePackage(pkg, "groupId", "artifactId", "version")
+}
+
+// readNPMPackage reads the name and version in the package.json of an npm tarball.
+func readNPMPackage(filename string) (Package, error) {
+	_, content, found, err := readTarGzipEntry(filename, topLevelFile("package.json"))
+	if err != nil {
+		return Package{}, err
+	}
+	if !found {
+		return Package{}, fmt.Errorf("no package.json in tarball")
+	}
+
+	packageJSON := struct {
+		Name    string `json:"name"`
+		Version string `json:"version"`
+	}{}
+	if err := json.Unmarshal(content, &packageJSON); err != nil {
+		return Package{}, fmt.Errorf("unable to parse package.json: %s", err)
+	}
+
+	pkg := Package{Type: NPMPackage, Name: packageJSON.Name, Version: packageJSON.Version}
+
+	return pkg, validatePackage(pkg, "", "name", "version")
+}
+
+// readPyPIPackage reads the name and version in the METADATA of a wheel, or the PKG-INFO of a source distribution.
+func readPyPIPackage(filename string) (Package, error) {
+	var metadataFile string
+	var content []byte
+	var found bool
+	var err error
+
+	lower := strings.ToLower(filename)
+	switch {
+	case strings.HasSuffix(lower, ".whl"):
+		metadataFile = "METADATA"
+		_, content, found, err = readZipEntry(filename, func(name string) bool {
+			matched, _ := path.Match("*.dist-info/METADATA", name)
+			return matched
+		})
+	case strings.HasSuffix(lower, ".zip"):
+		metadataFile = "PKG-INFO"
+		_, content, found, err = readZipEntry(filename, topLevelFile(metadataFile))
+	default:
+		metadataFile = "PKG-INFO"
+		_, content, found, err = readTarGzipEntry(filename, topLevelFile(metadataFile))
+	}
+	if err != nil {
+		return Package{}, err
+	}
+	if !found {
+		return Package{}, fmt.Errorf("no %s in distribution", metadataFile)
+	}
+
+	// core metadata is in the format of email headers, followed by the description
+	header, err := textproto.NewReader(bufio.NewReader(strings.NewReader(string(content)))).ReadMIMEHeader()
+	if err != nil && header == nil {
+		return Package{}, fmt.Errorf("unable to parse %s: %s", metadataFile, err)
+	}
+
+	pkg := Package{Type: PyPIPackage, Name: header.Get("Name"), Version: header.Get("Version")}
+
+	return pkg, validatePackage(pkg, "", "Name", "Version")
+}
+
+// validatePackage returns an error naming the metadata field of the first of the package's coordinates that is
+// empty. Coordinates whose field is named "" aren't required.
+func validatePackage(pkg Package, groupIDField string, nameField string, versionField string) error {
+	for _, coordinate := range []struct{ field, value string }{
+		{groupIDField, pkg.GroupID},
+		{nameField, pkg.Name},
+		{versionField, pkg.Version},
+	} {
+		if coordinate.field != "" && coordinate.value == "" {
+			return fmt.Errorf("metadata has no %s", coordinate.field)
+		}
+	}
+
+	return nil
+}
+
+// topLevelFile returns a function matching archive entries that are one of baseNames, in a directory at the root of
+// the archive.
+func topLevelFile(baseNames ...string) func(name string) bool {
+	return func(name string) bool {
+		dir, base := path.Split(path.Clean(name))
+		dir = strings.TrimSuffix(dir, "/")
+		if dir == "" || dir == "." || strings.Contains(dir, "/") {
+			return false
+		}
+
+		for _, baseName := range baseNames {
+			if base == baseName {
+				return true
+			}
+		}
+
+		return false
+	}
+}
+
+// readTarGzipEntry returns the name and content of the first regular file in a gzipped tar archive matched by match.
+func readTarGzipEntry(filename string, match func(name string) bool) (name string, content []byte, found bool, err error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return "", nil, false, err
+	}
+	defer file.Close()
+
+	gzipReader, err := gzip.NewReader(bufio.NewReader(file))
+	if err != nil {
+		return "", nil, false, fmt.Errorf("not a gzipped tar archive: %s", err)
+	}
+
+	tarReader := tar.NewReader(gzipReader)
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			return "", nil, false, nil
+		}
+		if err != nil {
+			return "", nil, false, err
+		}
+
+		if header.Typeflag == tar.TypeReg && match(header.Name) {
+			content, err := io.ReadAll(io.LimitReader(tarReader, maxMetadataSize))
+			return header.Name, content, true, err
+		}
+	}
+}
+
+// readZipEntry returns the name and content of the first file in a zip archive matched by match.
+func readZipEntry(filename string, match func(name string) bool) (name string, content []byte, found bool, err error) {
+	err = forEachZipEntry(filename, func(entryName string, reader io.Reader) error {
+		if !match(entryName) {
+			return nil
+		}
+
+		name = entryName
+		if content, err = io.ReadAll(io.LimitReader(reader, maxMetadataSize)); err != nil {
+			return err
+		}
+
+		return errEntryFound
+	})
+	if err == errEntryFound {
+		return name, content, true, nil
+	}
+
+	return "", nil, false, err
+}
+
+// forEachZipEntry calls fn with the name and content of each file in a zip archive, stopping at the first error.
+func forEachZipEntry(filename string, fn func(name string, reader io.Reader) error) error {
+	zipReader, err := zip.OpenReader(filename)
+	if err != nil {
+		return fmt.Errorf("not a zip archive: %s", err)
+	}
+	defer zipReader.Close()
+
+	for _, file := range zipReader.File {
+		if file.FileInfo().IsDir() {
+			continue
+		}
+
+		reader, err := file.Open()
+		if err != nil {
+			return err
+		}
+		err = fn(file.Name, reader)
+		reader.Close()
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// hasAnySuffix returns true if s ends with any of suffixes.
+func hasAnySuffix(s string, suffixes ...string) bool {
+	for _, suffix := range suffixes {
+		if strings.HasSuffix(s, suffix) {
+			return true
+		}
+	}
+
+	return false
+}
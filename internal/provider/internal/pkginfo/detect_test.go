@@ -0,0 +1,200 @@
+// Copyright 2021 Splunk, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pkginfo
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const (
+	testPOM = `<?xml version="1.0" encoding="UTF-8"?>
+<project>
+  <modelVersion>4.0.0</modelVersion>
+  <parent>
+    <groupId>com.splunk</groupId>
+    <artifactId>parent</artifactId>
+    <version>1.2.3</version>
+  </parent>
+  <artifactId>example</artifactId>
+</project>
+`
+	testPackageJSON  = `{"name": "@splunk/example", "version": "1.2.3"}`
+	testPyPIMetadata = "Metadata-Version: 2.1\nName: example-package\nVersion: 1.2.3\n\nA description.\n"
+)
+
+// writeTestFile writes content to a file named name in a new directory.
+func writeTestFile(t *testing.T, name string, content []byte) string {
+	filename := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(filename, content, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	return filename
+}
+
+// writeTestZip writes a zip archive of files, by path, to a file named name in a new directory.
+func writeTestZip(t *testing.T, name string, files map[string]string) string {
+	archive := &bytes.Buffer{}
+	zipWriter := zip.NewWriter(archive)
+	for entryName, content := range files {
+		writer, err := zipWriter.Create(entryName)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := writer.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	zipWriter.Close()
+
+	return writeTestFile(t, name, archive.Bytes())
+}
+
+// writeTestTarGzip writes a gzipped tar archive of files, by path, to a file named name in a new directory.
+func writeTestTarGzip(t *testing.T, name string, files map[string]string) string {
+	archive := &bytes.Buffer{}
+	gzipWriter := gzip.NewWriter(archive)
+	tarWriter := tar.NewWriter(gzipWriter)
+	for entryName, content := range files {
+		if err := tarWriter.WriteHeader(&tar.Header{Name: entryName, Mode: 0644, Size: int64(len(content)), Typeflag: tar.TypeReg}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tarWriter.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	tarWriter.Close()
+	gzipWriter.Close()
+
+	return writeTestFile(t, name, archive.Bytes())
+}
+
+func TestDetectPackage(t *testing.T) {
+	mavenPackage := Package{Type: MavenPackage, GroupID: "com.splunk", Name: "example", Version: "1.2.3"}
+	pypiPackage := Package{Type: PyPIPackage, Name: "example-package", Version: "1.2.3"}
+
+	tests := []struct {
+		name     string
+		filename string
+		want     Package
+	}{
+		{
+			name:     "pom",
+			filename: writeTestFile(t, "example-1.2.3.pom", []byte(testPOM)),
+			want:     mavenPackage,
+		},
+		{
+			name: "jar",
+			filename: writeTestZip(t, "example-1.2.3.jar", map[string]string{
+				"META-INF/MANIFEST.MF":                                 "Manifest-Version: 1.0\n",
+				"META-INF/maven/com.splunk/example/pom.properties":     "#Generated by Maven\ngroupId=com.splunk\nartifactId=example\nversion=1.2.3\n",
+				"META-INF/maven/org.example/dependency/pom.properties": "groupId=org.example\nartifactId=dependency\nversion=0.1.0\n",
+			}),
+			want: mavenPackage,
+		},
+		{
+			name:     "npm",
+			filename: writeTestTarGzip(t, "example-1.2.3.tgz", map[string]string{"package/package.json": testPackageJSON}),
+			want:     Package{Type: NPMPackage, Name: "@splunk/example", Version: "1.2.3"},
+		},
+		{
+			name:     "wheel",
+			filename: writeTestZip(t, "example_package-1.2.3-py3-none-any.whl", map[string]string{"example_package-1.2.3.dist-info/METADATA": testPyPIMetadata}),
+			want:     pypiPackage,
+		},
+		{
+			name:     "sdist",
+			filename: writeTestTarGzip(t, "example-package-1.2.3.tar.gz", map[string]string{"example-package-1.2.3/PKG-INFO": testPyPIMetadata}),
+			want:     pypiPackage,
+		},
+		{
+			name:     "generic archive",
+			filename: writeTestTarGzip(t, "tool.tar.gz", map[string]string{"tool/bin/tool": "#!/bin/sh\n"}),
+			want:     Package{Type: GenericPackage},
+		},
+		{
+			name:     "generic jar",
+			filename: writeTestZip(t, "tool.jar", map[string]string{"META-INF/MANIFEST.MF": "Manifest-Version: 1.0\n"}),
+			want:     Package{Type: GenericPackage},
+		},
+		{
+			name:     "generic file",
+			filename: writeTestFile(t, "notes.txt", []byte("notes")),
+			want:     Package{Type: GenericPackage},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			pkg, err := DetectPackage(test.filename, AutoPackage)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if pkg != test.want {
+				t.Errorf("got package %+v, want %+v", pkg, test.want)
+			}
+
+			// the detected type reads the same package when given explicitly
+			pkg, err = DetectPackage(test.filename, test.want.Type)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if pkg != test.want {
+				t.Errorf("got package %+v for type %s, want %+v", pkg, test.want.Type, test.want)
+			}
+		})
+	}
+}
+
+func TestDetectPackageErrors(t *testing.T) {
+	tests := []struct {
+		name        string
+		filename    string
+		packageType PackageType
+	}{
+		{
+			name:        "npm without package.json",
+			filename:    writeTestTarGzip(t, "tool.tgz", map[string]string{"tool/bin/tool": "#!/bin/sh\n"}),
+			packageType: NPMPackage,
+		},
+		{
+			name:        "jar without pom.properties",
+			filename:    writeTestZip(t, "tool.jar", map[string]string{"META-INF/MANIFEST.MF": "Manifest-Version: 1.0\n"}),
+			packageType: MavenPackage,
+		},
+		{
+			name:        "pom with property version",
+			filename:    writeTestFile(t, "example.pom", []byte("<project><groupId>g</groupId><artifactId>a</artifactId><version>${revision}</version></project>")),
+			packageType: MavenPackage,
+		},
+		{
+			name:        "unsupported type",
+			filename:    writeTestFile(t, "notes.txt", []byte("notes")),
+			packageType: "rubygems",
+		},
+	}
+
+	for _, test := range tests {
+		if _, err := DetectPackage(test.filename, test.packageType); err == nil {
+			t.Errorf("%s: expected error", test.name)
+		}
+	}
+}
@@ -0,0 +1,159 @@
+// Copyright 2021 Splunk, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pkginfo
+
+import (
+	"archive/tar"
+	"bufio"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	helmChartFile  = "Chart.yaml"
+	helmIgnoreFile = ".helmignore"
+)
+
+// HelmChart is the subset of a Helm chart's Chart.yaml needed to publish it.
+type HelmChart struct {
+	Name       string `yaml:"name"`
+	Version    string `yaml:"version"`
+	AppVersion string `yaml:"appVersion"`
+}
+
+// FileName returns the canonical file name of the packaged chart.
+func (c HelmChart) FileName() string {
+	return fmt.Sprintf("%s-%s.tgz", c.Name, c.Version)
+}
+
+// ReadHelmChart reads the Chart.yaml of the chart at chartPath, which is either a chart directory or a packaged chart.
+func ReadHelmChart(chartPath string) (HelmChart, error) {
+	info, err := os.Stat(chartPath)
+	if err != nil {
+		return HelmChart{}, fmt.Errorf("unable to read chart %s: %s", chartPath, err)
+	}
+
+	var chart HelmChart
+	if info.IsDir() {
+		chart, err = readHelmChartDir(chartPath)
+	} else {
+		chart, err = readHelmChartPackage(chartPath)
+	}
+	if err != nil {
+		return HelmChart{}, fmt.Errorf("unable to read %s of chart %s: %s", helmChartFile, chartPath, err)
+	}
+
+	return chart, nil
+}
+
+// readHelmChartDir reads the Chart.yaml of a chart directory.
+func readHelmChartDir(dir string) (HelmChart, error) {
+	file, err := os.Open(filepath.Join(dir, helmChartFile))
+	if err != nil {
+		return HelmChart{}, err
+	}
+	defer file.Close()
+
+	return parseHelmChart(file)
+}
+
+// readHelmChartPackage reads the Chart.yaml of a packaged chart, which is in the chart's directory at the root of the
+// archive.
+func readHelmChartPackage(filename string) (HelmChart, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return HelmChart{}, err
+	}
+	defer file.Close()
+
+	gzipReader, err := gzip.NewReader(bufio.NewReader(file))
+	if err != nil {
+		return HelmChart{}, fmt.Errorf("not a packaged chart: %s", err)
+	}
+
+	tarReader := tar.NewReader(gzipReader)
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			return HelmChart{}, fmt.Errorf("no %s in package", helmChartFile)
+		}
+		if err != nil {
+			return HelmChart{}, err
+		}
+
+		// Chart.yaml files of dependencies in charts/ are deeper in the archive
+		dir, name := path.Split(path.Clean(header.Name))
+		if name == helmChartFile && dir != "" && !strings.Contains(strings.TrimSuffix(dir, "/"), "/") {
+			return parseHelmChart(tarReader)
+		}
+	}
+}
+
+// parseHelmChart parses a Chart.yaml, which must have a name and version.
+func parseHelmChart(reader io.Reader) (HelmChart, error) {
+	chart := HelmChart{}
+	if err := yaml.NewDecoder(reader).Decode(&chart); err != nil {
+		return chart, err
+	}
+
+	if chart.Name == "" {
+		return chart, fmt.Errorf("chart has no name")
+	}
+	if chart.Version == "" {
+		return chart, fmt.Errorf("chart has no version")
+	}
+
+	return chart, nil
+}
+
+// HelmIgnorePatterns returns the patterns in the .helmignore of a chart directory, if it has one. Patterns containing a
+// slash are matched against paths relative to the directory, and are returned in excludes. Other patterns are matched
+// against the base names of files and directories at any depth, and are returned in excludeNames.
+func HelmIgnorePatterns(dir string) (excludes []string, excludeNames []string, err error) {
+	content, err := os.ReadFile(filepath.Join(dir, helmIgnoreFile))
+	if os.IsNotExist(err) {
+		return nil, nil, nil
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to read %s of chart %s: %s", helmIgnoreFile, dir, err)
+	}
+
+	for _, line := range strings.Split(string(content), "\n") {
+		pattern := strings.TrimSpace(line)
+		if pattern == "" || strings.HasPrefix(pattern, "#") {
+			continue
+		}
+		if strings.HasPrefix(pattern, "!") {
+			return nil, nil, fmt.Errorf("negated pattern %q in %s of chart %s is not supported", pattern, helmIgnoreFile, dir)
+		}
+
+		// patterns for directories match them and, as they're excluded, their contents
+		pattern = strings.TrimSuffix(pattern, "/")
+		if strings.Contains(pattern, "/") {
+			excludes = append(excludes, strings.TrimPrefix(pattern, "/"))
+		} else {
+			excludeNames = append(excludeNames, pattern)
+		}
+	}
+
+	return excludes, excludeNames, nil
+}
@@ -0,0 +1,131 @@
+// Copyright 2021 Splunk, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pkginfo
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+const testChart = `apiVersion: v2
+name: example
+description: an example
+version: 1.2.3
+appVersion: "4.5"
+`
+
+// writeHelmChartPackage writes a packaged chart with files, by path, to a file in a new directory.
+func writeHelmChartPackage(t *testing.T, files map[string]string) string {
+	archive := &bytes.Buffer{}
+	gzipWriter := gzip.NewWriter(archive)
+	tarWriter := tar.NewWriter(gzipWriter)
+	for _, name := range []string{"example/charts/dependency/Chart.yaml", "example/Chart.yaml", "example/values.yaml"} {
+		content, ok := files[name]
+		if !ok {
+			continue
+		}
+		if err := tarWriter.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(content)), Typeflag: tar.TypeReg}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tarWriter.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	tarWriter.Close()
+	gzipWriter.Close()
+
+	filename := filepath.Join(t.TempDir(), "example-1.2.3.tgz")
+	if err := os.WriteFile(filename, archive.Bytes(), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	return filename
+}
+
+func TestReadHelmChart(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "Chart.yaml"), []byte(testChart), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	packaged := writeHelmChartPackage(t, map[string]string{
+		"example/charts/dependency/Chart.yaml": "name: dependency\nversion: 0.1.0\n",
+		"example/Chart.yaml":                   testChart,
+		"example/values.yaml":                  "replicas: 1\n",
+	})
+
+	want := HelmChart{Name: "example", Version: "1.2.3", AppVersion: "4.5"}
+	for name, chartPath := range map[string]string{"directory": dir, "package": packaged} {
+		t.Run(name, func(t *testing.T) {
+			chart, err := ReadHelmChart(chartPath)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if chart != want {
+				t.Errorf("got chart %+v, want %+v", chart, want)
+			}
+			if got, want := chart.FileName(), "example-1.2.3.tgz"; got != want {
+				t.Errorf("got file name %q, want %q", got, want)
+			}
+		})
+	}
+}
+
+func TestReadHelmChartErrors(t *testing.T) {
+	tests := map[string]string{
+		"no Chart.yaml": writeHelmChartPackage(t, map[string]string{"example/values.yaml": "replicas: 1\n"}),
+		"no version":    writeHelmChartPackage(t, map[string]string{"example/Chart.yaml": "name: example\n"}),
+		"not a package": filepath.Join(t.TempDir(), "missing.tgz"),
+	}
+
+	for name, chartPath := range tests {
+		if _, err := ReadHelmChart(chartPath); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
+
+func TestHelmIgnorePatterns(t *testing.T) {
+	dir := t.TempDir()
+	helmIgnore := "# comment\n\n.git/\n*.tmp\n/ci/values.yaml\ntemplates/tests/\n"
+	if err := os.WriteFile(filepath.Join(dir, ".helmignore"), []byte(helmIgnore), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	excludes, excludeNames, err := HelmIgnorePatterns(dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if want := []string{"ci/values.yaml", "templates/tests"}; !reflect.DeepEqual(excludes, want) {
+		t.Errorf("got excludes %v, want %v", excludes, want)
+	}
+	if want := []string{".git", "*.tmp"}; !reflect.DeepEqual(excludeNames, want) {
+		t.Errorf("got exclude names %v, want %v", excludeNames, want)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, ".helmignore"), []byte("!keep.tmp\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := HelmIgnorePatterns(dir); err == nil {
+		t.Error("expected error for a negated pattern")
+	}
+}
@@ -0,0 +1,198 @@
+// Copyright 2021 Splunk, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pkginfo
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+)
+
+const (
+	rpmLeadSize = 96
+
+	rpmTagName      = 1000
+	rpmTagVersion   = 1001
+	rpmTagRelease   = 1002
+	rpmTagEpoch     = 1003
+	rpmTagArch      = 1022
+	rpmTagSourceRPM = 1044
+
+	rpmTypeInt32  = 4
+	rpmTypeString = 6
+	// rpmTypeI18NString values are string tables, of which only the first is read
+	rpmTypeI18NString = 9
+)
+
+var (
+	rpmLeadMagic   = []byte{0xed, 0xab, 0xee, 0xdb}
+	rpmHeaderMagic = []byte{0x8e, 0xad, 0xe8, 0x01}
+)
+
+// RPMHeader is the subset of an RPM package's header needed to publish it.
+type RPMHeader struct {
+	Name    string
+	Version string
+	Release string
+	// Epoch is zero if the package has none.
+	Epoch int
+	// Arch is "src" for source packages.
+	Arch string
+}
+
+// FileName returns the canonical file name of the package.
+func (h RPMHeader) FileName() string {
+	return fmt.Sprintf("%s-%s-%s.%s.rpm", h.Name, h.Version, h.Release, h.Arch)
+}
+
+// ReadRPM reads the header of the RPM package at filename.
+func ReadRPM(filename string) (RPMHeader, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return RPMHeader{}, fmt.Errorf("unable to read package %s: %s", filename, err)
+	}
+	defer file.Close()
+
+	header, err := readRPM(bufio.NewReader(file))
+	if err != nil {
+		return RPMHeader{}, fmt.Errorf("unable to read header of package %s: %s", filename, err)
+	}
+
+	return header, nil
+}
+
+// rpmIndexEntry locates a tag's value within a header's store.
+type rpmIndexEntry struct {
+	Tag    int32
+	Type   int32
+	Offset int32
+	Count  int32
+}
+
+// readRPM reads the lead, signature, and header of an RPM package.
+func readRPM(reader io.Reader) (RPMHeader, error) {
+	lead := make([]byte, rpmLeadSize)
+	if _, err := io.ReadFull(reader, lead); err != nil || !bytes.Equal(lead[:4], rpmLeadMagic) {
+		return RPMHeader{}, fmt.Errorf("not an RPM package")
+	}
+
+	// the signature is a header structure, padded to a multiple of 8 bytes
+	_, signatureSize, err := readRPMHeaderStructure(reader)
+	if err != nil {
+		return RPMHeader{}, fmt.Errorf("invalid signature: %s", err)
+	}
+	if padding := (8 - signatureSize%8) % 8; padding > 0 {
+		if _, err := io.CopyN(io.Discard, reader, int64(padding)); err != nil {
+			return RPMHeader{}, err
+		}
+	}
+
+	tags, _, err := readRPMHeaderStructure(reader)
+	if err != nil {
+		return RPMHeader{}, fmt.Errorf("invalid header: %s", err)
+	}
+
+	header := RPMHeader{
+		Name:    tags.string(rpmTagName),
+		Version: tags.string(rpmTagVersion),
+		Release: tags.string(rpmTagRelease),
+		Epoch:   tags.int(rpmTagEpoch),
+		Arch:    tags.string(rpmTagArch),
+	}
+
+	// binary packages name the source package they were built from
+	if _, ok := tags[rpmTagSourceRPM]; !ok {
+		header.Arch = "src"
+	}
+
+	for _, field := range []struct{ name, value string }{
+		{"name", header.Name},
+		{"version", header.Version},
+		{"release", header.Release},
+		{"arch", header.Arch},
+	} {
+		if field.value == "" {
+			return header, fmt.Errorf("header has no %s", field.name)
+		}
+	}
+
+	return header, nil
+}
+
+// rpmTags are the values of a header's tags, as strings or int32s.
+type rpmTags map[int32]interface{}
+
+func (t rpmTags) string(tag int32) string {
+	value, _ := t[tag].(string)
+	return value
+}
+
+func (t rpmTags) int(tag int32) int {
+	value, _ := t[tag].(int32)
+	return int(value)
+}
+
+// readRPMHeaderStructure reads a header structure, returning the values of its string and int32 tags, and its size
+// excluding the preamble.
+func readRPMHeaderStructure(reader io.Reader) (rpmTags, int, error) {
+	preamble := make([]byte, 16)
+	if _, err := io.ReadFull(reader, preamble); err != nil {
+		return nil, 0, err
+	}
+	if !bytes.Equal(preamble[:4], rpmHeaderMagic) {
+		return nil, 0, fmt.Errorf("bad magic")
+	}
+
+	indexCount := binary.BigEndian.Uint32(preamble[8:12])
+	storeSize := binary.BigEndian.Uint32(preamble[12:16])
+	// real headers are far smaller, so larger sizes mean a corrupt file rather than a need for gigabytes of memory
+	if indexCount > 1<<16 || storeSize > 1<<26 {
+		return nil, 0, fmt.Errorf("header is too large")
+	}
+
+	entries := make([]rpmIndexEntry, indexCount)
+	if err := binary.Read(reader, binary.BigEndian, entries); err != nil {
+		return nil, 0, err
+	}
+
+	store := make([]byte, storeSize)
+	if _, err := io.ReadFull(reader, store); err != nil {
+		return nil, 0, err
+	}
+
+	tags := rpmTags{}
+	for _, entry := range entries {
+		if entry.Offset < 0 || int(entry.Offset) >= len(store) {
+			continue
+		}
+		value := store[entry.Offset:]
+
+		switch entry.Type {
+		case rpmTypeString, rpmTypeI18NString:
+			if end := bytes.IndexByte(value, 0); end >= 0 {
+				tags[entry.Tag] = string(value[:end])
+			}
+		case rpmTypeInt32:
+			if len(value) >= 4 {
+				tags[entry.Tag] = int32(binary.BigEndian.Uint32(value))
+			}
+		}
+	}
+
+	return tags, int(indexCount)*16 + int(storeSize), nil
+}
@@ -0,0 +1,149 @@
+// Copyright 2021 Splunk, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pkginfo
+
+import (
+	"bytes"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// rpmTestTag is a tag to write to a test package's header.
+type rpmTestTag struct {
+	tag   int32
+	value interface{}
+}
+
+// writeRPMHeaderStructure writes a header structure with tags, which are strings or int32s.
+func writeRPMHeaderStructure(buffer *bytes.Buffer, tags []rpmTestTag) int {
+	entries := make([]rpmIndexEntry, 0, len(tags))
+	store := &bytes.Buffer{}
+	for _, tag := range tags {
+		entry := rpmIndexEntry{Tag: tag.tag, Offset: int32(store.Len()), Count: 1}
+		switch value := tag.value.(type) {
+		case string:
+			entry.Type = rpmTypeString
+			store.WriteString(value)
+			store.WriteByte(0)
+		case int32:
+			entry.Type = rpmTypeInt32
+			binary.Write(store, binary.BigEndian, value)
+		}
+		entries = append(entries, entry)
+	}
+
+	buffer.Write(rpmHeaderMagic)
+	binary.Write(buffer, binary.BigEndian, []uint32{0, uint32(len(entries)), uint32(store.Len())})
+	binary.Write(buffer, binary.BigEndian, entries)
+	buffer.Write(store.Bytes())
+
+	return len(entries)*16 + store.Len()
+}
+
+// writeRPM writes a package with tags in its header to a file in a new directory.
+func writeRPM(t *testing.T, tags []rpmTestTag) string {
+	rpm := &bytes.Buffer{}
+	lead := make([]byte, rpmLeadSize)
+	copy(lead, rpmLeadMagic)
+	rpm.Write(lead)
+
+	signatureSize := writeRPMHeaderStructure(rpm, []rpmTestTag{{tag: 1000, value: int32(1234)}})
+	rpm.Write(make([]byte, (8-signatureSize%8)%8))
+	writeRPMHeaderStructure(rpm, tags)
+	rpm.WriteString("payload is not read")
+
+	filename := filepath.Join(t.TempDir(), "package.rpm")
+	if err := os.WriteFile(filename, rpm.Bytes(), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	return filename
+}
+
+func TestReadRPM(t *testing.T) {
+	tests := []struct {
+		name         string
+		tags         []rpmTestTag
+		want         RPMHeader
+		wantFileName string
+	}{
+		{
+			name: "binary",
+			tags: []rpmTestTag{
+				{tag: rpmTagName, value: "example"},
+				{tag: rpmTagVersion, value: "1.2.3"},
+				{tag: rpmTagRelease, value: "1.el9"},
+				{tag: rpmTagEpoch, value: int32(2)},
+				{tag: rpmTagArch, value: "x86_64"},
+				{tag: rpmTagSourceRPM, value: "example-1.2.3-1.el9.src.rpm"},
+			},
+			want:         RPMHeader{Name: "example", Version: "1.2.3", Release: "1.el9", Epoch: 2, Arch: "x86_64"},
+			wantFileName: "example-1.2.3-1.el9.x86_64.rpm",
+		},
+		{
+			name: "source",
+			tags: []rpmTestTag{
+				{tag: rpmTagName, value: "example"},
+				{tag: rpmTagVersion, value: "1.2.3"},
+				{tag: rpmTagRelease, value: "1"},
+				{tag: rpmTagArch, value: "x86_64"},
+			},
+			want:         RPMHeader{Name: "example", Version: "1.2.3", Release: "1", Arch: "src"},
+			wantFileName: "example-1.2.3-1.src.rpm",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			header, err := ReadRPM(writeRPM(t, test.tags))
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if header != test.want {
+				t.Errorf("got header %+v, want %+v", header, test.want)
+			}
+			if got := header.FileName(); got != test.wantFileName {
+				t.Errorf("got file name %q, want %q", got, test.wantFileName)
+			}
+		})
+	}
+}
+
+func TestReadRPMMissingRelease(t *testing.T) {
+	filename := writeRPM(t, []rpmTestTag{
+		{tag: rpmTagName, value: "example"},
+		{tag: rpmTagVersion, value: "1.2.3"},
+		{tag: rpmTagArch, value: "noarch"},
+		{tag: rpmTagSourceRPM, value: "example-1.2.3-1.src.rpm"},
+	})
+
+	if _, err := ReadRPM(filename); err == nil {
+		t.Error("expected error reading a package without a release")
+	}
+}
+
+func TestReadRPMNotAPackage(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "package.rpm")
+	if err := os.WriteFile(filename, []byte("not a package"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := ReadRPM(filename); err == nil {
+		t.Error("expected error reading a file that isn't a package")
+	}
+}
@@ -0,0 +1,99 @@
+// Copyright 2021 Splunk, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package signing makes detached OpenPGP signatures of artifacts.
+package signing
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+)
+
+// Signer makes detached signatures with an OpenPGP private key.
+type Signer struct {
+	entity *openpgp.Entity
+}
+
+// ReadKey returns the first key in an armored key ring. It must contain a private key, which is decrypted with
+// passphrase if it is encrypted. An empty passphrase only reads the key, which is enough to check that it's valid.
+func ReadKey(armoredKey string, passphrase string) (*openpgp.Entity, error) {
+	entities, err := openpgp.ReadArmoredKeyRing(strings.NewReader(armoredKey))
+	if err != nil {
+		return nil, fmt.Errorf("unable to read armored key: %s", err)
+	}
+	if len(entities) == 0 {
+		return nil, fmt.Errorf("armored key contains no keys")
+	}
+
+	entity := entities[0]
+	if entity.PrivateKey == nil {
+		return nil, fmt.Errorf("key %s isn't a private key", entity.PrimaryKey.KeyIdString())
+	}
+
+	if passphrase != "" {
+		if err := entity.DecryptPrivateKeys([]byte(passphrase)); err != nil {
+			return nil, fmt.Errorf("unable to decrypt key %s: %s", entity.PrimaryKey.KeyIdString(), err)
+		}
+	}
+
+	return entity, nil
+}
+
+// NewSigner returns a Signer using the first key in an armored key ring, decrypted with passphrase if it is
+// encrypted.
+func NewSigner(armoredKey string, passphrase string) (*Signer, error) {
+	entity, err := ReadKey(armoredKey, passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	if entity.PrivateKey.Encrypted {
+		return nil, fmt.Errorf("key %s is encrypted, and requires a passphrase", entity.PrimaryKey.KeyIdString())
+	}
+
+	return &Signer{entity: entity}, nil
+}
+
+// ArmoredDetachSign returns an armored detached signature of everything read from message.
+func (s *Signer) ArmoredDetachSign(message io.Reader) ([]byte, error) {
+	signature := &bytes.Buffer{}
+	if err := openpgp.ArmoredDetachSign(signature, s.entity, message, nil); err != nil {
+		return nil, fmt.Errorf("unable to sign: %s", err)
+	}
+
+	// armored output doesn't end with a newline, which tools expect of text files
+	signature.WriteString("\n")
+
+	return signature.Bytes(), nil
+}
+
+// DetachSign returns a binary detached signature of everything read from message, as the Terraform provider registry
+// protocol expects of SHA256SUMS signatures.
+func (s *Signer) DetachSign(message io.Reader) ([]byte, error) {
+	signature := &bytes.Buffer{}
+	if err := openpgp.DetachSign(signature, s.entity, message, nil); err != nil {
+		return nil, fmt.Errorf("unable to sign: %s", err)
+	}
+
+	return signature.Bytes(), nil
+}
+
+// KeyID returns the ID of the signing key, as 16 uppercase hexadecimal digits.
+func (s *Signer) KeyID() string {
+	return s.entity.PrimaryKey.KeyIdString()
+}
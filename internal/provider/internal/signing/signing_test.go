@@ -0,0 +1,112 @@
+// Copyright 2021 Splunk, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package signing
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+)
+
+// armoredPrivateKey returns a new armored private key, encrypted with passphrase if it isn't empty.
+func armoredPrivateKey(t *testing.T, passphrase string) (string, *openpgp.Entity) {
+	entity, err := openpgp.NewEntity("Test Signer", "", "signer@example.com", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buffer := &bytes.Buffer{}
+	writer, err := armor.Encode(buffer, openpgp.PrivateKeyType, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if passphrase == "" {
+		err = entity.SerializePrivate(writer, nil)
+	} else {
+		if err = entity.EncryptPrivateKeys([]byte(passphrase), nil); err == nil {
+			err = entity.SerializePrivateWithoutSigning(writer, nil)
+		}
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	writer.Close()
+
+	return buffer.String(), entity
+}
+
+func TestArmoredDetachSign(t *testing.T) {
+	for _, passphrase := range []string{"", "passphrase"} {
+		key, entity := armoredPrivateKey(t, passphrase)
+
+		signer, err := NewSigner(key, passphrase)
+		if err != nil {
+			t.Fatalf("unexpected error reading key: %s", err)
+		}
+
+		message := "artifact contents"
+		signature, err := signer.ArmoredDetachSign(strings.NewReader(message))
+		if err != nil {
+			t.Fatalf("unexpected error signing: %s", err)
+		}
+
+		if _, err := openpgp.CheckArmoredDetachedSignature(openpgp.EntityList{entity}, strings.NewReader(message), bytes.NewReader(signature), nil); err != nil {
+			t.Errorf("signature doesn't verify: %s", err)
+		}
+	}
+}
+
+func TestDetachSign(t *testing.T) {
+	key, entity := armoredPrivateKey(t, "")
+
+	signer, err := NewSigner(key, "")
+	if err != nil {
+		t.Fatalf("unexpected error reading key: %s", err)
+	}
+
+	if got, want := signer.KeyID(), entity.PrimaryKey.KeyIdString(); got != want {
+		t.Errorf("got key ID %q, want %q", got, want)
+	}
+
+	message := "SHA256SUMS contents"
+	signature, err := signer.DetachSign(strings.NewReader(message))
+	if err != nil {
+		t.Fatalf("unexpected error signing: %s", err)
+	}
+
+	if _, err := openpgp.CheckDetachedSignature(openpgp.EntityList{entity}, strings.NewReader(message), bytes.NewReader(signature), nil); err != nil {
+		t.Errorf("signature doesn't verify: %s", err)
+	}
+}
+
+func TestNewSignerRequiresPassphrase(t *testing.T) {
+	key, _ := armoredPrivateKey(t, "passphrase")
+
+	if _, err := NewSigner(key, ""); err == nil {
+		t.Error("expected error using an encrypted key without a passphrase")
+	}
+
+	if _, err := NewSigner(key, "wrong"); err == nil {
+		t.Error("expected error using an encrypted key with the wrong passphrase")
+	}
+
+	if _, err := ReadKey(key, ""); err != nil {
+		t.Errorf("unexpected error reading an encrypted key without a passphrase: %s", err)
+	}
+}
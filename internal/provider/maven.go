@@ -0,0 +1,486 @@
+// Copyright 2021 Splunk, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	"terraform-provider-artifacts/internal/provider/internal/client"
+	"terraform-provider-artifacts/internal/provider/internal/layout"
+)
+
+func resourceMaven() *schema.Resource {
+	return &schema.Resource{
+		Description:   "Upload a Maven artifact to Artifactory, at the path given by its coordinates",
+		CreateContext: resourceMavenCreate,
+		ReadContext:   resourceMavenRead,
+		UpdateContext: resourceMavenUpdate,
+		DeleteContext: resourceMavenDelete,
+		CustomizeDiff: resourceMavenDiff,
+		Schema: map[string]*schema.Schema{
+			repositoryKey: {
+				Description: fmt.Sprintf("Key of the repository to upload to. The provider's `%s` isn't applied, as Maven artifacts are always at the root of their repository.", basePathKey),
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+			},
+			groupIDKey: {
+				Description: "Group ID of the artifact, such as `com.example.tools`",
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+			},
+			artifactIDKey: {
+				Description: "Artifact ID of the artifact",
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+			},
+			versionKey: {
+				Description: "Version of the artifact. Files of `-SNAPSHOT` versions are uploaded with a unique timestamped version each time they change.",
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+			},
+			classifierKey: {
+				Description: "Classifier of the artifact, such as `sources` or `linux-amd64`",
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+			},
+			extensionKey: {
+				Description: fmt.Sprintf("Extension of the artifact, such as `jar`. Defaults to the extension of `%s`.", uploadFileKey),
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				ForceNew:    true,
+			},
+			uploadFileKey: {
+				Description: "File containing content to upload",
+				Type:        schema.TypeString,
+				Required:    true,
+			},
+			generatePOMKey: {
+				Description: "Set to true to also upload a minimal POM, without dependencies, for the version. Defaults to false.",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+			},
+			packagingKey: {
+				Description: fmt.Sprintf("Packaging in the generated POM. Defaults to `%s`.", extensionKey),
+				Type:        schema.TypeString,
+				Optional:    true,
+			},
+			updateMetadataKey: {
+				Description: fmt.Sprintf("Set to false to leave updating the artifact's `%s`, and for `-SNAPSHOT` versions the version's, to the repository, on both upload and destruction. Defaults to true. Updates by resources for the same artifact are serialized within a Terraform run, but separate runs updating the same artifact at once can overwrite each other's changes.", layout.MavenMetadataFile),
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+			},
+			deleteOldPath: {
+				Description: "Set to false if the uploaded file and POM should be orphaned on destruction of the resource, along with the previous build of `-SNAPSHOT` versions on update. Defaults to true.",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+			},
+			triggersKey: {
+				Description: "Arbitrary map of values that, when changed, will trigger re-uploading of this resource's file.",
+				Type:        schema.TypeMap,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			fullPathKey: {
+				Description: "Path the file is uploaded to, relative to the provider's URL",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			pomPathKey: {
+				Description: fmt.Sprintf("Path the POM is uploaded to, relative to the provider's URL, when `%s` is set", generatePOMKey),
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			fileVersionKey: {
+				Description: "Version in the uploaded file's name, which is the timestamped version of `-SNAPSHOT` versions",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			sha1Key: {
+				Description: "SHA1 of the uploaded file",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			sha256Key: {
+				Description: "SHA256 of the uploaded file",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			md5Key: {
+				Description: "MD5 of the uploaded file",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+		},
+	}
+}
+
+// resourceMavenCreate uploads the artifact, and its POM and metadata. It also re-uploads the artifact on update, which
+// for -SNAPSHOT versions uploads a new timestamped build and deletes the build it supersedes.
+func resourceMavenCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	c := meta.(*client.Client)
+
+	filePath, err := filepath.Abs(d.Get(uploadFileKey).(string))
+	if err != nil {
+		return diag.Errorf("unable to determine absolute path for file %s", d.Get(uploadFileKey).(string))
+	}
+
+	coordinates := mavenCoordinates(d)
+	if coordinates.Extension == "" {
+		coordinates.Extension = mavenExtension(filePath)
+	}
+	if err := coordinates.Validate(); err != nil {
+		return diag.FromErr(err)
+	}
+
+	repository, err := client.JoinPath(d.Get(repositoryKey).(string))
+	if err != nil {
+		return diag.Errorf("invalid %s: %s", repositoryKey, err)
+	}
+
+	// the metadata is read, changed and written back, so changes for the same artifact are serialized
+	defer c.Locks.Lock(repository + "/" + coordinates.ArtifactDir())()
+
+	now := time.Now()
+	fileVersion := coordinates.Version
+	var snapshotMetadata []byte
+	if coordinates.IsSnapshot() {
+		// the next build follows the latest in the version's metadata
+		snapshotMetadataPath := repository + "/" + coordinates.VersionDir() + "/" + layout.MavenMetadataFile
+		if snapshotMetadata, _, err = c.FileContent(ctx, snapshotMetadataPath); err != nil {
+			return diag.FromErr(err)
+		}
+
+		buildNumber, err := layout.NextSnapshotBuildNumber(snapshotMetadata)
+		if err != nil {
+			return diag.Errorf("unable to read %s: %s", snapshotMetadataPath, err)
+		}
+
+		if d.Id() != "" && d.Get(deleteOldPath).(bool) {
+			oldFileVersion, _ := d.GetChange(fileVersionKey)
+			if snapshotMetadata, err = coordinates.RemoveSnapshotBuild(snapshotMetadata, oldFileVersion.(string), now); err != nil {
+				return diag.FromErr(err)
+			}
+		}
+
+		fileVersion = coordinates.SnapshotVersion(now, buildNumber)
+		if snapshotMetadata, err = coordinates.UpdateSnapshotMetadata(snapshotMetadata, now, buildNumber, d.Get(generatePOMKey).(bool)); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	fullPath := repository + "/" + coordinates.Path(fileVersion)
+	if err := c.Upload(ctx, fullPath, filePath, nil); err != nil {
+		return diag.Errorf("failure uploading file %s: %s", filePath, err)
+	}
+
+	pomPath := ""
+	if d.Get(generatePOMKey).(bool) {
+		packaging := d.Get(packagingKey).(string)
+		if packaging == "" {
+			packaging = coordinates.Extension
+		}
+
+		pom, err := coordinates.POM(packaging)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+
+		pomPath = repository + "/" + coordinates.POMPath(fileVersion)
+		if err := c.UploadContent(ctx, pomPath, pom, nil); err != nil {
+			return diag.Errorf("failure uploading POM: %s", err)
+		}
+	}
+
+	if d.Get(updateMetadataKey).(bool) {
+		if err := updateMavenMetadata(ctx, c, repository, coordinates, now, snapshotMetadata); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	// files of the previous build, or a POM that's no longer generated, are superseded
+	if d.Id() != "" && d.Get(deleteOldPath).(bool) {
+		oldPOMPath, _ := d.GetChange(pomPathKey)
+
+		var superseded []string
+		for _, oldPath := range []string{d.Id(), oldPOMPath.(string)} {
+			if oldPath != "" && oldPath != fullPath && oldPath != pomPath {
+				superseded = append(superseded, oldPath)
+			}
+		}
+
+		if err := deleteExisting(ctx, c, superseded); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	d.SetId(fullPath)
+	for key, value := range map[string]string{
+		extensionKey:   coordinates.Extension,
+		fullPathKey:    fullPath,
+		pomPathKey:     pomPath,
+		fileVersionKey: fileVersion,
+	} {
+		if err := d.Set(key, value); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	return resourceMavenRead(ctx, d, meta)
+}
+
+func resourceMavenRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*client.Client)
+
+	checksums, err := client.Checksums(ctx, d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if checksums.Get(client.ChecksumType) == "" {
+		d.SetId("")
+		return nil
+	}
+
+	if err := setChecksums(d, checksums); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return nil
+}
+
+// resourceMavenUpdate uploads the artifact again if its file, POM, or triggers changed, or if it drifted. Other changes
+// only affect later operations.
+func resourceMavenUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	if d.HasChanges(uploadFileKey, triggersKey, generatePOMKey, packagingKey, sha1Key, sha256Key, md5Key) {
+		return resourceMavenCreate(ctx, d, meta)
+	}
+
+	return resourceMavenRead(ctx, d, meta)
+}
+
+// resourceMavenDelete deletes the artifact's file and POM, and removes them from its metadata.
+func resourceMavenDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	c := meta.(*client.Client)
+
+	if !d.Get(deleteOldPath).(bool) {
+		return nil
+	}
+
+	paths := []string{d.Id()}
+	if pomPath := d.Get(pomPathKey).(string); pomPath != "" {
+		paths = append(paths, pomPath)
+	}
+
+	if err := deleteExisting(ctx, c, paths); err != nil {
+		return diag.FromErr(err)
+	}
+
+	if d.Get(updateMetadataKey).(bool) {
+		repository, err := client.JoinPath(d.Get(repositoryKey).(string))
+		if err != nil {
+			return diag.Errorf("invalid %s: %s", repositoryKey, err)
+		}
+
+		if err := removeMavenMetadata(ctx, c, repository, mavenCoordinates(d), d.Get(fileVersionKey).(string), time.Now()); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	return nil
+}
+
+func resourceMavenDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if err := validateMavenCoordinates(d); err != nil {
+		return err
+	}
+
+	drifted, err := uploadDrifted(d, meta)
+	if err != nil {
+		return err
+	}
+
+	if !d.HasChanges(uploadFileKey, triggersKey, generatePOMKey, packagingKey) && !drifted {
+		return nil
+	}
+
+	if err := setNewComputedChecksums(d); err != nil {
+		return err
+	}
+
+	// each upload of a snapshot is a new build, with a new path
+	if strings.HasSuffix(d.Get(versionKey).(string), "-SNAPSHOT") {
+		for _, key := range []string{fullPathKey, pomPathKey, fileVersionKey} {
+			if err := d.SetNewComputed(key); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// validateMavenCoordinates returns an error if the known coordinates of the artifact in d can't be used in a path.
+func validateMavenCoordinates(d *schema.ResourceDiff) error {
+	for _, key := range []string{groupIDKey, artifactIDKey, versionKey, classifierKey, extensionKey, uploadFileKey} {
+		if !d.NewValueKnown(key) {
+			return nil
+		}
+	}
+
+	coordinates := mavenCoordinates(d)
+	if coordinates.Extension == "" {
+		coordinates.Extension = mavenExtension(d.Get(uploadFileKey).(string))
+	}
+
+	return coordinates.Validate()
+}
+
+// updateMavenMetadata uploads the artifact's metadata listing its version, and for snapshots the version's metadata.
+func updateMavenMetadata(ctx context.Context, c *client.Client, repository string, coordinates layout.MavenCoordinates, now time.Time, snapshotMetadata []byte) error {
+	artifactMetadataPath := repository + "/" + coordinates.ArtifactDir() + "/" + layout.MavenMetadataFile
+
+	existing, _, err := c.FileContent(ctx, artifactMetadataPath)
+	if err != nil {
+		return err
+	}
+
+	artifactMetadata, err := coordinates.UpdateArtifactMetadata(existing, now)
+	if err != nil {
+		return fmt.Errorf("unable to update %s: %s", artifactMetadataPath, err)
+	}
+
+	if err := c.UploadContent(ctx, artifactMetadataPath, artifactMetadata, nil); err != nil {
+		return fmt.Errorf("failure uploading %s: %s", artifactMetadataPath, err)
+	}
+
+	if coordinates.IsSnapshot() {
+		snapshotMetadataPath := repository + "/" + coordinates.VersionDir() + "/" + layout.MavenMetadataFile
+		if err := c.UploadContent(ctx, snapshotMetadataPath, snapshotMetadata, nil); err != nil {
+			return fmt.Errorf("failure uploading %s: %s", snapshotMetadataPath, err)
+		}
+	}
+
+	return nil
+}
+
+// removeMavenMetadata removes a deleted snapshot build from the version's metadata. Once none of the version's files
+// remain, it deletes the version's folder and removes the version from the artifact's metadata, deleting that too if
+// no versions remain. Changes for the same artifact are serialized with those made by resourceMavenCreate.
+func removeMavenMetadata(ctx context.Context, c *client.Client, repository string, coordinates layout.MavenCoordinates, fileVersion string, now time.Time) error {
+	defer c.Locks.Lock(repository + "/" + coordinates.ArtifactDir())()
+
+	versionDir := repository + "/" + coordinates.VersionDir()
+
+	info, found, err := c.Folder(ctx, versionDir)
+	if err != nil {
+		return err
+	}
+
+	remaining := false
+	for _, child := range info.Children {
+		// including the metadata's checksum files
+		remaining = remaining || !strings.HasPrefix(child, layout.MavenMetadataFile)
+	}
+
+	if remaining {
+		if !coordinates.IsSnapshot() {
+			return nil
+		}
+
+		snapshotMetadataPath := versionDir + "/" + layout.MavenMetadataFile
+		existing, found, err := c.FileContent(ctx, snapshotMetadataPath)
+		if err != nil || !found {
+			return err
+		}
+
+		snapshotMetadata, err := coordinates.RemoveSnapshotBuild(existing, fileVersion, now)
+		if err != nil {
+			return fmt.Errorf("unable to update %s: %s", snapshotMetadataPath, err)
+		}
+
+		if err := c.UploadContent(ctx, snapshotMetadataPath, snapshotMetadata, nil); err != nil {
+			return fmt.Errorf("failure uploading %s: %s", snapshotMetadataPath, err)
+		}
+
+		return nil
+	}
+
+	if found {
+		if err := deleteExisting(ctx, c, []string{versionDir}); err != nil {
+			return err
+		}
+	}
+
+	artifactMetadataPath := repository + "/" + coordinates.ArtifactDir() + "/" + layout.MavenMetadataFile
+	existing, found, err := c.FileContent(ctx, artifactMetadataPath)
+	if err != nil || !found {
+		return err
+	}
+
+	artifactMetadata, err := coordinates.RemoveArtifactVersion(existing, now)
+	if err != nil {
+		return fmt.Errorf("unable to update %s: %s", artifactMetadataPath, err)
+	}
+
+	if artifactMetadata == nil {
+		return deleteExisting(ctx, c, []string{artifactMetadataPath})
+	}
+
+	if err := c.UploadContent(ctx, artifactMetadataPath, artifactMetadata, nil); err != nil {
+		return fmt.Errorf("failure uploading %s: %s", artifactMetadataPath, err)
+	}
+
+	return nil
+}
+
+// mavenCoordinates returns the coordinates of the artifact in d, whose extension may not be known yet.
+func mavenCoordinates(d resourceGetter) layout.MavenCoordinates {
+	return layout.MavenCoordinates{
+		GroupID:    d.Get(groupIDKey).(string),
+		ArtifactID: d.Get(artifactIDKey).(string),
+		Version:    d.Get(versionKey).(string),
+		Classifier: d.Get(classifierKey).(string),
+		Extension:  d.Get(extensionKey).(string),
+	}
+}
+
+// mavenExtension returns the extension of filename, including compound extensions such as tar.gz.
+func mavenExtension(filename string) string {
+	base := filepath.Base(filename)
+
+	for _, compound := range []string{".tar.gz", ".tar.bz2", ".tar.xz", ".tar.zst"} {
+		if strings.HasSuffix(base, compound) {
+			return strings.TrimPrefix(compound, ".")
+		}
+	}
+
+	return strings.TrimPrefix(filepath.Ext(base), ".")
+}
@@ -0,0 +1,91 @@
+// Copyright 2021 Splunk, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+
+	"terraform-provider-artifacts/internal/provider/internal/layout"
+)
+
+// mavenPathFunction is provider::artifacts::maven_path, which returns the path of a Maven artifact the way
+// artifacts_maven and artifacts_upload lay it out.
+type mavenPathFunction struct{}
+
+func newMavenPathFunction() function.Function {
+	return mavenPathFunction{}
+}
+
+// Metadata implements function.Function.
+func (f mavenPathFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = mavenPathFunctionKey
+}
+
+// Definition implements function.Function.
+func (f mavenPathFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Path of a Maven artifact",
+		Description: "Returns the path of a Maven artifact's file, relative to its repository, in the Maven 2 layout. " +
+			"Files of SNAPSHOT versions are named with the SNAPSHOT version, which Artifactory resolves to the latest " +
+			"timestamped file.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "group",
+				Description: "Group ID, such as `com.example`",
+			},
+			function.StringParameter{
+				Name:        "artifact",
+				Description: "Artifact ID",
+			},
+			function.StringParameter{
+				Name:        "version",
+				Description: "Version",
+			},
+			function.StringParameter{
+				Name:           "classifier",
+				Description:    "Classifier, such as `sources`, or null or an empty string for none",
+				AllowNullValue: true,
+			},
+			function.StringParameter{
+				Name:        "ext",
+				Description: "Extension of the file, such as `jar`",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+// Run implements function.Function.
+func (f mavenPathFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var coordinates layout.MavenCoordinates
+	var classifier *string
+	resp.Error = function.ConcatFuncErrors(resp.Error, req.Arguments.Get(ctx, &coordinates.GroupID,
+		&coordinates.ArtifactID, &coordinates.Version, &classifier, &coordinates.Extension))
+	if resp.Error != nil {
+		return
+	}
+	if classifier != nil {
+		coordinates.Classifier = *classifier
+	}
+
+	if err := coordinates.Validate(); err != nil {
+		resp.Error = function.NewFuncError(err.Error())
+		return
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Error, resp.Result.Set(ctx, coordinates.Path(coordinates.Version)))
+}
@@ -0,0 +1,291 @@
+// Copyright 2021 Splunk, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+
+	"terraform-provider-artifacts/internal/provider/internal/client"
+	"terraform-provider-artifacts/internal/provider/internal/layout"
+)
+
+func TestAccResourceMaven(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV5ProviderFactories: protoV5ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testResourceMavenConfig,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("artifacts_maven.test", "full_path", "sas-maven/com/splunk/terraform-provider-artifacts-test/1.0.0/terraform-provider-artifacts-test-1.0.0-linux.txt"),
+					resource.TestCheckResourceAttr("artifacts_maven.test", "pom_path", "sas-maven/com/splunk/terraform-provider-artifacts-test/1.0.0/terraform-provider-artifacts-test-1.0.0.pom"),
+					resource.TestCheckResourceAttr("artifacts_maven.test", "extension", "txt"),
+					resource.TestCheckResourceAttr("artifacts_maven.test", "sha1", "af3d968c42b3046f86296c7522b3b20dfdc58c59"),
+				),
+			},
+		},
+	})
+}
+
+func TestMavenExtension(t *testing.T) {
+	tests := map[string]string{
+		"build/tool.jar":           "jar",
+		"build/tool-1.0.0.tar.gz":  "tar.gz",
+		"build/tool-1.0.0.tar.zst": "tar.zst",
+		"build/tool":               "",
+	}
+
+	for filename, want := range tests {
+		if got := mavenExtension(filename); got != want {
+			t.Errorf("mavenExtension(%q) = %q, want %q", filename, got, want)
+		}
+	}
+}
+
+const testResourceMavenConfig = `
+provider "artifacts" {
+  url = "https://repo.splunk.com/artifactory"
+}
+
+resource "artifacts_maven" "test" {
+  repository   = "sas-maven"
+  group_id     = "com.splunk"
+  artifact_id  = "terraform-provider-artifacts-test"
+  version      = "1.0.0"
+  classifier   = "linux"
+  upload_file  = "test_files/source_file.txt"
+  generate_pom = true
+}
+`
+
+// fakeRepository serves files from a map of their paths to their content, listing folders from the paths. The map
+// is only safe to use once the server's requests are done.
+func fakeRepository(files map[string][]byte) *httptest.Server {
+	var mu sync.Mutex
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		filePath := strings.TrimPrefix(r.URL.Path, "/")
+
+		switch {
+		case r.Method == http.MethodGet && strings.HasPrefix(filePath, "api/storage/"):
+			if content, ok := files[strings.TrimPrefix(filePath, "api/storage/")]; ok {
+				json.NewEncoder(w).Encode(map[string]interface{}{"checksums": map[string]string{"sha1": fmt.Sprintf("%x", sha1.Sum(content))}})
+				return
+			}
+
+			folder := strings.TrimPrefix(filePath, "api/storage/") + "/"
+			children := map[string]bool{}
+			for existing := range files {
+				if strings.HasPrefix(existing, folder) {
+					children["/"+strings.SplitN(strings.TrimPrefix(existing, folder), "/", 2)[0]] = true
+				}
+			}
+			if len(children) == 0 {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			uris := []map[string]interface{}{}
+			for child := range children {
+				uris = append(uris, map[string]interface{}{"uri": child})
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{"children": uris})
+		case r.Method == http.MethodGet:
+			content, ok := files[filePath]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			w.Write(content)
+		case r.Method == http.MethodPut:
+			files[filePath], _ = io.ReadAll(r.Body)
+			w.WriteHeader(http.StatusCreated)
+		case r.Method == http.MethodDelete:
+			for existing := range files {
+				if existing == filePath || strings.HasPrefix(existing, filePath+"/") {
+					delete(files, existing)
+				}
+			}
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+}
+
+func TestRemoveMavenMetadata(t *testing.T) {
+	now := time.Date(2021, 12, 1, 12, 34, 56, 0, time.UTC)
+	release := layout.MavenCoordinates{GroupID: "com.splunk", ArtifactID: "tool", Version: "1.0.0", Extension: "jar"}
+	snapshot := layout.MavenCoordinates{GroupID: "com.splunk", ArtifactID: "tool", Version: "1.1.0-SNAPSHOT", Extension: "jar"}
+	sources := snapshot
+	sources.Classifier = "sources"
+
+	artifactMetadata, _ := release.UpdateArtifactMetadata(nil, now)
+	artifactMetadata, _ = snapshot.UpdateArtifactMetadata(artifactMetadata, now)
+	snapshotMetadata, _ := snapshot.UpdateSnapshotMetadata(nil, now, 1, false)
+	snapshotMetadata, _ = sources.UpdateSnapshotMetadata(snapshotMetadata, now, 2, false)
+
+	// the artifacts' files have already been deleted
+	files := map[string][]byte{
+		"maven/com/splunk/tool/maven-metadata.xml":                                      artifactMetadata,
+		"maven/com/splunk/tool/1.0.0/maven-metadata.xml":                                {},
+		"maven/com/splunk/tool/1.1.0-SNAPSHOT/maven-metadata.xml":                       snapshotMetadata,
+		"maven/com/splunk/tool/1.1.0-SNAPSHOT/maven-metadata.xml.sha1":                  {},
+		"maven/com/splunk/tool/1.1.0-SNAPSHOT/tool-1.1.0-20211201.123456-2-sources.jar": {},
+	}
+	server := fakeRepository(files)
+	defer server.Close()
+	c := &client.Client{URL: server.URL}
+
+	if err := removeMavenMetadata(context.Background(), c, "maven", release, "1.0.0", now); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := files["maven/com/splunk/tool/1.0.0/maven-metadata.xml"]; ok {
+		t.Error("expected the release's folder to be deleted")
+	}
+	if strings.Contains(string(files["maven/com/splunk/tool/maven-metadata.xml"]), "<version>1.0.0</version>") {
+		t.Errorf("expected the release to be removed from the artifact's metadata, got:\n%s", files["maven/com/splunk/tool/maven-metadata.xml"])
+	}
+
+	if err := removeMavenMetadata(context.Background(), c, "maven", snapshot, "1.1.0-20211201.123456-1", now); err != nil {
+		t.Fatal(err)
+	}
+	if metadata := string(files["maven/com/splunk/tool/1.1.0-SNAPSHOT/maven-metadata.xml"]); strings.Contains(metadata, "1.1.0-20211201.123456-1") || !strings.Contains(metadata, "1.1.0-20211201.123456-2") {
+		t.Errorf("expected only build 1 to be removed from the snapshot's metadata, got:\n%s", metadata)
+	}
+
+	delete(files, "maven/com/splunk/tool/1.1.0-SNAPSHOT/tool-1.1.0-20211201.123456-2-sources.jar")
+	if err := removeMavenMetadata(context.Background(), c, "maven", sources, "1.1.0-20211201.123456-2", now); err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 0 {
+		t.Errorf("expected all metadata to be deleted, got %v", files)
+	}
+}
+
+// TestMavenCreateParallel creates versions of the same artifact in parallel, as Terraform applies resources, checking
+// that none of them are lost from the artifact's metadata.
+func TestMavenCreateParallel(t *testing.T) {
+	files := map[string][]byte{}
+	server := fakeRepository(files)
+	defer server.Close()
+	c := &client.Client{URL: server.URL, Locks: client.NewPathLocks()}
+
+	versions := []string{"1.0.0", "1.1.0", "1.2.0", "2.0.0-SNAPSHOT", "2.1.0-SNAPSHOT"}
+	resources := make([]*schema.ResourceData, len(versions))
+	for i, version := range versions {
+		resources[i] = schema.TestResourceDataRaw(t, resourceMaven().Schema, map[string]interface{}{
+			repositoryKey:  "maven",
+			groupIDKey:     "com.splunk",
+			artifactIDKey:  "tool",
+			versionKey:     version,
+			uploadFileKey:  "test_files/source_file.txt",
+			generatePOMKey: true,
+		})
+	}
+
+	var wg sync.WaitGroup
+	for _, d := range resources {
+		wg.Add(1)
+		go func(d *schema.ResourceData) {
+			defer wg.Done()
+			if diags := resourceMavenCreate(context.Background(), d, c); diags.HasError() {
+				t.Errorf("unexpected errors: %v", diags)
+			}
+		}(d)
+	}
+	wg.Wait()
+
+	metadata := string(files["maven/com/splunk/tool/maven-metadata.xml"])
+	for _, version := range versions {
+		if !strings.Contains(metadata, "<version>"+version+"</version>") {
+			t.Errorf("expected version %s in the artifact's metadata, got:\n%s", version, metadata)
+		}
+	}
+}
+
+// TestMavenUpdateWithoutUpload checks that changes that don't affect the uploaded file, such as to update_metadata,
+// don't upload a new build of a snapshot.
+func TestMavenUpdateWithoutUpload(t *testing.T) {
+	fullPath := "maven/com/splunk/tool/1.0.0-SNAPSHOT/tool-1.0.0-20211201.123456-1.txt"
+	files := map[string][]byte{}
+	server := fakeRepository(files)
+	defer server.Close()
+	c := &client.Client{URL: server.URL, ChecksumType: client.ChecksumSHA1}
+
+	content, err := os.ReadFile("test_files/source_file.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	files[fullPath] = content
+
+	r := resourceMaven()
+	state := &terraform.InstanceState{
+		ID: fullPath,
+		Attributes: map[string]string{
+			"id":              fullPath,
+			repositoryKey:     "maven",
+			groupIDKey:        "com.splunk",
+			artifactIDKey:     "tool",
+			versionKey:        "1.0.0-SNAPSHOT",
+			extensionKey:      "txt",
+			uploadFileKey:     "test_files/source_file.txt",
+			generatePOMKey:    "false",
+			updateMetadataKey: "true",
+			deleteOldPath:     "true",
+			fullPathKey:       fullPath,
+			fileVersionKey:    "1.0.0-20211201.123456-1",
+			sha1Key:           fmt.Sprintf("%x", sha1.Sum(content)),
+		},
+	}
+	config := terraform.NewResourceConfigRaw(map[string]interface{}{
+		repositoryKey:     "maven",
+		groupIDKey:        "com.splunk",
+		artifactIDKey:     "tool",
+		versionKey:        "1.0.0-SNAPSHOT",
+		uploadFileKey:     "test_files/source_file.txt",
+		updateMetadataKey: false,
+	})
+
+	diff, err := r.Diff(context.Background(), state, config, c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	newState, diags := r.Apply(context.Background(), state, diff, c)
+	if diags.HasError() {
+		t.Fatalf("unexpected errors: %v", diags)
+	}
+
+	if newState.Attributes[fullPathKey] != fullPath || newState.Attributes[fileVersionKey] != "1.0.0-20211201.123456-1" {
+		t.Errorf("expected the build to be kept, got %s", newState.Attributes[fullPathKey])
+	}
+	if len(files) != 1 {
+		t.Errorf("expected nothing to be uploaded, got %v", files)
+	}
+}
@@ -0,0 +1,68 @@
+// Copyright 2021 Splunk, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+
+	"terraform-provider-artifacts/internal/provider/internal/client"
+)
+
+// pathJoinFunction is provider::artifacts::path_join, which joins path elements the way the provider joins base_path
+// and upload_path.
+type pathJoinFunction struct{}
+
+func newPathJoinFunction() function.Function {
+	return pathJoinFunction{}
+}
+
+// Metadata implements function.Function.
+func (f pathJoinFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = pathJoinFunctionKey
+}
+
+// Definition implements function.Function.
+func (f pathJoinFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Join path elements with slashes",
+		Description: "Joins path elements with slashes, the same way the provider joins `base_path` and `upload_path`. " +
+			"Leading and trailing slashes of each element are ignored, and empty elements are skipped. Elements " +
+			"containing double slashes, or `.` or `..` segments, are an error, so that a joined path can't escape its base.",
+		VariadicParameter: function.StringParameter{
+			Name:        "elements",
+			Description: "Path elements to join",
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+// Run implements function.Function.
+func (f pathJoinFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var elements []string
+	resp.Error = function.ConcatFuncErrors(resp.Error, req.Arguments.Get(ctx, &elements))
+	if resp.Error != nil {
+		return
+	}
+
+	joined, err := client.JoinPath(elements...)
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(0, err.Error())
+		return
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Error, resp.Result.Set(ctx, joined))
+}
@@ -0,0 +1,264 @@
+// Copyright 2021 Splunk, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+
+	"terraform-provider-artifacts/internal/provider/internal/client"
+)
+
+func resourcePointer() *schema.Resource {
+	return &schema.Resource{
+		Description:   "Maintain an alias path, such as latest.txt, that points at another artifact",
+		CreateContext: resourcePointerWrite,
+		ReadContext:   resourcePointerRead,
+		UpdateContext: resourcePointerWrite,
+		DeleteContext: resourcePointerDelete,
+		CustomizeDiff: resourcePointerDiff,
+		Schema: map[string]*schema.Schema{
+			pointerPathKey: {
+				Description: fmt.Sprintf("Alias path, relative to the provider's URL and `%s`", basePathKey),
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+			},
+			targetPathKey: {
+				Description: fmt.Sprintf("Path of the artifact the alias points at, relative to the provider's URL and `%s`. It must already exist. "+
+					"Use the `%s` of an `%s`, rather than its `%s`, which already includes `%s`.", basePathKey, uploadPathKey, uploadResourceKey, fullPathKey, basePathKey),
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			pointerModeKey: {
+				Description: fmt.Sprintf("How the alias points at its target. `%s` copies the target's contents to the alias on the server, "+
+					"`%s` writes `%s` to the alias, and `%s` writes an empty file to the alias whose `%s` and `%s` properties "+
+					"name the target, for redirecting clients. Defaults to `%s`.",
+					pointerModeCopy, pointerModeFile, pointerContentKey, pointerModeProperty, pointerTargetProp, pointerURLProp, pointerModeCopy),
+				Type:             schema.TypeString,
+				Optional:         true,
+				Default:          pointerModeCopy,
+				ValidateDiagFunc: validation.ToDiagFunc(validation.StringInSlice([]string{pointerModeCopy, pointerModeFile, pointerModeProperty}, false)),
+			},
+			pointerContentKey: {
+				Description: fmt.Sprintf("Contents of the alias when `%s` is `%s`, such as the target's version. Defaults to the target's URL.", pointerModeKey, pointerModeFile),
+				Type:        schema.TypeString,
+				Optional:    true,
+			},
+			fullPathKey: {
+				Description: fmt.Sprintf("Alias path, relative to the provider's URL, after applying the provider's `%s`", basePathKey),
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			targetFullPathKey: {
+				Description: fmt.Sprintf("Path of the target, relative to the provider's URL, after applying the provider's `%s`", basePathKey),
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			targetURLKey: {
+				Description: "URL of the target",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			sha1Key: {
+				Description: "SHA1 checksum of the alias",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			targetSHA1Key: {
+				Description: "SHA1 checksum of the target",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+		},
+	}
+}
+
+// resourcePointerWrite creates or retargets an alias. The alias is replaced in place, so that it always points at
+// either its old or its new target, and never at nothing.
+func resourcePointerWrite(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*client.Client)
+
+	aliasPath, err := client.ResolvePath(d.Get(pointerPathKey).(string), nil)
+	if err != nil {
+		return diag.Errorf("unable to resolve %s: %s", pointerPathKey, err)
+	}
+
+	targetPath, err := client.ResolvePath(d.Get(targetPathKey).(string), nil)
+	if err != nil {
+		return diag.Errorf("unable to resolve %s: %s", targetPathKey, err)
+	}
+
+	targetChecksums, err := client.Checksums(ctx, targetPath)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if targetChecksums.SHA1 == "" {
+		return diag.Errorf("target %s doesn't exist", targetPath)
+	}
+
+	targetURL := client.FileURL(targetPath)
+	properties := map[string][]string{
+		pointerTargetProp: {targetPath},
+		pointerURLProp:    {targetURL},
+	}
+
+	switch d.Get(pointerModeKey).(string) {
+	case pointerModeCopy:
+		err = client.Copy(ctx, targetPath, aliasPath)
+	case pointerModeFile:
+		err = client.UploadContent(ctx, aliasPath, []byte(pointerContent(d, targetURL)), properties)
+	case pointerModeProperty:
+		err = client.UploadContent(ctx, aliasPath, nil, properties)
+	}
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(aliasPath)
+
+	return resourcePointerRead(ctx, d, meta)
+}
+
+func resourcePointerRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*client.Client)
+
+	aliasChecksums, err := client.Checksums(ctx, d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if aliasChecksums.SHA1 == "" {
+		d.SetId("")
+		return nil
+	}
+
+	targetPath, err := client.ResolvePath(d.Get(targetPathKey).(string), nil)
+	if err != nil {
+		return diag.Errorf("unable to resolve %s: %s", targetPathKey, err)
+	}
+
+	// a missing target leaves an empty checksum, so that a copied alias is updated once the target exists again
+	targetChecksums, err := client.Checksums(ctx, targetPath)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	for key, value := range map[string]string{
+		fullPathKey:       d.Id(),
+		targetFullPathKey: targetPath,
+		targetURLKey:      client.FileURL(targetPath),
+		sha1Key:           aliasChecksums.SHA1,
+		targetSHA1Key:     targetChecksums.SHA1,
+	} {
+		if err := d.Set(key, value); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	return nil
+}
+
+func resourcePointerDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*client.Client)
+
+	if err := client.Delete(ctx, d.Id()); err != nil {
+		return diag.Errorf("error attempting delete: %s", err)
+	}
+
+	return nil
+}
+
+func resourcePointerDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	c, ok := meta.(*client.Client)
+	if !ok {
+		return nil
+	}
+
+	if !d.NewValueKnown(targetPathKey) {
+		for _, key := range []string{targetFullPathKey, targetURLKey, sha1Key, targetSHA1Key} {
+			if err := d.SetNewComputed(key); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	targetPath, err := c.ResolvePath(d.Get(targetPathKey).(string), nil)
+	if err != nil {
+		return fmt.Errorf("unable to resolve %s: %s", targetPathKey, err)
+	}
+	targetURL := c.FileURL(targetPath)
+
+	if d.Id() == "" {
+		return nil
+	}
+
+	if targetPath != d.Get(targetFullPathKey).(string) {
+		if err := d.SetNew(targetFullPathKey, targetPath); err != nil {
+			return err
+		}
+		if err := d.SetNew(targetURLKey, targetURL); err != nil {
+			return err
+		}
+		return setNewComputedPointerChecksums(d)
+	}
+
+	if d.HasChanges(pointerModeKey, pointerContentKey) {
+		return setNewComputedPointerChecksums(d)
+	}
+
+	// the alias no longer matches what it would be written as, because the target or the alias changed remotely
+	drifted := false
+	switch d.Get(pointerModeKey).(string) {
+	case pointerModeCopy:
+		drifted = d.Get(sha1Key).(string) != d.Get(targetSHA1Key).(string)
+	case pointerModeFile:
+		if d.NewValueKnown(pointerContentKey) {
+			drifted = d.Get(sha1Key).(string) != client.ContentChecksums([]byte(pointerContent(d, targetURL))).SHA1
+		}
+	case pointerModeProperty:
+		drifted = d.Get(sha1Key).(string) != client.ContentChecksums(nil).SHA1
+	}
+	if drifted {
+		return setNewComputedPointerChecksums(d)
+	}
+
+	return nil
+}
+
+// pointerContent returns the contents of an alias in file mode.
+func pointerContent(d resourceGetter, targetURL string) string {
+	if content := d.Get(pointerContentKey).(string); content != "" {
+		return content
+	}
+
+	return targetURL
+}
+
+// setNewComputedPointerChecksums marks the alias and target checksums as "known after apply".
+func setNewComputedPointerChecksums(d *schema.ResourceDiff) error {
+	for _, key := range []string{sha1Key, targetSHA1Key} {
+		if err := d.SetNewComputed(key); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
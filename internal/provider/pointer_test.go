@@ -0,0 +1,82 @@
+// Copyright 2021 Splunk, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccResourcePointer(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV5ProviderFactories: protoV5ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testResourcePointerCopyConfig,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("artifacts_pointer.test", "full_path", "sas-binary/terraform-provider-artifacts-test/latest.txt"),
+					resource.TestCheckResourceAttr("artifacts_pointer.test", "sha1", "af3d968c42b3046f86296c7522b3b20dfdc58c59"),
+					resource.TestCheckResourceAttr("artifacts_pointer.test", "target_sha1", "af3d968c42b3046f86296c7522b3b20dfdc58c59"),
+				),
+			},
+			{
+				Config: testResourcePointerFileConfig,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("artifacts_pointer.test", "full_path", "sas-binary/terraform-provider-artifacts-test/latest.txt"),
+					resource.TestCheckResourceAttr("artifacts_pointer.test", "target_full_path", "sas-binary/terraform-provider-artifacts-test/1.0.0/test_file.txt"),
+					// sha1 of "1.0.0"
+					resource.TestCheckResourceAttr("artifacts_pointer.test", "sha1", "91e95be6b6634e3c21072dfcd661146728694326"),
+				),
+			},
+		},
+	})
+}
+
+const testResourcePointerCopyConfig = `
+provider "artifacts" {
+  url = "https://repo.splunk.com/artifactory"
+}
+
+resource "artifacts_upload" "test" {
+  upload_path = "sas-binary/terraform-provider-artifacts-test/1.0.0/test_file.txt"
+  upload_file = "test_files/source_file.txt"
+}
+
+resource "artifacts_pointer" "test" {
+  path        = "sas-binary/terraform-provider-artifacts-test/latest.txt"
+  target_path = artifacts_upload.test.upload_path
+}
+`
+
+// the provider's base_path applies to target_path once, as it does to path
+const testResourcePointerFileConfig = `
+provider "artifacts" {
+  url       = "https://repo.splunk.com/artifactory"
+  base_path = "sas-binary/terraform-provider-artifacts-test"
+}
+
+resource "artifacts_upload" "test" {
+  upload_path = "1.0.0/test_file.txt"
+  upload_file = "test_files/source_file.txt"
+}
+
+resource "artifacts_pointer" "test" {
+  path        = "latest.txt"
+  target_path = artifacts_upload.test.upload_path
+  mode        = "file"
+  content     = "1.0.0"
+}
+`
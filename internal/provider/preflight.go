@@ -0,0 +1,62 @@
+// Copyright 2021 Splunk, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"terraform-provider-artifacts/internal/provider/internal/client"
+)
+
+// preflight checks that the service is reachable and accepts the client's credentials, so that a bad provider
+// configuration is reported before any resources are changed. credentialsKey is the attribute that configured the
+// client's credentials, which errors about them point at.
+func preflight(ctx context.Context, c *client.Client, credentialsKey string) diag.Diagnostics {
+	if err := c.Ping(ctx); err != nil {
+		return attributeError(urlKey, "Unable to reach Artifactory", fmt.Sprintf("The preflight check of %s failed: %s", c.URL, err))
+	}
+
+	user, err := c.Authenticate(ctx)
+	if err != nil {
+		var authErr *client.AuthError
+		if errors.As(err, &authErr) {
+			if credentialsKey == "" {
+				return attributeError(urlKey, "Artifactory requires credentials", fmt.Sprintf("%s rejected the provider's anonymous requests: %s", c.URL, err))
+			}
+			return attributeError(credentialsKey, "Artifactory rejected the provider's credentials", fmt.Sprintf("The credentials configured by %s were rejected by %s: %s", credentialsKey, c.URL, err))
+		}
+
+		return attributeError(urlKey, "Unable to verify credentials", fmt.Sprintf("The preflight check of %s failed: %s", c.URL, err))
+	}
+
+	info, err := c.SystemInfo(ctx)
+	if err != nil {
+		// the version is only informational, so don't fail over it
+		return diag.Diagnostics{diag.NewWarningDiagnostic("Unable to determine Artifactory version", err.Error())}
+	}
+
+	tflog.Info(ctx, "Artifactory preflight check succeeded", map[string]interface{}{
+		"url":                 c.URL,
+		"artifactory_version": info.Version,
+		"user":                user,
+	})
+
+	return nil
+}
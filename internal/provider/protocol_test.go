@@ -0,0 +1,112 @@
+// Copyright 2021 Splunk, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// objectValue returns a value of an object type with values for some of its attributes, and nulls for the rest.
+func objectValue(objectType tftypes.Object, values map[string]tftypes.Value) tftypes.Value {
+	attributes := map[string]tftypes.Value{}
+	for key, attributeType := range objectType.AttributeTypes {
+		attributes[key] = tftypes.NewValue(attributeType, nil)
+		if value, ok := values[key]; ok {
+			attributes[key] = value
+		}
+	}
+
+	return tftypes.NewValue(objectType, attributes)
+}
+
+// checkDiagnostics fails the test if any of diagnostics are errors.
+func checkDiagnostics(t *testing.T, diagnostics []*tfprotov5.Diagnostic) {
+	t.Helper()
+
+	for _, diagnostic := range diagnostics {
+		if diagnostic.Severity == tfprotov5.DiagnosticSeverityError {
+			t.Fatalf("%s: %s", diagnostic.Summary, diagnostic.Detail)
+		}
+	}
+}
+
+// configuredProviderServer returns the provider's server, configured with url, and its schemas.
+func configuredProviderServer(t *testing.T, url string) (tfprotov5.ProviderServer, *tfprotov5.GetProviderSchemaResponse) {
+	t.Helper()
+
+	ctx := context.Background()
+	providerServer, err := ProtoV5ProviderServer("dev")()
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	schemas, err := providerServer.GetProviderSchema(ctx, &tfprotov5.GetProviderSchemaRequest{})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	providerType := schemas.Provider.ValueType().(tftypes.Object)
+	providerConfig, err := tfprotov5.NewDynamicValue(providerType, objectValue(providerType, map[string]tftypes.Value{
+		urlKey: tftypes.NewValue(tftypes.String, url),
+	}))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	configureResp, err := providerServer.ConfigureProvider(ctx, &tfprotov5.ConfigureProviderRequest{Config: &providerConfig})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	checkDiagnostics(t, configureResp.Diagnostics)
+
+	return providerServer, schemas
+}
+
+// dynamicValue returns value as a tfprotov5.DynamicValue of valueType.
+func dynamicValue(t *testing.T, valueType tftypes.Type, value tftypes.Value) *tfprotov5.DynamicValue {
+	t.Helper()
+
+	dynamic, err := tfprotov5.NewDynamicValue(valueType, value)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	return &dynamic
+}
+
+// proposedNewState returns the proposed new state that Terraform would plan from prior and config, which takes
+// computed attributes that aren't configured from prior.
+func proposedNewState(t *testing.T, schema *tfprotov5.Schema, prior tftypes.Value, config tftypes.Value) tftypes.Value {
+	t.Helper()
+
+	priorAttributes := map[string]tftypes.Value{}
+	configAttributes := map[string]tftypes.Value{}
+	if err := prior.As(&priorAttributes); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if err := config.As(&configAttributes); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	for _, attribute := range schema.Block.Attributes {
+		if attribute.Computed && configAttributes[attribute.Name].IsNull() {
+			configAttributes[attribute.Name] = priorAttributes[attribute.Name]
+		}
+	}
+
+	return tftypes.NewValue(config.Type(), configAttributes)
+}
@@ -17,63 +17,314 @@ package provider
 import (
 	"context"
 	"fmt"
+	"os"
+	"strings"
 
-	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
-	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/provider"
+	providerschema "github.com/hashicorp/terraform-plugin-framework/provider/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
 
 	"terraform-provider-artifacts/internal/provider/internal/client"
 )
 
-func configure(version string, p *schema.Provider) func(context.Context, *schema.ResourceData) (interface{}, diag.Diagnostics) {
-	return func(ctx context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
-		client := client.Client{
-			URL:     d.Get(urlKey).(string),
-			Context: ctx,
-		}
+const (
+	providerTypeName            = "artifacts"
+	defaultMultipartPartSize    = 100 * 1024 * 1024
+	minMultipartPartSize        = 5 * 1024 * 1024
+	defaultMultipartConcurrency = 4
+)
 
-		if usernameInterface, ok := d.GetOk(usernameKey); ok {
-			client.Username = usernameInterface.(string)
-			// we're counting on RequiredWith functionality to be valid, so set Password if Username is given
-			client.Password = d.Get(passwordKey).(string)
-		}
+// artifactsProvider is the plugin framework provider. Resources that haven't been ported to the framework are served
+// by the SDKv2 provider returned by newSDKProvider, which uses the client this provider configures.
+type artifactsProvider struct {
+	version string
+	// client is set when the provider is configured.
+	client *client.Client
+}
+
+// providerModel is the provider's configuration.
+type providerModel struct {
+	URL                    types.String `tfsdk:"url"`
+	Username               types.String `tfsdk:"username"`
+	Password               types.String `tfsdk:"password"`
+	AccessToken            types.String `tfsdk:"access_token"`
+	CredentialHelper       types.List   `tfsdk:"credential_helper"`
+	JFrogCLIServerID       types.String `tfsdk:"jfrog_cli_server_id"`
+	JFrogCLIConfigFile     types.String `tfsdk:"jfrog_cli_config_file"`
+	Netrc                  types.Bool   `tfsdk:"netrc"`
+	NetrcFile              types.String `tfsdk:"netrc_file"`
+	ChecksumType           types.String `tfsdk:"checksum_type"`
+	MultipartThreshold     types.Int64  `tfsdk:"multipart_threshold"`
+	MultipartPartSize      types.Int64  `tfsdk:"multipart_part_size"`
+	MultipartConcurrency   types.Int64  `tfsdk:"multipart_concurrency"`
+	MultipartStateDir      types.String `tfsdk:"multipart_state_dir"`
+	MaxConcurrentTransfers types.Int64  `tfsdk:"max_concurrent_transfers"`
+	MaxBytesPerSecond      types.Int64  `tfsdk:"max_bytes_per_second"`
+	Preflight              types.Bool   `tfsdk:"preflight"`
+	BasePath               types.String `tfsdk:"base_path"`
+	PathVars               types.Map    `tfsdk:"path_vars"`
+}
 
-		return &client, nil
+// New returns a function that returns a new plugin framework provider.
+func New(version string) func() provider.Provider {
+	return func() provider.Provider {
+		return &artifactsProvider{version: version}
 	}
 }
 
-// New returns a function that returns a pointer to a new schema.Provider for this provider.
-func New(version string) func() *schema.Provider {
-	return func() *schema.Provider {
-		p := &schema.Provider{
-			Schema: map[string]*schema.Schema{
-				urlKey: {
-					Type:        schema.TypeString,
-					Required:    true,
-					Description: "URL of the Artifactory service",
-				},
-				usernameKey: {
-					Type: schema.TypeString,
-					// username is optional because this provider may add a "download" data source or other functionality
-					// that doesn't always require authentication.
-					Optional:    true,
-					DefaultFunc: schema.EnvDefaultFunc(usernameEnvKey, nil),
-					Description: fmt.Sprintf("Username used to authenticate to Artifactory. May be set via the `%s` environment variable instead.", usernameEnvKey),
-				},
-				passwordKey: {
-					Type:         schema.TypeString,
-					Optional:     true,
-					DefaultFunc:  schema.EnvDefaultFunc(passwordEnvKey, nil),
-					RequiredWith: []string{usernameKey},
-					Description:  fmt.Sprintf("Password used to authenticate to Artifactory. Must be set if %s is set. May be set via the `%s` environment variable instead.", usernameKey, passwordEnvKey),
-				},
-			},
-			ResourcesMap: map[string]*schema.Resource{
-				uploadResourceKey: resourceUpload(),
+// Metadata implements provider.Provider.
+func (p *artifactsProvider) Metadata(ctx context.Context, req provider.MetadataRequest, resp *provider.MetadataResponse) {
+	resp.TypeName = providerTypeName
+	resp.Version = p.version
+}
+
+// Schema implements provider.Provider.
+func (p *artifactsProvider) Schema(ctx context.Context, req provider.SchemaRequest, resp *provider.SchemaResponse) {
+	resp.Schema = providerSchema()
+}
+
+// providerSchema returns the provider's schema, which the SDKv2 provider's schema is derived from.
+func providerSchema() providerschema.Schema {
+	return providerschema.Schema{
+		Attributes: map[string]providerschema.Attribute{
+			urlKey: providerschema.StringAttribute{
+				Required:    true,
+				Description: "URL of the Artifactory service",
+			},
+			usernameKey: providerschema.StringAttribute{
+				// username is optional because this provider may add a "download" data source or other functionality
+				// that doesn't always require authentication.
+				Optional:    true,
+				Description: fmt.Sprintf("Username used to authenticate to Artifactory. May be set via the `%s` environment variable instead.", usernameEnvKey),
+			},
+			passwordKey: providerschema.StringAttribute{
+				Optional:    true,
+				Sensitive:   true,
+				Validators:  []validator.String{stringvalidator.AlsoRequires(path.MatchRoot(usernameKey))},
+				Description: fmt.Sprintf("Password used to authenticate to Artifactory. Must be set if %s is set. May be set via the `%s` environment variable instead.", usernameKey, passwordEnvKey),
+			},
+			accessTokenKey: providerschema.StringAttribute{
+				Optional:    true,
+				Sensitive:   true,
+				Validators:  []validator.String{stringvalidator.ConflictsWith(path.MatchRoot(usernameKey))},
+				Description: fmt.Sprintf("Access token used to authenticate to Artifactory, as an alternative to %s and %s. May be set via the `%s` environment variable instead.", usernameKey, passwordKey, accessTokenEnvKey),
+			},
+			credentialHelperKey: providerschema.ListAttribute{
+				ElementType: types.StringType,
+				Optional:    true,
+				Description: fmt.Sprintf("Command, and its arguments, run to obtain credentials when neither %s nor %s are set. It must print a JSON object with either `username` and `password`, or `token`. The command is run at most once, when credentials are first needed, with the `%s` environment variable set to the provider's URL.", usernameKey, accessTokenKey, client.CredentialHelperURLEnvVar),
+			},
+			jfrogCLIServerIDKey: providerschema.StringAttribute{
+				Optional:    true,
+				Description: fmt.Sprintf("ID of a server in the JFrog CLI configuration whose credentials are used when neither %s, %s, nor %s are set. The server's access token is used if it has one, otherwise its user and password.", usernameKey, accessTokenKey, credentialHelperKey),
+			},
+			jfrogCLIConfigFileKey: providerschema.StringAttribute{
+				Optional:    true,
+				Description: fmt.Sprintf("JFrog CLI configuration file to read %s from. Defaults to `jfrog-cli.conf.v6` in `$JFROG_CLI_HOME_DIR`, or in `~/.jfrog`.", jfrogCLIServerIDKey),
+			},
+			netrcKey: providerschema.BoolAttribute{
+				Optional:    true,
+				Description: fmt.Sprintf("Set to true to use the netrc entry matching the host of %s when no other credentials are set.", urlKey),
+			},
+			netrcFileKey: providerschema.StringAttribute{
+				Optional:    true,
+				Description: "Netrc file to read credentials from. Defaults to the file named by `$NETRC`, or `~/.netrc`.",
+			},
+			checksumTypeKey: providerschema.StringAttribute{
+				Optional:    true,
+				Validators:  []validator.String{stringvalidator.OneOf(checksumTypeStrings()...)},
+				Description: fmt.Sprintf("Checksum used to determine if an uploaded file is missing or differs from its local file. One of %s. Defaults to `%s`. Useful for remote repositories that don't populate every checksum.", strings.Join(checksumTypeStrings(), ", "), client.ChecksumSHA1),
 			},
+			multipartThresholdKey: providerschema.Int64Attribute{
+				Optional:    true,
+				Validators:  []validator.Int64{int64validator.AtLeast(0)},
+				Description: "Size in bytes at or above which files are uploaded in parts using Artifactory's multipart upload API, when the service supports it. Files are uploaded with a single request otherwise. Defaults to 0, which disables multipart uploads.",
+			},
+			multipartPartSizeKey: providerschema.Int64Attribute{
+				Optional:    true,
+				Validators:  []validator.Int64{int64validator.AtLeast(minMultipartPartSize), multipleOfValidator{unit: client.MultipartPartSizeUnit, unitName: "1 MiB"}},
+				Description: fmt.Sprintf("Size in bytes of each part of a multipart upload, which must be a multiple of %d (1 MiB), as the service accepts part sizes in whole MiB. Defaults to %d (100 MiB).", client.MultipartPartSizeUnit, defaultMultipartPartSize),
+			},
+			multipartConcurrencyKey: providerschema.Int64Attribute{
+				Optional:    true,
+				Validators:  []validator.Int64{int64validator.AtLeast(1)},
+				Description: fmt.Sprintf("Number of parts of a multipart upload to upload in parallel. Defaults to %d.", defaultMultipartConcurrency),
+			},
+			multipartStateDirKey: providerschema.StringAttribute{
+				Optional:    true,
+				Description: "Directory in which the progress of multipart uploads is saved, allowing a failed upload to resume when applied again. Defaults to a directory in the user's cache directory.",
+			},
+			maxConcurrentTransfersKey: providerschema.Int64Attribute{
+				Optional:    true,
+				Validators:  []validator.Int64{int64validator.AtLeast(0)},
+				Description: "Maximum number of file transfers performed at once across all resources, independent of Terraform's parallelism. Metadata requests, such as reading checksums, aren't limited. Defaults to 0, which is unlimited.",
+			},
+			maxBytesPerSecondKey: providerschema.Int64Attribute{
+				Optional:    true,
+				Validators:  []validator.Int64{int64validator.AtLeast(0)},
+				Description: "Maximum combined bandwidth, in bytes per second, of all file transfers. Defaults to 0, which is unlimited.",
+			},
+			preflightKey: providerschema.BoolAttribute{
+				Optional:    true,
+				Description: "Set to true to check that Artifactory is reachable and accepts the provider's credentials when the provider is configured, before any resources are changed.",
+			},
+			basePathKey: providerschema.StringAttribute{
+				Optional:    true,
+				Validators:  []validator.String{stringValidator{description: "value must be a valid path", validate: validateBasePath}},
+				Description: "Path prepended to the paths of all resources, typically a repository and a prefix within it, such as `my-repo/my-team`. Paths are joined with a single slash, and may not contain `..` or empty segments.",
+			},
+			pathVarsKey: providerschema.MapAttribute{
+				ElementType: types.StringType,
+				Optional:    true,
+				Description: "Default values of `{name}` tokens in resource paths, such as `version`, `os`, or `arch`. Resources may override individual values.",
+			},
+		},
+	}
+}
+
+// validateBasePath is a schema.SchemaValidateFunc that checks base_path can be joined with resource paths.
+func validateBasePath(value interface{}, key string) ([]string, []error) {
+	if _, err := client.JoinPath(value.(string)); err != nil {
+		return nil, []error{fmt.Errorf("invalid %s: %s", key, err)}
+	}
+
+	return nil, nil
+}
+
+// Configure implements provider.Provider.
+func (p *artifactsProvider) Configure(ctx context.Context, req provider.ConfigureRequest, resp *provider.ConfigureResponse) {
+	config := providerModel{}
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	c, diags := configureClient(ctx, config)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	p.client = c
+	resp.ResourceData = c
+	resp.DataSourceData = c
+	resp.EphemeralResourceData = c
+}
+
+// configureClient returns the client configured by config, after its preflight check if one is enabled.
+func configureClient(ctx context.Context, config providerModel) (*client.Client, diag.Diagnostics) {
+	diags := diag.Diagnostics{}
+
+	pathVars := map[string]string{}
+	diags.Append(config.PathVars.ElementsAs(ctx, &pathVars, false)...)
+	if diags.HasError() {
+		return nil, diags
+	}
+
+	c := &client.Client{
+		URL:          config.URL.ValueString(),
+		ChecksumType: client.ChecksumType(stringOrDefault(config.ChecksumType, string(client.ChecksumSHA1))),
+		Multipart: client.MultipartOptions{
+			Threshold:   config.MultipartThreshold.ValueInt64(),
+			PartSize:    int64OrDefault(config.MultipartPartSize, defaultMultipartPartSize),
+			Concurrency: int(int64OrDefault(config.MultipartConcurrency, defaultMultipartConcurrency)),
+			StateDir:    config.MultipartStateDir.ValueString(),
+		},
+		Transfers: client.NewTransferLimiter(
+			int(config.MaxConcurrentTransfers.ValueInt64()),
+			config.MaxBytesPerSecond.ValueInt64(),
+		),
+		Locks:    client.NewPathLocks(),
+		BasePath: config.BasePath.ValueString(),
+		PathVars: pathVars,
+	}
+
+	credentialsKey, credentialsDiags := configureCredentials(ctx, config, c)
+	diags.Append(credentialsDiags...)
+	if diags.HasError() {
+		return nil, diags
+	}
+
+	c.LogSettings(ctx)
+
+	if config.Preflight.ValueBool() {
+		diags.Append(preflight(ctx, c, credentialsKey)...)
+		if diags.HasError() {
+			return nil, diags
 		}
+	}
+
+	return c, diags
+}
+
+// stringOrDefault returns the value of value, or defaultValue if it is null, unknown, or empty.
+func stringOrDefault(value types.String, defaultValue string) string {
+	if value.ValueString() == "" {
+		return defaultValue
+	}
+
+	return value.ValueString()
+}
+
+// stringOrEnv returns the value of value, or of the environment variable envKey if it is null, unknown, or empty.
+func stringOrEnv(value types.String, envKey string) string {
+	return stringOrDefault(value, os.Getenv(envKey))
+}
+
+// int64OrDefault returns the value of value, or defaultValue if it is null or unknown.
+func int64OrDefault(value types.Int64, defaultValue int64) int64 {
+	if value.IsNull() || value.IsUnknown() {
+		return defaultValue
+	}
+
+	return value.ValueInt64()
+}
+
+// Resources implements provider.Provider.
+func (p *artifactsProvider) Resources(ctx context.Context) []func() resource.Resource {
+	return []func() resource.Resource{
+		newUploadResource,
+	}
+}
+
+// DataSources implements provider.Provider.
+func (p *artifactsProvider) DataSources(ctx context.Context) []func() datasource.DataSource {
+	return nil
+}
 
-		p.ConfigureContextFunc = configure(version, p)
+// EphemeralResources implements provider.ProviderWithEphemeralResources.
+func (p *artifactsProvider) EphemeralResources(ctx context.Context) []func() ephemeral.EphemeralResource {
+	return []func() ephemeral.EphemeralResource{
+		newAccessTokenEphemeralResource,
+		newSignedURLEphemeralResource,
+	}
+}
 
-		return p
+// Functions implements provider.ProviderWithFunctions.
+func (p *artifactsProvider) Functions(ctx context.Context) []func() function.Function {
+	return []func() function.Function{
+		newPathJoinFunction,
+		newDownloadURLFunction,
+		newMavenPathFunction,
+		newFileSHA256Function,
 	}
 }
+
+// checksumTypeStrings returns the supported checksum types as strings, for schema validation and descriptions.
+func checksumTypeStrings() []string {
+	types := make([]string, len(client.ChecksumTypes))
+	for i, checksumType := range client.ChecksumTypes {
+		types[i] = string(checksumType)
+	}
+
+	return types
+}
@@ -15,21 +15,50 @@
 package provider
 
 import (
-	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"context"
 	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
 )
 
-// providerFactories are used to instantiate a provider during acceptance testing.
+// protoV5ProviderFactories are used to instantiate a provider during acceptance testing.
 // The factory function will be invoked for every Terraform CLI command executed
 // to create a provider server to which the CLI can reattach.
-var providerFactories = map[string]func() (*schema.Provider, error){
-	"artifacts": func() (*schema.Provider, error) {
-		return New("dev")(), nil
-	},
+var protoV5ProviderFactories = map[string]func() (tfprotov5.ProviderServer, error){
+	"artifacts": ProtoV5ProviderServer("dev"),
 }
 
 func TestProvider(t *testing.T) {
-	if err := New("dev")().InternalValidate(); err != nil {
+	if err := newSDKProvider(&artifactsProvider{version: "dev"}).InternalValidate(); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+}
+
+func TestProviderServer(t *testing.T) {
+	server, err := ProtoV5ProviderServer("dev")()
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	// the mux server reports differences between the schemas of the framework and SDKv2 providers
+	resp, err := server.GetProviderSchema(context.Background(), &tfprotov5.GetProviderSchemaRequest{})
+	if err != nil {
 		t.Fatalf("err: %s", err)
 	}
+	for _, diagnostic := range resp.Diagnostics {
+		if diagnostic.Severity == tfprotov5.DiagnosticSeverityError {
+			t.Errorf("%s: %s", diagnostic.Summary, diagnostic.Detail)
+		}
+	}
+
+	for _, name := range []string{uploadResourceKey, "artifacts_archive_upload"} {
+		if _, ok := resp.ResourceSchemas[name]; !ok {
+			t.Errorf("missing schema of %s", name)
+		}
+	}
+	for _, name := range []string{pathJoinFunctionKey, downloadURLFunctionKey, mavenPathFunctionKey, fileSHA256FunctionKey} {
+		if _, ok := resp.Functions[name]; !ok {
+			t.Errorf("missing function %s", name)
+		}
+	}
 }
@@ -0,0 +1,395 @@
+// Copyright 2021 Splunk, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+
+	"terraform-provider-artifacts/internal/provider/internal/client"
+)
+
+func resourceRetention() *schema.Resource {
+	return &schema.Resource{
+		Description:   "Delete old versions of the files in a folder in Artifactory, keeping the newest versions. Folders left empty by deleting a version's files are also deleted.",
+		CreateContext: resourceRetentionApply,
+		ReadContext:   resourceRetentionRead,
+		UpdateContext: resourceRetentionApply,
+		DeleteContext: resourceRetentionDelete,
+		CustomizeDiff: resourceRetentionDiff,
+		Schema: map[string]*schema.Schema{
+			retentionPathKey: {
+				Description: fmt.Sprintf("Path of the folder, relative to the provider's URL and `%s`", basePathKey),
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+			},
+			versionRegexKey: {
+				Description: "Regular expression matched against the path of each file relative to the folder, such as `^([^/]+)/`. " +
+					"Files are grouped into versions by the expression's `version` named group, its first group if it has no `version` group, " +
+					"or the whole match if it has no groups. Files that don't match are never deleted.",
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.StringIsValidRegExp,
+			},
+			keepLatestKey: {
+				Description:  "Number of versions to keep, newest first, by the most recent modification of any of their files. Older versions are deleted.",
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ValidateFunc: validation.IntAtLeast(1),
+				AtLeastOneOf: []string{keepLatestKey, maxAgeKey},
+			},
+			maxAgeKey: {
+				Description:  "Versions not modified for longer than this are deleted, even if they are within `keep_latest`. It is a duration such as `720h`, or a number of days such as `30d`.",
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateAge,
+				AtLeastOneOf: []string{keepLatestKey, maxAgeKey},
+			},
+			pinPropertyKey: {
+				Description: fmt.Sprintf("Versions with any file that has this property are never deleted, but still count towards `%s`. Defaults to `%s`. "+
+					"Pinned files are found with a single AQL search, which the provider's credentials must be allowed to run.", keepLatestKey, defaultPinProperty),
+				Type:     schema.TypeString,
+				Optional: true,
+				Default:  defaultPinProperty,
+			},
+			dryRunKey: {
+				Description: fmt.Sprintf("Set to true to only report the files that would be deleted in `%s`, without deleting them. Defaults to false.", candidatesKey),
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+			},
+			fullPathKey: {
+				Description: fmt.Sprintf("Path of the folder, relative to the provider's URL, after applying the provider's `%s`", basePathKey),
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			retainedVersionsKey: {
+				Description: "Versions that are kept, newest first",
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			candidatesKey: {
+				Description: "Paths of the files that are due to be deleted, relative to the provider's URL. Any are deleted by the next apply, unless `dry_run` is set.",
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			deletedKey: {
+				Description: "Paths of the files deleted by the most recent apply, relative to the provider's URL",
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+		},
+	}
+}
+
+// retentionVersion is a group of files that share a version.
+type retentionVersion struct {
+	name     string
+	files    []client.FileListing
+	modified time.Time
+}
+
+// retentionPlan is the outcome of applying a retention policy to a folder.
+type retentionPlan struct {
+	retained   []string
+	candidates []string
+}
+
+// resourceRetentionApply deletes the files of expired versions, or only records them when dry_run is set.
+func resourceRetentionApply(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*client.Client)
+
+	folderPath, err := client.ResolvePath(d.Get(retentionPathKey).(string), nil)
+	if err != nil {
+		return diag.Errorf("unable to resolve %s: %s", retentionPathKey, err)
+	}
+
+	plan, err := planRetention(ctx, d, client, folderPath)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(folderPath)
+
+	deleted := []string{}
+	if !d.Get(dryRunKey).(bool) {
+		for _, path := range plan.candidates {
+			if err := client.Delete(ctx, path); err != nil {
+				// record what was deleted before the failure
+				_ = d.Set(deletedKey, deleted)
+				return diag.Errorf("error attempting delete: %s", err)
+			}
+			deleted = append(deleted, path)
+		}
+
+		if err := deleteEmptyFolders(ctx, client, folderPath, deleted); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	if err := d.Set(deletedKey, deleted); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return resourceRetentionRead(ctx, d, meta)
+}
+
+func resourceRetentionRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*client.Client)
+
+	plan, err := planRetention(ctx, d, client, d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	for key, value := range map[string]interface{}{
+		fullPathKey:         d.Id(),
+		retainedVersionsKey: plan.retained,
+		candidatesKey:       plan.candidates,
+	} {
+		if err := d.Set(key, value); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	return nil
+}
+
+// resourceRetentionDelete only removes the policy from state, leaving the folder and its files in place.
+func resourceRetentionDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	return nil
+}
+
+func resourceRetentionDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if d.Id() == "" {
+		return nil
+	}
+
+	// changed settings, or files found due for deletion when state was refreshed, mean there's something to apply
+	if d.HasChanges(versionRegexKey, keepLatestKey, maxAgeKey, pinPropertyKey, dryRunKey) ||
+		(!d.Get(dryRunKey).(bool) && len(d.Get(candidatesKey).([]interface{})) > 0) {
+		for _, key := range []string{retainedVersionsKey, candidatesKey, deletedKey} {
+			if err := d.SetNewComputed(key); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// planRetention lists the files in the folder at folderPath and returns the versions to keep and the paths of the
+// files to delete, according to the policy in d.
+func planRetention(ctx context.Context, d resourceGetter, c *client.Client, folderPath string) (retentionPlan, error) {
+	plan := retentionPlan{retained: []string{}, candidates: []string{}}
+
+	versionRegex, err := regexp.Compile(d.Get(versionRegexKey).(string))
+	if err != nil {
+		return plan, fmt.Errorf("invalid %s: %s", versionRegexKey, err)
+	}
+
+	var maxAge time.Duration
+	if age := d.Get(maxAgeKey).(string); age != "" {
+		if maxAge, err = parseAge(age); err != nil {
+			return plan, fmt.Errorf("invalid %s: %s", maxAgeKey, err)
+		}
+	}
+
+	files, found, err := c.ListFiles(ctx, folderPath)
+	if err != nil {
+		return plan, err
+	}
+	if !found {
+		// nothing has been uploaded yet
+		return plan, nil
+	}
+
+	versions := groupVersions(files, versionRegex)
+	kept, expired := expireVersions(versions, d.Get(keepLatestKey).(int), maxAge, time.Now())
+
+	// a single search finds pinned files, rather than reading the properties of every expired file on each refresh
+	pinnedFiles := map[string]bool{}
+	if pinProperty := d.Get(pinPropertyKey).(string); pinProperty != "" && len(expired) > 0 {
+		if pinnedFiles, err = c.FilesWithProperty(ctx, folderPath, pinProperty); err != nil {
+			return plan, err
+		}
+	}
+
+	for _, version := range expired {
+		if versionPinned(version, pinnedFiles) {
+			kept = append(kept, version)
+			continue
+		}
+
+		for _, file := range version.files {
+			plan.candidates = append(plan.candidates, folderPath+"/"+file.Path)
+		}
+	}
+
+	sortVersions(kept)
+	for _, version := range kept {
+		plan.retained = append(plan.retained, version.name)
+	}
+	sort.Strings(plan.candidates)
+
+	return plan, nil
+}
+
+// groupVersions groups files into versions by versionRegex, newest first. Files that don't match are ignored.
+func groupVersions(files []client.FileListing, versionRegex *regexp.Regexp) []retentionVersion {
+	groupIndex := 0
+	if versionRegex.NumSubexp() > 0 {
+		groupIndex = 1
+	}
+	if index := versionRegex.SubexpIndex("version"); index > 0 {
+		groupIndex = index
+	}
+
+	versionsByName := map[string]*retentionVersion{}
+	for _, file := range files {
+		match := versionRegex.FindStringSubmatch(file.Path)
+		if match == nil || match[groupIndex] == "" {
+			continue
+		}
+
+		name := match[groupIndex]
+		version, ok := versionsByName[name]
+		if !ok {
+			version = &retentionVersion{name: name}
+			versionsByName[name] = version
+		}
+
+		version.files = append(version.files, file)
+		if file.LastModified.After(version.modified) {
+			version.modified = file.LastModified
+		}
+	}
+
+	versions := make([]retentionVersion, 0, len(versionsByName))
+	for _, version := range versionsByName {
+		versions = append(versions, *version)
+	}
+	sortVersions(versions)
+
+	return versions
+}
+
+// sortVersions sorts versions newest first, breaking ties by name so the order is stable.
+func sortVersions(versions []retentionVersion) {
+	sort.Slice(versions, func(i, j int) bool {
+		if !versions[i].modified.Equal(versions[j].modified) {
+			return versions[i].modified.After(versions[j].modified)
+		}
+		return versions[i].name > versions[j].name
+	})
+}
+
+// expireVersions splits versions, which are sorted newest first, into those kept and those beyond keepLatest or
+// older than maxAge. Zero values of keepLatest and maxAge don't limit versions.
+func expireVersions(versions []retentionVersion, keepLatest int, maxAge time.Duration, now time.Time) (kept []retentionVersion, expired []retentionVersion) {
+	for i, version := range versions {
+		if (keepLatest > 0 && i >= keepLatest) || (maxAge > 0 && now.Sub(version.modified) > maxAge) {
+			expired = append(expired, version)
+			continue
+		}
+
+		kept = append(kept, version)
+	}
+
+	return kept, expired
+}
+
+// versionPinned returns true if any of a version's files is in pinnedFiles.
+func versionPinned(version retentionVersion, pinnedFiles map[string]bool) bool {
+	for _, file := range version.files {
+		if pinnedFiles[file.Path] {
+			return true
+		}
+	}
+
+	return false
+}
+
+// deleteEmptyFolders deletes the folders within folderPath that contained deleted files and are now empty, along with
+// any of their parents, up to but not including folderPath, that are left empty in turn.
+func deleteEmptyFolders(ctx context.Context, c *client.Client, folderPath string, deleted []string) error {
+	folders := map[string]bool{}
+	for _, file := range deleted {
+		for folder := path.Dir(file); strings.HasPrefix(folder, folderPath+"/"); folder = path.Dir(folder) {
+			folders[folder] = true
+		}
+	}
+
+	// deepest first, so that a parent is only checked once its emptied children are gone
+	sorted := make([]string, 0, len(folders))
+	for folder := range folders {
+		sorted = append(sorted, folder)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return strings.Count(sorted[i], "/") > strings.Count(sorted[j], "/")
+	})
+
+	for _, folder := range sorted {
+		info, found, err := c.Folder(ctx, folder)
+		if err != nil {
+			return err
+		}
+
+		if found && len(info.Children) == 0 {
+			if err := c.Delete(ctx, folder); err != nil {
+				return fmt.Errorf("unable to delete empty folder %s: %s", folder, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// parseAge parses a duration, which may also be a whole number of days such as "30d".
+func parseAge(age string) (time.Duration, error) {
+	if days := strings.TrimSuffix(age, "d"); days != age {
+		count, err := strconv.Atoi(days)
+		if err != nil || count < 0 {
+			return 0, fmt.Errorf("%q isn't a number of days", age)
+		}
+
+		return time.Duration(count) * 24 * time.Hour, nil
+	}
+
+	return time.ParseDuration(age)
+}
+
+// validateAge is a schema.SchemaValidateFunc for ages parsed by parseAge.
+func validateAge(value interface{}, key string) ([]string, []error) {
+	if _, err := parseAge(value.(string)); err != nil {
+		return nil, []error{fmt.Errorf("invalid %s: %s", key, err)}
+	}
+
+	return nil, nil
+}
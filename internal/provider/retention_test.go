@@ -0,0 +1,203 @@
+// Copyright 2021 Splunk, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"terraform-provider-artifacts/internal/provider/internal/client"
+)
+
+func TestRetentionVersions(t *testing.T) {
+	now := time.Date(2021, 12, 1, 0, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+
+	files := []client.FileListing{
+		{Path: "1.0.0/tool-linux.tgz", LastModified: now.Add(-40 * day)},
+		{Path: "1.0.0/tool-darwin.tgz", LastModified: now.Add(-39 * day)},
+		{Path: "1.1.0/tool-linux.tgz", LastModified: now.Add(-20 * day)},
+		{Path: "1.2.0/tool-linux.tgz", LastModified: now.Add(-10 * day)},
+		{Path: "2.0.0/tool-linux.tgz", LastModified: now.Add(-1 * day)},
+		{Path: "latest.txt", LastModified: now.Add(-50 * day)},
+	}
+
+	tests := []struct {
+		name        string
+		regex       string
+		keepLatest  int
+		maxAge      time.Duration
+		wantKept    []string
+		wantExpired []string
+	}{
+		{
+			name:        "keep latest",
+			regex:       `^([^/]+)/`,
+			keepLatest:  2,
+			wantKept:    []string{"2.0.0", "1.2.0"},
+			wantExpired: []string{"1.1.0", "1.0.0"},
+		},
+		{
+			name:        "max age",
+			regex:       `^(?P<version>[^/]+)/`,
+			maxAge:      15 * day,
+			wantKept:    []string{"2.0.0", "1.2.0"},
+			wantExpired: []string{"1.1.0", "1.0.0"},
+		},
+		{
+			name:        "major versions within keep latest but past max age",
+			regex:       `^(\d+)\.\d+\.\d+/`,
+			keepLatest:  3,
+			maxAge:      5 * day,
+			wantKept:    []string{"2"},
+			wantExpired: []string{"1"},
+		},
+		{
+			name:        "whole match",
+			regex:       `^\d+\.\d+\.\d+`,
+			keepLatest:  3,
+			wantKept:    []string{"2.0.0", "1.2.0", "1.1.0"},
+			wantExpired: []string{"1.0.0"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			versions := groupVersions(files, regexp.MustCompile(test.regex))
+			kept, expired := expireVersions(versions, test.keepLatest, test.maxAge, now)
+
+			if got := retentionVersionNames(kept); !reflect.DeepEqual(got, test.wantKept) {
+				t.Errorf("kept %v, want %v", got, test.wantKept)
+			}
+			if got := retentionVersionNames(expired); !reflect.DeepEqual(got, test.wantExpired) {
+				t.Errorf("expired %v, want %v", got, test.wantExpired)
+			}
+		})
+	}
+}
+
+func TestVersionPinned(t *testing.T) {
+	version := retentionVersion{name: "1.0.0", files: []client.FileListing{{Path: "1.0.0/tool-linux.tgz"}, {Path: "1.0.0/tool-darwin.tgz"}}}
+
+	if !versionPinned(version, map[string]bool{"1.0.0/tool-darwin.tgz": true}) {
+		t.Error("expected version with a pinned file to be pinned")
+	}
+	if versionPinned(version, map[string]bool{"1.1.0/tool-darwin.tgz": true}) {
+		t.Error("expected version without pinned files not to be pinned")
+	}
+}
+
+func TestDeleteEmptyFolders(t *testing.T) {
+	// children of each folder, after the deleted files are gone
+	folders := map[string][]string{
+		"repo/tools":                 {"/1.0.0", "/1.1.0", "/2.0.0"},
+		"repo/tools/1.0.0":           {"/linux"},
+		"repo/tools/1.0.0/linux":     {},
+		"repo/tools/1.1.0":           {"/notes.txt"},
+		"repo/tools/2.0.0":           {"/tool.tgz"},
+		"repo/tools/2.0.0/unrelated": {},
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		folder := strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/storage"), "/")
+		children, ok := folders[folder]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		switch r.Method {
+		case http.MethodGet:
+			json.NewEncoder(w).Encode(map[string]interface{}{"children": childURIs(children)})
+		case http.MethodDelete:
+			delete(folders, folder)
+			parent := path.Dir(folder)
+			for i, child := range folders[parent] {
+				if child == "/"+path.Base(folder) {
+					folders[parent] = append(folders[parent][:i], folders[parent][i+1:]...)
+				}
+			}
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer server.Close()
+
+	deleted := []string{"repo/tools/1.0.0/linux/tool.tgz", "repo/tools/1.1.0/tool.tgz"}
+	if err := deleteEmptyFolders(context.Background(), &client.Client{URL: server.URL}, "repo/tools", deleted); err != nil {
+		t.Fatal(err)
+	}
+
+	remaining := []string{}
+	for folder := range folders {
+		remaining = append(remaining, folder)
+	}
+	sort.Strings(remaining)
+
+	expected := []string{"repo/tools", "repo/tools/1.1.0", "repo/tools/2.0.0", "repo/tools/2.0.0/unrelated"}
+	if !reflect.DeepEqual(remaining, expected) {
+		t.Errorf("expected remaining folders %v, got %v", expected, remaining)
+	}
+}
+
+func TestParseAge(t *testing.T) {
+	tests := map[string]time.Duration{
+		"30d":  30 * 24 * time.Hour,
+		"720h": 720 * time.Hour,
+		"90m":  90 * time.Minute,
+	}
+
+	for age, want := range tests {
+		got, err := parseAge(age)
+		if err != nil {
+			t.Errorf("unexpected error parsing %q: %s", age, err)
+		}
+		if got != want {
+			t.Errorf("parsing %q got %s, want %s", age, got, want)
+		}
+	}
+
+	for _, age := range []string{"d", "-1d", "1.5d", "30"} {
+		if _, err := parseAge(age); err == nil {
+			t.Errorf("expected error parsing %q", age)
+		}
+	}
+}
+
+func retentionVersionNames(versions []retentionVersion) []string {
+	names := []string{}
+	for _, version := range versions {
+		names = append(names, version.name)
+	}
+
+	return names
+}
+
+func childURIs(children []string) []map[string]interface{} {
+	uris := []map[string]interface{}{}
+	for _, child := range children {
+		uris = append(uris, map[string]interface{}{"uri": child, "folder": true})
+	}
+
+	return uris
+}
@@ -0,0 +1,300 @@
+// Copyright 2021 Splunk, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	"terraform-provider-artifacts/internal/provider/internal/client"
+	"terraform-provider-artifacts/internal/provider/internal/pkginfo"
+)
+
+func resourceRPMPackage() *schema.Resource {
+	return &schema.Resource{
+		Description:   "Upload an RPM package to a YUM repository in Artifactory, named from the package's header",
+		CreateContext: resourceRPMPackageCreate,
+		ReadContext:   resourceRPMPackageRead,
+		UpdateContext: resourceRPMPackageUpdate,
+		DeleteContext: resourceRPMPackageDelete,
+		CustomizeDiff: resourceRPMPackageDiff,
+		Schema: map[string]*schema.Schema{
+			repositoryKey: {
+				Description: fmt.Sprintf("Key of the YUM repository to upload to. The provider's `%s` isn't applied, as package paths are relative to the repository.", basePathKey),
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+			},
+			uploadFileKey: {
+				Description: "RPM package file to upload",
+				Type:        schema.TypeString,
+				Required:    true,
+			},
+			rpmDirectoryKey: {
+				Description: "Directory within the repository to upload the package to, such as `el9/x86_64`. Defaults to the repository's root.",
+				Type:        schema.TypeString,
+				Optional:    true,
+			},
+			calculateMetadataKey: {
+				Description: "Set to true to recalculate the repository's metadata after uploading or deleting the package, for repositories that don't calculate it automatically. Defaults to false.",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+			},
+			deleteOldPath: {
+				Description: "Set to false if the uploaded package should be orphaned on destruction of the resource or change of its path. Defaults to true.",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+			},
+			triggersKey: {
+				Description: "Arbitrary map of values that, when changed, will trigger re-uploading of this resource's file.",
+				Type:        schema.TypeMap,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			packageNameKey: {
+				Description: "Name of the package, from its header",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			versionKey: {
+				Description: "Version of the package, from its header",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			releaseKey: {
+				Description: "Release of the package, from its header",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			architectureKey: {
+				Description: "Architecture of the package, from its header. Source packages have the architecture `src`.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			fullPathKey: {
+				Description: "Path the package is uploaded to, relative to the provider's URL",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			sha1Key: {
+				Description: "SHA1 of the uploaded package",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			sha256Key: {
+				Description: "SHA256 of the uploaded package",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			md5Key: {
+				Description: "MD5 of the uploaded package",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+		},
+	}
+}
+
+// rpmPackage is an RPM package to upload, and where to upload it.
+type rpmPackage struct {
+	header   pkginfo.RPMHeader
+	fullPath string
+}
+
+// readRPMPackage reads the header of the package in d, and determines its path in the repository.
+func readRPMPackage(d resourceGetter) (rpmPackage, error) {
+	header, err := pkginfo.ReadRPM(d.Get(uploadFileKey).(string))
+	if err != nil {
+		return rpmPackage{}, err
+	}
+
+	fullPath, err := client.JoinPath(d.Get(repositoryKey).(string), d.Get(rpmDirectoryKey).(string), header.FileName())
+	if err != nil {
+		return rpmPackage{}, err
+	}
+
+	return rpmPackage{header: header, fullPath: fullPath}, nil
+}
+
+// calculateYumMetadata recalculates the metadata of the repository in d, if configured to.
+func calculateYumMetadata(ctx context.Context, d resourceGetter, c *client.Client) error {
+	if !d.Get(calculateMetadataKey).(bool) {
+		return nil
+	}
+
+	return c.CalculateYumMetadata(ctx, d.Get(repositoryKey).(string))
+}
+
+func resourceRPMPackageCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*client.Client)
+
+	pkg, err := readRPMPackage(d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	filePath, err := filepath.Abs(d.Get(uploadFileKey).(string))
+	if err != nil {
+		return diag.Errorf("unable to determine absolute path for file %s", d.Get(uploadFileKey).(string))
+	}
+
+	if err := client.Upload(ctx, pkg.fullPath, filePath, nil); err != nil {
+		return diag.Errorf("failure uploading package %s: %s", filePath, err)
+	}
+
+	d.SetId(pkg.fullPath)
+	if err := setRPMPackage(d, pkg); err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err := calculateYumMetadata(ctx, d, client); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return resourceRPMPackageRead(ctx, d, meta)
+}
+
+func resourceRPMPackageRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*client.Client)
+
+	checksums, err := client.Checksums(ctx, d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if checksums.Get(client.ChecksumType) == "" {
+		d.SetId("")
+		return nil
+	}
+
+	if err := setChecksums(d, checksums); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return nil
+}
+
+// resourceRPMPackageUpdate uploads the package again if it or its path changed. Other changes only affect later
+// operations.
+func resourceRPMPackageUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*client.Client)
+
+	pkg, err := readRPMPackage(d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if pkg.fullPath != d.Id() {
+		if d.Get(deleteOldPath).(bool) {
+			if err := deleteExisting(ctx, client, []string{d.Id()}); err != nil {
+				return diag.FromErr(err)
+			}
+		}
+
+		return resourceRPMPackageCreate(ctx, d, meta)
+	}
+
+	if d.HasChanges(uploadFileKey, triggersKey, sha1Key, sha256Key, md5Key) {
+		return resourceRPMPackageCreate(ctx, d, meta)
+	}
+
+	return resourceRPMPackageRead(ctx, d, meta)
+}
+
+func resourceRPMPackageDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*client.Client)
+
+	if d.Get(deleteOldPath).(bool) {
+		if err := deleteExisting(ctx, client, []string{d.Id()}); err != nil {
+			return diag.FromErr(err)
+		}
+
+		if err := calculateYumMetadata(ctx, d, client); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	return nil
+}
+
+func resourceRPMPackageDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if d.HasChanges(uploadFileKey, triggersKey) {
+		if err := setNewComputedChecksums(d); err != nil {
+			return err
+		}
+	}
+
+	packageKeys := []string{packageNameKey, versionKey, releaseKey, architectureKey, fullPathKey}
+	if known, err := diffPackageFile(d, uploadFileKey, []string{repositoryKey, rpmDirectoryKey}, packageKeys); !known || err != nil {
+		return err
+	}
+
+	pkg, err := readRPMPackage(d)
+	if err != nil {
+		return err
+	}
+
+	if pkg.fullPath != d.Get(fullPathKey).(string) && d.Id() != "" {
+		if err := setNewComputedChecksums(d); err != nil {
+			return err
+		}
+	}
+
+	for key, value := range rpmPackageAttributes(pkg) {
+		if value != d.Get(key).(string) {
+			if err := d.SetNew(key, value); err != nil {
+				return err
+			}
+		}
+	}
+
+	drifted, err := uploadDrifted(d, meta)
+	if err != nil {
+		return err
+	}
+	if drifted {
+		return setNewComputedChecksums(d)
+	}
+
+	return nil
+}
+
+// rpmPackageAttributes returns the attributes read from a package's header.
+func rpmPackageAttributes(pkg rpmPackage) map[string]string {
+	return map[string]string{
+		packageNameKey:  pkg.header.Name,
+		versionKey:      pkg.header.Version,
+		releaseKey:      pkg.header.Release,
+		architectureKey: pkg.header.Arch,
+		fullPathKey:     pkg.fullPath,
+	}
+}
+
+// setRPMPackage sets the attributes read from a package's header.
+func setRPMPackage(d *schema.ResourceData, pkg rpmPackage) error {
+	for key, value := range rpmPackageAttributes(pkg) {
+		if err := d.Set(key, value); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,52 @@
+// Copyright 2021 Splunk, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccResourceRPMPackage(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV5ProviderFactories: protoV5ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testResourceRPMPackageConfig,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("artifacts_rpm_package.test", "full_path", "sas-rpm/terraform-provider-artifacts-test/terraform-provider-artifacts-test-1.0.0-1.noarch.rpm"),
+					resource.TestCheckResourceAttr("artifacts_rpm_package.test", "package_name", "terraform-provider-artifacts-test"),
+					resource.TestCheckResourceAttr("artifacts_rpm_package.test", "version", "1.0.0"),
+					resource.TestCheckResourceAttr("artifacts_rpm_package.test", "release", "1"),
+					resource.TestCheckResourceAttr("artifacts_rpm_package.test", "architecture", "noarch"),
+				),
+			},
+		},
+	})
+}
+
+const testResourceRPMPackageConfig = `
+provider "artifacts" {
+  url = "https://repo.splunk.com/artifactory"
+}
+
+resource "artifacts_rpm_package" "test" {
+  repository         = "sas-rpm"
+  directory          = "terraform-provider-artifacts-test"
+  upload_file        = "test_files/terraform-provider-artifacts-test-1.0.0-1.noarch.rpm"
+  calculate_metadata = true
+}
+`
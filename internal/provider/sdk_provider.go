@@ -0,0 +1,97 @@
+// Copyright 2021 Splunk, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// newSDKProvider returns the SDKv2 provider of the resources and data sources that haven't been ported to the plugin
+// framework. It is served alongside framework, which validates the provider's configuration and configures the client
+// both providers use.
+func newSDKProvider(framework *artifactsProvider) *schema.Provider {
+	p := &schema.Provider{
+		Schema: sdkProviderSchema(),
+		ResourcesMap: map[string]*schema.Resource{
+			folderResourceKey:            resourceFolder(),
+			pointerResourceKey:           resourcePointer(),
+			retentionResourceKey:         resourceRetention(),
+			archiveUploadResourceKey:     resourceArchiveUpload(),
+			mavenResourceKey:             resourceMaven(),
+			debianPackageResourceKey:     resourceDebianPackage(),
+			rpmPackageResourceKey:        resourceRPMPackage(),
+			helmChartResourceKey:         resourceHelmChart(),
+			terraformModuleResourceKey:   resourceTerraformModule(),
+			terraformProviderResourceKey: resourceTerraformProvider(),
+			buildInfoResourceKey:         resourceBuildInfo(),
+			buildPromotionResourceKey:    resourceBuildPromotion(),
+		},
+		DataSourcesMap: map[string]*schema.Resource{
+			systemDataSourceKey: dataSourceSystem(),
+		},
+	}
+
+	p.ConfigureContextFunc = func(ctx context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
+		// the framework provider is configured first, and its errors stop this provider from being configured
+		if framework.client == nil {
+			return nil, diag.Errorf("the provider's client wasn't configured")
+		}
+
+		return framework.client, nil
+	}
+
+	return p
+}
+
+// sdkProviderSchema returns the SDKv2 equivalent of the framework provider's schema, as muxed providers must have
+// identical schemas. Validation and defaults are left to the framework provider.
+func sdkProviderSchema() map[string]*schema.Schema {
+	sdkSchema := map[string]*schema.Schema{}
+
+	for key, attribute := range providerSchema().Attributes {
+		sdkAttribute := &schema.Schema{
+			Description: attribute.GetDescription(),
+			Required:    attribute.IsRequired(),
+			Optional:    attribute.IsOptional(),
+			Sensitive:   attribute.IsSensitive(),
+		}
+
+		switch attributeType := attribute.GetType(); {
+		case attributeType.Equal(types.StringType):
+			sdkAttribute.Type = schema.TypeString
+		case attributeType.Equal(types.BoolType):
+			sdkAttribute.Type = schema.TypeBool
+		case attributeType.Equal(types.Int64Type):
+			sdkAttribute.Type = schema.TypeInt
+		case attributeType.Equal(types.ListType{ElemType: types.StringType}):
+			sdkAttribute.Type = schema.TypeList
+			sdkAttribute.Elem = &schema.Schema{Type: schema.TypeString}
+		case attributeType.Equal(types.MapType{ElemType: types.StringType}):
+			sdkAttribute.Type = schema.TypeMap
+			sdkAttribute.Elem = &schema.Schema{Type: schema.TypeString}
+		default:
+			panic(fmt.Sprintf("provider attribute %s has type %s, which has no SDKv2 equivalent", key, attributeType))
+		}
+
+		sdkSchema[key] = sdkAttribute
+	}
+
+	return sdkSchema
+}
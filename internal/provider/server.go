@@ -0,0 +1,42 @@
+// Copyright 2021 Splunk, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-mux/tf5muxserver"
+)
+
+// ProtoV5ProviderServer returns a function that returns a server of the provider, muxing the plugin framework
+// provider with the SDKv2 provider of the resources that haven't been ported to the framework yet.
+func ProtoV5ProviderServer(version string) func() (tfprotov5.ProviderServer, error) {
+	return func() (tfprotov5.ProviderServer, error) {
+		framework := New(version)().(*artifactsProvider)
+
+		// servers are configured in order, and the framework provider configures the client both use
+		muxServer, err := tf5muxserver.NewMuxServer(context.Background(),
+			providerserver.NewProtocol5(framework),
+			newSDKProvider(framework).GRPCProvider,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		return muxServer.ProviderServer(), nil
+	}
+}
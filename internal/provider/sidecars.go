@@ -0,0 +1,231 @@
+// Copyright 2021 Splunk, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"reflect"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	"terraform-provider-artifacts/internal/provider/internal/client"
+	"terraform-provider-artifacts/internal/provider/internal/signing"
+)
+
+// sidecarPaths returns the paths of the sidecar files published next to an upload at fullPath: a checksum file for
+// each of checksum_sidecars, and a signature if signing_key is set.
+func sidecarPaths(d resourceGetter, fullPath string) []string {
+	paths := []string{}
+
+	for _, checksumType := range stringList(d.Get(checksumSidecarsKey)) {
+		paths = append(paths, fullPath+"."+checksumType)
+	}
+
+	if armoredKey, _ := signingKey(d); armoredKey != "" {
+		paths = append(paths, fullPath+"."+signatureExtension)
+	}
+
+	return paths
+}
+
+// publishSidecars uploads the sidecar files of filename, which has been uploaded to fullPath. Checksum files contain
+// the checksum and the uploaded file's name, in the format of tools such as sha256sum.
+func publishSidecars(ctx context.Context, d resourceGetter, c *client.Client, fullPath string, filename string) error {
+	checksumTypes := stringList(d.Get(checksumSidecarsKey))
+	if len(checksumTypes) > 0 {
+		checksums, err := c.FileChecksums(filename)
+		if err != nil {
+			return err
+		}
+
+		for _, checksumType := range checksumTypes {
+			content := fmt.Sprintf("%s  %s\n", checksums.Get(client.ChecksumType(checksumType)), path.Base(fullPath))
+			if err := c.UploadContent(ctx, fullPath+"."+checksumType, []byte(content), nil); err != nil {
+				return fmt.Errorf("failure uploading %s checksum of %s: %s", checksumType, filename, err)
+			}
+		}
+	}
+
+	if armoredKey, passphrase := signingKey(d); armoredKey != "" {
+		signer, err := signing.NewSigner(armoredKey, passphrase)
+		if err != nil {
+			return fmt.Errorf("unable to use %s: %s", signingKeyKey, err)
+		}
+
+		file, err := os.Open(filename)
+		if err != nil {
+			return fmt.Errorf("unable to read file %s: %s", filename, err)
+		}
+		defer file.Close()
+
+		signature, err := signer.ArmoredDetachSign(file)
+		if err != nil {
+			return fmt.Errorf("unable to sign %s: %s", filename, err)
+		}
+
+		if err := c.UploadContent(ctx, fullPath+"."+signatureExtension, signature, nil); err != nil {
+			return fmt.Errorf("failure uploading signature of %s: %s", filename, err)
+		}
+	}
+
+	return nil
+}
+
+// signingKey returns the armored signing key of an upload and its passphrase, from signing_key and
+// signing_key_passphrase, or from their write-only equivalents. Resources without write-only equivalents read them as
+// nil, which is no key.
+func signingKey(d resourceGetter) (string, string) {
+	armoredKey, _ := d.Get(signingKeyKey).(string)
+	if armoredKey == "" {
+		armoredKey = writeOnlyString(d, signingKeyWOKey)
+	}
+
+	passphrase, _ := d.Get(signingKeyPassphraseKey).(string)
+	if passphrase == "" {
+		passphrase = writeOnlyString(d, signingKeyPassphraseWOKey)
+	}
+
+	return armoredKey, passphrase
+}
+
+// writeOnlyString returns the value of the write-only string attribute named key, or "" if it isn't set or known.
+// SDKv2 resources only have write-only values in their raw configuration, while frameworkData reads them from the
+// configuration itself.
+func writeOnlyString(d resourceGetter, key string) string {
+	raw, ok := d.(interface{ GetRawConfig() cty.Value })
+	if !ok {
+		value, _ := d.Get(key).(string)
+		return value
+	}
+
+	config := raw.GetRawConfig()
+	if config.IsNull() || !config.IsKnown() || !config.Type().IsObjectType() || !config.Type().HasAttribute(key) {
+		return ""
+	}
+
+	value := config.GetAttr(key)
+	if value.IsNull() || !value.IsKnown() || !value.Type().Equals(cty.String) {
+		return ""
+	}
+
+	return value.AsString()
+}
+
+// signingKeyWOSDKSchema returns the SDKv2 schema of the write-only equivalents of signing_key and
+// signing_key_passphrase, and of the version that publishes what's signed again when it changes. If required is
+// true, exactly one of signing_key and signing_key_wo must be set.
+func signingKeyWOSDKSchema(required bool) map[string]*schema.Schema {
+	keySchema := &schema.Schema{
+		Description:   uploadDescriptions[signingKeyWOKey],
+		Type:          schema.TypeString,
+		Optional:      true,
+		Sensitive:     true,
+		WriteOnly:     true,
+		ValidateFunc:  validateSigningKey,
+		ConflictsWith: []string{signingKeyKey},
+	}
+	if required {
+		keySchema.ConflictsWith = nil
+		keySchema.ExactlyOneOf = []string{signingKeyKey, signingKeyWOKey}
+	}
+
+	return map[string]*schema.Schema{
+		signingKeyWOKey: keySchema,
+		signingKeyPassphraseWOKey: {
+			Description:   uploadDescriptions[signingKeyPassphraseWOKey],
+			Type:          schema.TypeString,
+			Optional:      true,
+			Sensitive:     true,
+			WriteOnly:     true,
+			ConflictsWith: []string{signingKeyPassphraseKey},
+		},
+		signingKeyWOVersionKey: {
+			Description:  uploadDescriptions[signingKeyWOVersionKey],
+			Type:         schema.TypeInt,
+			Optional:     true,
+			RequiredWith: []string{signingKeyWOKey},
+		},
+	}
+}
+
+// existingSidecars returns those of paths that exist on the service, for an upload at fullPath with checksums.
+// Artifactory handles a checksum file deployed next to a file as a checksum deploy, rather than storing it, and serves
+// it from the file's own checksums. So checksum sidecars exist whenever the file has the checksum, and only signatures
+// are looked up.
+func existingSidecars(ctx context.Context, c *client.Client, fullPath string, checksums client.Checksums, paths []string) ([]string, error) {
+	existing := []string{}
+
+	for _, sidecarPath := range paths {
+		if checksumType, ok := sidecarChecksumType(fullPath, sidecarPath); ok {
+			if checksums.Get(checksumType) != "" {
+				existing = append(existing, sidecarPath)
+			}
+			continue
+		}
+
+		sidecarChecksums, err := c.Checksums(ctx, sidecarPath)
+		if err != nil {
+			return nil, err
+		}
+
+		if sidecarChecksums.SHA1 != "" {
+			existing = append(existing, sidecarPath)
+		}
+	}
+
+	return existing, nil
+}
+
+// sidecarChecksumType returns the checksum type of the checksum sidecar at sidecarPath, for an upload at fullPath.
+// ok is false if it isn't a checksum sidecar of the upload.
+func sidecarChecksumType(fullPath string, sidecarPath string) (checksumType client.ChecksumType, ok bool) {
+	for _, checksumType := range client.ChecksumTypes {
+		if sidecarPath == fullPath+"."+string(checksumType) {
+			return checksumType, true
+		}
+	}
+
+	return "", false
+}
+
+// diffSidecars sets the new sidecar_paths, which changes when the sidecars or the upload's full path change, or when
+// sidecars found to be missing when state was refreshed need to be published again.
+func diffSidecars(d resourceDiffer) error {
+	if !d.NewValueKnown(fullPathKey) || !d.NewValueKnown(checksumSidecarsKey) || !d.NewValueKnown(signingKeyKey) ||
+		!d.NewValueKnown(signingKeyWOKey) {
+		return d.SetNewComputed(sidecarPathsKey)
+	}
+
+	paths := sidecarPaths(d, d.Get(fullPathKey).(string))
+	if !reflect.DeepEqual(paths, stringList(d.Get(sidecarPathsKey))) {
+		return d.SetNew(sidecarPathsKey, paths)
+	}
+
+	return nil
+}
+
+// validateSigningKey is a schema.SchemaValidateFunc that checks a signing key can be read, without decrypting it.
+func validateSigningKey(value interface{}, key string) ([]string, []error) {
+	if _, err := signing.ReadKey(value.(string), ""); err != nil {
+		// the key is sensitive, so only the error is reported
+		return nil, []error{fmt.Errorf("invalid %s: %s", key, err)}
+	}
+
+	return nil, nil
+}
@@ -0,0 +1,52 @@
+// Copyright 2021 Splunk, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"terraform-provider-artifacts/internal/provider/internal/client"
+)
+
+func TestExistingSidecars(t *testing.T) {
+	// like Artifactory, only the signature is stored as a file, and checksum files are served from the file's checksums
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/storage/repo/tool.tgz.asc" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		fmt.Fprint(w, `{"checksums": {"sha1": "signature-sha1"}}`)
+	}))
+	defer server.Close()
+
+	paths := []string{"repo/tool.tgz.sha256", "repo/tool.tgz.md5", "repo/tool.tgz.asc"}
+
+	existing, err := existingSidecars(context.Background(), &client.Client{URL: server.URL}, "repo/tool.tgz", client.Checksums{SHA1: "sha1", SHA256: "sha256"}, paths)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the file has no MD5, so its checksum file can't be served
+	expected := []string{"repo/tool.tgz.sha256", "repo/tool.tgz.asc"}
+	if !reflect.DeepEqual(existing, expected) {
+		t.Errorf("expected %v, got %v", expected, existing)
+	}
+}
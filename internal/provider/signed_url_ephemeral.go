@@ -0,0 +1,202 @@
+// Copyright 2021 Splunk, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	ephemeralschema "github.com/hashicorp/terraform-plugin-framework/ephemeral/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"terraform-provider-artifacts/internal/provider/internal/client"
+)
+
+// signedURLEphemeralResource is the ephemeral artifacts_signed_url, which creates a link to download an artifact for
+// the duration of a Terraform run, falling back to an access token that is revoked when Terraform is done with it.
+type signedURLEphemeralResource struct {
+	client *client.Client
+}
+
+// signedURLModel is the data of the ephemeral artifacts_signed_url.
+type signedURLModel struct {
+	Path       types.String `tfsdk:"path"`
+	ExpiresIn  types.Int64  `tfsdk:"expires_in"`
+	Method     types.String `tfsdk:"method"`
+	TokenScope types.String `tfsdk:"token_scope"`
+	URL        types.String `tfsdk:"url"`
+	Token      types.String `tfsdk:"token"`
+	ExpiresAt  types.String `tfsdk:"expires_at"`
+}
+
+func newSignedURLEphemeralResource() ephemeral.EphemeralResource {
+	return &signedURLEphemeralResource{}
+}
+
+// Metadata implements ephemeral.EphemeralResource.
+func (r *signedURLEphemeralResource) Metadata(ctx context.Context, req ephemeral.MetadataRequest, resp *ephemeral.MetadataResponse) {
+	resp.TypeName = signedURLEphemeralResourceKey
+}
+
+// Schema implements ephemeral.EphemeralResource.
+func (r *signedURLEphemeralResource) Schema(ctx context.Context, req ephemeral.SchemaRequest, resp *ephemeral.SchemaResponse) {
+	resp.Schema = ephemeralschema.Schema{
+		Description: "Time-limited link to download an artifact without the provider's credentials, from a signed URL or a scoped access token that is revoked when Terraform is done with it",
+		Attributes: map[string]ephemeralschema.Attribute{
+			signedURLPathKey: ephemeralschema.StringAttribute{
+				Description: "Path of the artifact, relative to the provider's URL, such as the `full_path` of an `artifacts_upload`",
+				Required:    true,
+			},
+			expiresInKey: ephemeralschema.Int64Attribute{
+				Description: fmt.Sprintf("Number of seconds the link is valid for, if its access token isn't revoked first. Defaults to %d.", defaultSignedURLExpiresIn),
+				Optional:    true,
+				Validators:  []validator.Int64{int64validator.AtLeast(1)},
+			},
+			signedURLMethodKey: ephemeralschema.StringAttribute{
+				Description: fmt.Sprintf(
+					"How the link is created. `%s` creates a signed URL, which requires an edition of Artifactory that supports them. `%s` creates an access token with `%s`, which must be sent as a bearer token when downloading `%s`. `%s`, the default, creates a signed URL, falling back to an access token only when `%s` is set and the service responds that it doesn't support signed URLs.",
+					signedURLMethodSigned, signedURLMethodAccessToken, tokenScopeKey, urlKey, signedURLMethodAuto, tokenScopeKey,
+				),
+				Optional:   true,
+				Validators: []validator.String{stringvalidator.OneOf(signedURLMethods...)},
+			},
+			tokenScopeKey: ephemeralschema.StringAttribute{
+				Description: fmt.Sprintf("Scope of the access token, such as `applied-permissions/groups:readers` to limit the token to the permissions of a group. Required for the `%s` method, and for `%s` to fall back to an access token.", signedURLMethodAccessToken, signedURLMethodAuto),
+				Optional:    true,
+			},
+			urlKey: ephemeralschema.StringAttribute{
+				Description: "Signed URL of the artifact, or its URL when an access token was created",
+				Computed:    true,
+				Sensitive:   true,
+			},
+			tokenKey: ephemeralschema.StringAttribute{
+				Description: "Access token to download the artifact with. Empty when a signed URL was created.",
+				Computed:    true,
+				Sensitive:   true,
+			},
+			expiresAtKey: ephemeralschema.StringAttribute{
+				Description: "Time the link expires, in RFC 3339 format",
+				Computed:    true,
+			},
+		},
+	}
+}
+
+// ValidateConfig implements ephemeral.EphemeralResourceWithValidateConfig.
+func (r *signedURLEphemeralResource) ValidateConfig(ctx context.Context, req ephemeral.ValidateConfigRequest, resp *ephemeral.ValidateConfigResponse) {
+	data := signedURLModel{}
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if data.Method.ValueString() == signedURLMethodAccessToken && data.TokenScope.IsNull() {
+		resp.Diagnostics.AddAttributeError(path.Root(tokenScopeKey), "Missing token scope",
+			fmt.Sprintf("%s must be set to create an access token, to limit what the token can access.", tokenScopeKey))
+	}
+}
+
+// Configure implements ephemeral.EphemeralResourceWithConfigure.
+func (r *signedURLEphemeralResource) Configure(ctx context.Context, req ephemeral.ConfigureRequest, resp *ephemeral.ConfigureResponse) {
+	// the provider isn't configured yet when configuration is validated
+	if req.ProviderData == nil {
+		return
+	}
+
+	c, ok := req.ProviderData.(*client.Client)
+	if !ok {
+		resp.Diagnostics.AddError("Unexpected provider data", fmt.Sprintf("Expected *client.Client, got %T.", req.ProviderData))
+		return
+	}
+
+	r.client = c
+}
+
+// Open implements ephemeral.EphemeralResource.
+func (r *signedURLEphemeralResource) Open(ctx context.Context, req ephemeral.OpenRequest, resp *ephemeral.OpenResponse) {
+	data := signedURLModel{}
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	url, token, expiresAt, err := signedURL(ctx, r.client,
+		data.Path.ValueString(),
+		time.Duration(int64OrDefault(data.ExpiresIn, defaultSignedURLExpiresIn))*time.Second,
+		stringOrDefault(data.Method, signedURLMethodAuto),
+		data.TokenScope.ValueString(),
+	)
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to create link", err.Error())
+		return
+	}
+
+	data.URL = types.StringValue(url)
+	data.Token = types.StringValue(token.Token)
+	data.ExpiresAt = types.StringValue("")
+	if !expiresAt.IsZero() {
+		data.ExpiresAt = types.StringValue(expiresAt.UTC().Format(time.RFC3339))
+	}
+	resp.Diagnostics.Append(resp.Result.Set(ctx, &data)...)
+
+	setAccessTokenID(ctx, resp, token)
+}
+
+// Close implements ephemeral.EphemeralResourceWithClose.
+func (r *signedURLEphemeralResource) Close(ctx context.Context, req ephemeral.CloseRequest, resp *ephemeral.CloseResponse) {
+	revokeAccessToken(ctx, r.client, req, resp)
+}
+
+// signedURLMethods are the supported values of method.
+var signedURLMethods = []string{signedURLMethodAuto, signedURLMethodSigned, signedURLMethodAccessToken}
+
+// signedURL returns a link to download the file at fullPath for validFor, as a signed URL or as the file's URL and an
+// access token with scope, as chosen by method. token is the zero AccessToken for signed URLs. With
+// signedURLMethodAuto, an access token is only created when the service doesn't support signed URLs and scope is set.
+func signedURL(ctx context.Context, c *client.Client, fullPath string, validFor time.Duration, method string, scope string) (url string, token client.AccessToken, expiresAt time.Time, err error) {
+	if method != signedURLMethodAccessToken {
+		expiresAt = time.Now().Add(validFor)
+		url, err = c.SignedURL(ctx, fullPath, validFor)
+		if err == nil {
+			return url, token, expiresAt, nil
+		}
+
+		var statusErr *client.StatusError
+		if method == signedURLMethodSigned || scope == "" || !errors.As(err, &statusErr) || !statusErr.Unsupported() {
+			return "", token, expiresAt, fmt.Errorf("unable to create signed URL for %s: %s", fullPath, err)
+		}
+	}
+
+	if scope == "" {
+		return "", token, expiresAt, fmt.Errorf("%s is required to create an access token for %s", tokenScopeKey, fullPath)
+	}
+
+	token, tokenErr := c.CreateAccessToken(ctx, scope, validFor, fmt.Sprintf("Download of %s", fullPath))
+	if tokenErr != nil {
+		if err != nil {
+			return "", token, expiresAt, fmt.Errorf("unable to create signed URL for %s: %s; %s", fullPath, err, tokenErr)
+		}
+		return "", token, expiresAt, tokenErr
+	}
+
+	return c.FileURL(fullPath), token, token.ExpiresAt, nil
+}
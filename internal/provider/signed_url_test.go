@@ -0,0 +1,222 @@
+// Copyright 2021 Splunk, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+
+	"terraform-provider-artifacts/internal/provider/internal/client"
+)
+
+func TestSignedURLFallback(t *testing.T) {
+	tests := []struct {
+		name      string
+		status    int
+		body      string
+		method    string
+		scope     string
+		wantToken bool
+		wantErr   bool
+	}{
+		{
+			name:      "unsupported edition",
+			status:    http.StatusBadRequest,
+			body:      `{"errors":[{"status":400,"message":"This feature is only available with an Enterprise+ license"}]}`,
+			method:    signedURLMethodAuto,
+			scope:     "applied-permissions/groups:readers",
+			wantToken: true,
+		},
+		{
+			name:    "unsupported edition without a scope",
+			status:  http.StatusBadRequest,
+			body:    `{"errors":[{"status":400,"message":"This feature is only available with an Enterprise+ license"}]}`,
+			method:  signedURLMethodAuto,
+			wantErr: true,
+		},
+		{
+			name:    "missing artifact",
+			status:  http.StatusNotFound,
+			body:    `{"errors":[{"status":404,"message":"Not Found"}]}`,
+			method:  signedURLMethodAuto,
+			scope:   "applied-permissions/groups:readers",
+			wantErr: true,
+		},
+		{
+			name:    "other client error",
+			status:  http.StatusBadRequest,
+			body:    `{"errors":[{"status":400,"message":"valid_for_secs is too large"}]}`,
+			method:  signedURLMethodAuto,
+			scope:   "applied-permissions/groups:readers",
+			wantErr: true,
+		},
+		{
+			name:    "signed URL only",
+			status:  http.StatusBadRequest,
+			body:    `{"errors":[{"status":400,"message":"This feature is only available with an Enterprise+ license"}]}`,
+			method:  signedURLMethodSigned,
+			scope:   "applied-permissions/groups:readers",
+			wantErr: true,
+		},
+		{
+			name:      "access token",
+			method:    signedURLMethodAccessToken,
+			scope:     "applied-permissions/groups:readers",
+			wantToken: true,
+		},
+		{
+			name:    "access token without a scope",
+			method:  signedURLMethodAccessToken,
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Path {
+				case "/artifactory/api/signed/url":
+					http.Error(w, test.body, test.status)
+				case "/access/api/v1/tokens":
+					if !test.wantToken {
+						t.Error("unexpected request for an access token")
+					}
+					w.Write([]byte(`{"token_id":"token-1","access_token":"download-token","scope":"applied-permissions/groups:readers","expires_in":600}`))
+				default:
+					t.Errorf("unexpected request %s %s", r.Method, r.URL)
+				}
+			}))
+			defer server.Close()
+
+			c := &client.Client{URL: server.URL + "/artifactory"}
+
+			url, token, expiresAt, err := signedURL(context.Background(), c, "repo/file.txt", 10*time.Minute, test.method, test.scope)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("got error %v, want error %t", err, test.wantErr)
+			}
+			if test.wantErr {
+				return
+			}
+
+			if want := server.URL + "/artifactory/repo/file.txt"; url != want {
+				t.Errorf("got url %q, want %q", url, want)
+			}
+			if token.Token != "download-token" || token.ID != "token-1" {
+				t.Errorf("got token %+v", token)
+			}
+			if until := time.Until(expiresAt); until <= 0 || until > 10*time.Minute {
+				t.Errorf("unexpected expiry %s", expiresAt)
+			}
+		})
+	}
+}
+
+func TestSignedURLEphemeralResource(t *testing.T) {
+	revoked := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/artifactory/api/signed/url":
+			http.Error(w, `{"errors":[{"status":400,"message":"This feature is only available with an Enterprise+ license"}]}`, http.StatusBadRequest)
+		case r.Method == http.MethodPost && r.URL.Path == "/access/api/v1/tokens":
+			w.Write([]byte(`{"token_id":"token-1","access_token":"download-token","scope":"applied-permissions/groups:readers","expires_in":600}`))
+		case r.Method == http.MethodDelete && r.URL.Path == "/access/api/v1/tokens/token-1":
+			revoked = true
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+		}
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	providerServer, schemas := configuredProviderServer(t, server.URL+"/artifactory")
+
+	signedURLType := schemas.EphemeralResourceSchemas[signedURLEphemeralResourceKey].ValueType().(tftypes.Object)
+	config, err := tfprotov5.NewDynamicValue(signedURLType, objectValue(signedURLType, map[string]tftypes.Value{
+		signedURLPathKey: tftypes.NewValue(tftypes.String, "repo/file.txt"),
+		tokenScopeKey:    tftypes.NewValue(tftypes.String, "applied-permissions/groups:readers"),
+	}))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	openResp, err := providerServer.OpenEphemeralResource(ctx, &tfprotov5.OpenEphemeralResourceRequest{
+		TypeName: signedURLEphemeralResourceKey,
+		Config:   &config,
+	})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	checkDiagnostics(t, openResp.Diagnostics)
+
+	result, err := openResp.Result.Unmarshal(signedURLType)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	attributes := map[string]tftypes.Value{}
+	if err := result.As(&attributes); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	var url, token string
+	if err := attributes[urlKey].As(&url); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if err := attributes[tokenKey].As(&token); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if url != server.URL+"/artifactory/repo/file.txt" || token != "download-token" {
+		t.Errorf("got url %q and token %q", url, token)
+	}
+
+	closeResp, err := providerServer.CloseEphemeralResource(ctx, &tfprotov5.CloseEphemeralResourceRequest{
+		TypeName: signedURLEphemeralResourceKey,
+		Private:  openResp.Private,
+	})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	checkDiagnostics(t, closeResp.Diagnostics)
+	if !revoked {
+		t.Error("token wasn't revoked")
+	}
+}
+
+func TestSignedURLEphemeralResourceRequiresScope(t *testing.T) {
+	ctx := context.Background()
+	providerServer, schemas := configuredProviderServer(t, "https://example.com/artifactory")
+
+	signedURLType := schemas.EphemeralResourceSchemas[signedURLEphemeralResourceKey].ValueType().(tftypes.Object)
+	config, err := tfprotov5.NewDynamicValue(signedURLType, objectValue(signedURLType, map[string]tftypes.Value{
+		signedURLPathKey:   tftypes.NewValue(tftypes.String, "repo/file.txt"),
+		signedURLMethodKey: tftypes.NewValue(tftypes.String, signedURLMethodAccessToken),
+	}))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	validateResp, err := providerServer.ValidateEphemeralResourceConfig(ctx, &tfprotov5.ValidateEphemeralResourceConfigRequest{
+		TypeName: signedURLEphemeralResourceKey,
+		Config:   &config,
+	})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if len(validateResp.Diagnostics) == 0 || validateResp.Diagnostics[0].Severity != tfprotov5.DiagnosticSeverityError {
+		t.Errorf("expected an error for a missing %s, got %v", tokenScopeKey, validateResp.Diagnostics)
+	}
+}
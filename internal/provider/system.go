@@ -0,0 +1,71 @@
+// Copyright 2021 Splunk, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	"terraform-provider-artifacts/internal/provider/internal/client"
+)
+
+func dataSourceSystem() *schema.Resource {
+	return &schema.Resource{
+		Description: "Version of the Artifactory service, and the user the provider authenticates as",
+		ReadContext: dataSourceSystemRead,
+		Schema: map[string]*schema.Schema{
+			versionKey: {
+				Description: "Version of Artifactory",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			revisionKey: {
+				Description: "Revision of Artifactory",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			userKey: {
+				Description: "Name of the user the provider authenticates as. Empty when it can't be determined, such as for anonymous access.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+		},
+	}
+}
+
+func dataSourceSystemRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*client.Client)
+
+	info, err := client.SystemInfo(ctx)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(client.URL)
+
+	for key, value := range map[string]string{
+		versionKey:  info.Version,
+		revisionKey: info.Revision,
+		userKey:     info.User,
+	} {
+		if err := d.Set(key, value); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,333 @@
+// Copyright 2021 Splunk, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+
+	"terraform-provider-artifacts/internal/provider/internal/archive"
+	"terraform-provider-artifacts/internal/provider/internal/client"
+	"terraform-provider-artifacts/internal/provider/internal/layout"
+)
+
+// terraformModuleExcludeNames are the names of files and directories that are never part of a module's package, as
+// they're local to a working directory.
+var terraformModuleExcludeNames = []string{".git", ".terraform", "*.tfstate", "*.tfstate.*"}
+
+func resourceTerraformModule() *schema.Resource {
+	return &schema.Resource{
+		Description:   "Package a Terraform module directory deterministically, and publish it to a Terraform repository in Artifactory at the path of its namespace, name, system, and version",
+		CreateContext: resourceTerraformModuleCreate,
+		ReadContext:   resourceTerraformModuleRead,
+		UpdateContext: resourceTerraformModuleUpdate,
+		DeleteContext: resourceTerraformModuleDelete,
+		CustomizeDiff: resourceTerraformModuleDiff,
+		Schema: map[string]*schema.Schema{
+			repositoryKey: {
+				Description: fmt.Sprintf("Key of the Terraform repository to publish to. The provider's `%s` isn't applied, as module paths are relative to the repository.", basePathKey),
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+			},
+			namespaceKey: {
+				Description: "Namespace of the module, such as the team that owns it",
+				Type:        schema.TypeString,
+				Required:    true,
+			},
+			terraformNameKey: {
+				Description: "Name of the module",
+				Type:        schema.TypeString,
+				Required:    true,
+			},
+			systemKey: {
+				Description: "Remote system the module targets, such as `aws`, which the registry calls the module's provider",
+				Type:        schema.TypeString,
+				Required:    true,
+			},
+			versionKey: {
+				Description: "Semantic version of the module, such as `1.2.3`",
+				Type:        schema.TypeString,
+				Required:    true,
+			},
+			sourceDirKey: {
+				Description: "Directory containing the module",
+				Type:        schema.TypeString,
+				Required:    true,
+			},
+			excludesKey: {
+				Description: fmt.Sprintf("Patterns of paths, relative to `%s`, to leave out of the package, such as `examples` or `*/*.tmp`. Excluded directories are left out along with their contents. `.git`, `.terraform`, and state files are always left out.", sourceDirKey),
+				Type:        schema.TypeList,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			archiveFormatKey: {
+				Description:  fmt.Sprintf("Format of the package, `%s` or `%s`. Defaults to `%s`, which Artifactory's Terraform module layout and `jf tf publish` use. Set to `%s` to publish a tarball instead.", archive.Zip, archive.TarGzip, archive.Zip, archive.TarGzip),
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      string(archive.Zip),
+				ValidateFunc: validation.StringInSlice([]string{string(archive.Zip), string(archive.TarGzip)}, false),
+			},
+			deleteOldPath: {
+				Description: "Set to false if the published package should be orphaned on destruction of the resource or change of its path, such as when the version is incremented. Defaults to true.",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+			},
+			triggersKey: {
+				Description: "Arbitrary map of values that, when changed, will trigger re-publishing of this resource's package.",
+				Type:        schema.TypeMap,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			fullPathKey: {
+				Description: "Path the package is published to, relative to the provider's URL",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			sourceHashKey: {
+				Description: "SHA256 of the package's entries and their contents, which is computed when planning without building the package. It changes, causing the package to be published again, only when the package's contents change.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			archiveSHA256Key: {
+				Description: "SHA256 of the package as it was built and published. The package is published again if the published file's `sha256` no longer matches it.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			sha1Key: {
+				Description: "SHA1 of the published package",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			sha256Key: {
+				Description: "SHA256 of the published package",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			md5Key: {
+				Description: "MD5 of the published package",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+		},
+	}
+}
+
+// terraformModulePath returns the path a module is published to, relative to the provider's URL.
+func terraformModulePath(d resourceGetter) (string, error) {
+	module := layout.TerraformModule{
+		Namespace: d.Get(namespaceKey).(string),
+		Name:      d.Get(terraformNameKey).(string),
+		System:    d.Get(systemKey).(string),
+		Version:   d.Get(versionKey).(string),
+	}
+	if err := module.Validate(); err != nil {
+		return "", err
+	}
+
+	return client.JoinPath(d.Get(repositoryKey).(string), module.Path(d.Get(archiveFormatKey).(string)))
+}
+
+// terraformModuleOptions returns the archive.Options of a module's package.
+func terraformModuleOptions(d resourceGetter) archive.Options {
+	return archive.Options{
+		Format:       archive.Format(d.Get(archiveFormatKey).(string)),
+		Excludes:     stringList(d.Get(excludesKey)),
+		ExcludeNames: terraformModuleExcludeNames,
+	}
+}
+
+// writeTerraformModule writes the package of a module's directory to w, returning its SHA256.
+func writeTerraformModule(w io.Writer, d resourceGetter) (string, error) {
+	sourceDir := d.Get(sourceDirKey).(string)
+	hash := sha256.New()
+	if err := archive.Write(io.MultiWriter(w, hash), sourceDir, terraformModuleOptions(d)); err != nil {
+		return "", fmt.Errorf("unable to package module %s: %s", sourceDir, err)
+	}
+
+	return fmt.Sprintf("%x", hash.Sum(nil)), nil
+}
+
+func resourceTerraformModuleCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*client.Client)
+
+	fullPath, err := terraformModulePath(d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	sourceDir := d.Get(sourceDirKey).(string)
+	sourceHash, err := archive.Hash(sourceDir, terraformModuleOptions(d))
+	if err != nil {
+		return diag.Errorf("unable to hash contents of module %s: %s", sourceDir, err)
+	}
+
+	file, err := os.CreateTemp("", "terraform-provider-artifacts-*."+d.Get(archiveFormatKey).(string))
+	if err != nil {
+		return diag.Errorf("unable to create temporary package file: %s", err)
+	}
+	defer os.Remove(file.Name())
+
+	archiveSHA256, err := writeTerraformModule(file, d)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err := client.Upload(ctx, fullPath, file.Name(), nil); err != nil {
+		return diag.Errorf("failure publishing module %s: %s", sourceDir, err)
+	}
+
+	d.SetId(fullPath)
+	for key, value := range map[string]string{fullPathKey: fullPath, sourceHashKey: sourceHash, archiveSHA256Key: archiveSHA256} {
+		if err := d.Set(key, value); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	return resourceTerraformModuleRead(ctx, d, meta)
+}
+
+func resourceTerraformModuleRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*client.Client)
+
+	checksums, err := client.Checksums(ctx, d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if checksums.Get(client.ChecksumType) == "" {
+		d.SetId("")
+		return nil
+	}
+
+	if err := setChecksums(d, checksums); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return nil
+}
+
+// resourceTerraformModuleUpdate publishes the package again if it or its path changed. Other changes only affect
+// later operations.
+func resourceTerraformModuleUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*client.Client)
+
+	fullPath, err := terraformModulePath(d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if fullPath != d.Id() {
+		if d.Get(deleteOldPath).(bool) {
+			if err := deleteExisting(ctx, client, []string{d.Id()}); err != nil {
+				return diag.FromErr(err)
+			}
+		}
+
+		return resourceTerraformModuleCreate(ctx, d, meta)
+	}
+
+	if d.HasChanges(sourceHashKey, triggersKey, sha1Key, sha256Key, md5Key) {
+		return resourceTerraformModuleCreate(ctx, d, meta)
+	}
+
+	return resourceTerraformModuleRead(ctx, d, meta)
+}
+
+func resourceTerraformModuleDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*client.Client)
+
+	if d.Get(deleteOldPath).(bool) {
+		if err := deleteExisting(ctx, client, []string{d.Id()}); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	return nil
+}
+
+func resourceTerraformModuleDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if d.HasChange(triggersKey) {
+		if err := setNewComputedArchiveChecksums(d); err != nil {
+			return err
+		}
+	}
+
+	pathChanged := true
+	if d.NewValueKnown(repositoryKey) && d.NewValueKnown(namespaceKey) && d.NewValueKnown(terraformNameKey) &&
+		d.NewValueKnown(systemKey) && d.NewValueKnown(versionKey) && d.NewValueKnown(archiveFormatKey) {
+		fullPath, err := terraformModulePath(d)
+		if err != nil {
+			return err
+		}
+
+		pathChanged = fullPath != d.Get(fullPathKey).(string)
+		if pathChanged {
+			if err := d.SetNew(fullPathKey, fullPath); err != nil {
+				return err
+			}
+		}
+	} else if err := d.SetNewComputed(fullPathKey); err != nil {
+		return err
+	}
+
+	if d.Id() != "" && pathChanged {
+		if err := setNewComputedArchiveChecksums(d); err != nil {
+			return err
+		}
+	}
+
+	if !d.NewValueKnown(sourceDirKey) || !d.NewValueKnown(excludesKey) || !d.NewValueKnown(archiveFormatKey) {
+		return setNewComputedArchive(d)
+	}
+
+	// directories that don't exist yet at plan time, such as those populated by other resources, are never
+	// considered drifted
+	sourceDir := d.Get(sourceDirKey).(string)
+	if _, err := os.Stat(sourceDir); err != nil {
+		return nil
+	}
+
+	// the contents are hashed without building and compressing the package, which is only done when publishing it
+	sourceHash, err := archive.Hash(sourceDir, terraformModuleOptions(d))
+	if err != nil {
+		return fmt.Errorf("unable to hash contents of module %s: %s", sourceDir, err)
+	}
+
+	if sourceHash != d.Get(sourceHashKey).(string) {
+		if err := d.SetNew(sourceHashKey, sourceHash); err != nil {
+			return err
+		}
+		return setNewComputedArchiveChecksums(d)
+	}
+
+	// the published file's checksum differing from the package's means it was changed remotely
+	if d.Id() != "" && d.Get(sha256Key).(string) != d.Get(archiveSHA256Key).(string) {
+		return setNewComputedArchiveChecksums(d)
+	}
+
+	return nil
+}
@@ -0,0 +1,51 @@
+// Copyright 2021 Splunk, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccResourceTerraformModule(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV5ProviderFactories: protoV5ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testResourceTerraformModuleConfig,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("artifacts_terraform_module.test", "full_path", "sas-terraform/splunk/terraform-provider-artifacts-test/local/1.0.0.zip"),
+					resource.TestCheckResourceAttrPair("artifacts_terraform_module.test", "archive_sha256", "artifacts_terraform_module.test", "sha256"),
+				),
+			},
+		},
+	})
+}
+
+const testResourceTerraformModuleConfig = `
+provider "artifacts" {
+  url = "https://repo.splunk.com/artifactory"
+}
+
+resource "artifacts_terraform_module" "test" {
+  repository = "sas-terraform"
+  namespace  = "splunk"
+  name       = "terraform-provider-artifacts-test"
+  system     = "local"
+  version    = "1.0.0"
+  source_dir = "test_files/terraform_module"
+}
+`
@@ -0,0 +1,467 @@
+// Copyright 2021 Splunk, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	"terraform-provider-artifacts/internal/provider/internal/client"
+	"terraform-provider-artifacts/internal/provider/internal/layout"
+	"terraform-provider-artifacts/internal/provider/internal/signing"
+)
+
+func resourceTerraformProvider() *schema.Resource {
+	providerSchema := map[string]*schema.Schema{
+		repositoryKey: {
+			Description: fmt.Sprintf("Key of the Terraform repository to publish to. The provider's `%s` isn't applied, as provider paths are relative to the repository.", basePathKey),
+			Type:        schema.TypeString,
+			Required:    true,
+			ForceNew:    true,
+		},
+		namespaceKey: {
+			Description: "Namespace of the provider, such as the team that owns it",
+			Type:        schema.TypeString,
+			Required:    true,
+		},
+		terraformNameKey: {
+			Description: "Name of the provider, which the registry calls its type, such as `artifacts` for `terraform-provider-artifacts`",
+			Type:        schema.TypeString,
+			Required:    true,
+		},
+		versionKey: {
+			Description: "Semantic version of the provider, such as `1.2.3`",
+			Type:        schema.TypeString,
+			Required:    true,
+		},
+		platformKey: {
+			Description: "Zip package of the provider for an operating system and architecture",
+			Type:        schema.TypeList,
+			Required:    true,
+			MinItems:    1,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					osKey: {
+						Description: "Operating system the package is built for, such as `linux`",
+						Type:        schema.TypeString,
+						Required:    true,
+					},
+					archKey: {
+						Description: "Architecture the package is built for, such as `amd64`",
+						Type:        schema.TypeString,
+						Required:    true,
+					},
+					uploadFileKey: {
+						Description: "Zip package file to publish",
+						Type:        schema.TypeString,
+						Required:    true,
+					},
+				},
+			},
+		},
+		signingKeyKey: {
+			Description:  fmt.Sprintf("Armored OpenPGP private key to sign the SHA256SUMS file with. The registry's clients verify the signature with its public key. Exactly one of `%s` or `%s` must be set.", signingKeyKey, signingKeyWOKey),
+			Type:         schema.TypeString,
+			Optional:     true,
+			Sensitive:    true,
+			ValidateFunc: validateSigningKey,
+			ExactlyOneOf: []string{signingKeyKey, signingKeyWOKey},
+		},
+		signingKeyPassphraseKey: {
+			Description: fmt.Sprintf("Passphrase to decrypt `%s` with, if it is encrypted.", signingKeyKey),
+			Type:        schema.TypeString,
+			Optional:    true,
+			Sensitive:   true,
+		},
+		deleteOldPath: {
+			Description: "Set to false if the published files should be orphaned on destruction of the resource or change of their paths, such as when the version is incremented. Defaults to true.",
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Default:     true,
+		},
+		triggersKey: {
+			Description: "Arbitrary map of values that, when changed, will trigger re-publishing of this resource's files.",
+			Type:        schema.TypeMap,
+			Optional:    true,
+			Elem:        &schema.Schema{Type: schema.TypeString},
+		},
+		packagePathsKey: {
+			Description: fmt.Sprintf("Paths the packages are published to, relative to the provider's URL, in the order of `%s`", platformKey),
+			Type:        schema.TypeList,
+			Computed:    true,
+			Elem:        &schema.Schema{Type: schema.TypeString},
+		},
+		shasumsPathKey: {
+			Description: "Path the SHA256SUMS file is published to, relative to the provider's URL",
+			Type:        schema.TypeString,
+			Computed:    true,
+		},
+		shasumsSignaturePathKey: {
+			Description: "Path the signature of the SHA256SUMS file is published to, relative to the provider's URL",
+			Type:        schema.TypeString,
+			Computed:    true,
+		},
+		shasumsKey: {
+			Description: "Content of the SHA256SUMS file, listing the SHA256 of each package",
+			Type:        schema.TypeString,
+			Computed:    true,
+		},
+		keyIDKey: {
+			Description: fmt.Sprintf("ID of `%s`, as 16 hexadecimal digits, as the registry lists it", signingKeyKey),
+			Type:        schema.TypeString,
+			Computed:    true,
+		},
+	}
+
+	// the signing key may instead be write-only, which is never stored
+	for key, value := range signingKeyWOSDKSchema(true) {
+		providerSchema[key] = value
+	}
+
+	return &schema.Resource{
+		Description:   "Publish a Terraform provider's zip packages to a Terraform repository in Artifactory, along with a SHA256SUMS file and its signature",
+		CreateContext: resourceTerraformProviderCreate,
+		ReadContext:   resourceTerraformProviderRead,
+		UpdateContext: resourceTerraformProviderUpdate,
+		DeleteContext: resourceTerraformProviderDelete,
+		CustomizeDiff: resourceTerraformProviderDiff,
+		Schema:        providerSchema,
+	}
+}
+
+// terraformProviderRelease is a version of a Terraform provider to publish, and where to publish it.
+type terraformProviderRelease struct {
+	provider  layout.TerraformProvider
+	platforms []layout.TerraformPlatform
+	// files are the package files, in the order of platforms.
+	files []string
+	// packagePaths are the paths to publish the packages to, in the order of platforms.
+	packagePaths []string
+	shasumsPath  string
+}
+
+// readTerraformProviderRelease reads the provider and platforms in d, and determines the paths to publish them to.
+func readTerraformProviderRelease(d resourceGetter) (terraformProviderRelease, error) {
+	release := terraformProviderRelease{
+		provider: layout.TerraformProvider{
+			Namespace: d.Get(namespaceKey).(string),
+			Type:      d.Get(terraformNameKey).(string),
+			Version:   d.Get(versionKey).(string),
+		},
+	}
+
+	for _, platform := range d.Get(platformKey).([]interface{}) {
+		platform := platform.(map[string]interface{})
+		release.platforms = append(release.platforms, layout.TerraformPlatform{OS: platform[osKey].(string), Arch: platform[archKey].(string)})
+		release.files = append(release.files, platform[uploadFileKey].(string))
+	}
+
+	if err := release.provider.Validate(release.platforms); err != nil {
+		return release, err
+	}
+
+	repository := d.Get(repositoryKey).(string)
+	for _, platform := range release.platforms {
+		packagePath, err := client.JoinPath(repository, release.provider.PackagePath(platform))
+		if err != nil {
+			return release, err
+		}
+		release.packagePaths = append(release.packagePaths, packagePath)
+	}
+
+	shasumsPath, err := client.JoinPath(repository, release.provider.SHA256SumsPath())
+	if err != nil {
+		return release, err
+	}
+	release.shasumsPath = shasumsPath
+
+	return release, nil
+}
+
+// paths returns the paths of all of the release's published files.
+func (r terraformProviderRelease) paths() []string {
+	return append(append([]string{}, r.packagePaths...), r.shasumsPath, r.shasumsPath+"."+shasumsSignatureExtension)
+}
+
+// shasums returns the content of the release's SHA256SUMS file, from its local package files.
+func (r terraformProviderRelease) shasums(c *client.Client) (string, error) {
+	sha256s := make(map[layout.TerraformPlatform]string, len(r.platforms))
+	for i, platform := range r.platforms {
+		checksums, err := c.FileChecksums(r.files[i])
+		if err != nil {
+			return "", err
+		}
+		sha256s[platform] = checksums.SHA256
+	}
+
+	return r.provider.SHA256Sums(sha256s), nil
+}
+
+func resourceTerraformProviderCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*client.Client)
+
+	release, err := readTerraformProviderRelease(d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	signer, err := signing.NewSigner(signingKey(d))
+	if err != nil {
+		return diag.Errorf("unable to use %s: %s", signingKeyKey, err)
+	}
+
+	shasums, err := release.shasums(client)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	for i, packagePath := range release.packagePaths {
+		filePath, err := filepath.Abs(release.files[i])
+		if err != nil {
+			return diag.Errorf("unable to determine absolute path for file %s", release.files[i])
+		}
+
+		if err := client.Upload(ctx, packagePath, filePath, nil); err != nil {
+			return diag.Errorf("failure publishing package %s: %s", filePath, err)
+		}
+	}
+
+	signature, err := signer.DetachSign(strings.NewReader(shasums))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	// the SHA256SUMS file is published last, so that it never lists packages that aren't published yet
+	if err := client.UploadContent(ctx, release.shasumsPath+"."+shasumsSignatureExtension, signature, nil); err != nil {
+		return diag.Errorf("failure publishing signature of SHA256SUMS: %s", err)
+	}
+	if err := client.UploadContent(ctx, release.shasumsPath, []byte(shasums), nil); err != nil {
+		return diag.Errorf("failure publishing SHA256SUMS: %s", err)
+	}
+
+	d.SetId(release.shasumsPath)
+	if err := setTerraformProviderRelease(d, release); err != nil {
+		return diag.FromErr(err)
+	}
+	for key, value := range map[string]string{shasumsKey: shasums, keyIDKey: signer.KeyID()} {
+		if err := d.Set(key, value); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	return resourceTerraformProviderRead(ctx, d, meta)
+}
+
+// resourceTerraformProviderRead reads the published SHA256SUMS file. Packages that are missing, or that don't match
+// it, and a missing signature, clear shasums so that everything is published again.
+func resourceTerraformProviderRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*client.Client)
+
+	content, found, err := client.FileContent(ctx, d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if !found {
+		d.SetId("")
+		return nil
+	}
+
+	shasums := string(content)
+	for _, packagePath := range stringList(d.Get(packagePathsKey)) {
+		checksums, err := client.Checksums(ctx, packagePath)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+
+		if checksums.SHA256 == "" || !strings.Contains(shasums, checksums.SHA256+"  ") {
+			shasums = ""
+		}
+	}
+
+	checksums, err := client.Checksums(ctx, d.Id()+"."+shasumsSignatureExtension)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if checksums.SHA1 == "" {
+		shasums = ""
+	}
+
+	if err := d.Set(shasumsKey, shasums); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return nil
+}
+
+// resourceTerraformProviderUpdate deletes files whose paths are no longer published to, and publishes everything
+// again if the packages, their paths, or the signing key changed. Other changes only affect later operations.
+func resourceTerraformProviderUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*client.Client)
+
+	release, err := readTerraformProviderRelease(d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if d.Get(deleteOldPath).(bool) {
+		wanted := map[string]bool{}
+		for _, path := range release.paths() {
+			wanted[path] = true
+		}
+
+		oldPackagePaths, _ := d.GetChange(packagePathsKey)
+		oldPaths := append(stringList(oldPackagePaths), d.Id(), d.Id()+"."+shasumsSignatureExtension)
+		unwanted := []string{}
+		for _, path := range oldPaths {
+			if !wanted[path] {
+				unwanted = append(unwanted, path)
+			}
+		}
+
+		if err := deleteExisting(ctx, client, unwanted); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	if d.HasChanges(shasumsKey, shasumsPathKey, packagePathsKey, platformKey, signingKeyKey, signingKeyPassphraseKey, signingKeyWOVersionKey, triggersKey) {
+		return resourceTerraformProviderCreate(ctx, d, meta)
+	}
+
+	return resourceTerraformProviderRead(ctx, d, meta)
+}
+
+func resourceTerraformProviderDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*client.Client)
+
+	if d.Get(deleteOldPath).(bool) {
+		paths := append(stringList(d.Get(packagePathsKey)), d.Id(), d.Id()+"."+shasumsSignatureExtension)
+		if err := deleteExisting(ctx, client, paths); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	return nil
+}
+
+func resourceTerraformProviderDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if d.HasChange(triggersKey) {
+		if err := d.SetNewComputed(shasumsKey); err != nil {
+			return err
+		}
+	}
+
+	if d.NewValueKnown(signingKeyKey) && d.NewValueKnown(signingKeyWOKey) {
+		armoredKey, _ := signingKey(d)
+		entity, err := signing.ReadKey(armoredKey, "")
+		if err != nil {
+			return fmt.Errorf("unable to use %s: %s", signingKeyKey, err)
+		}
+		if entity.PrimaryKey.KeyIdString() != d.Get(keyIDKey).(string) {
+			if err := d.SetNew(keyIDKey, entity.PrimaryKey.KeyIdString()); err != nil {
+				return err
+			}
+		}
+	} else if err := d.SetNewComputed(keyIDKey); err != nil {
+		return err
+	}
+
+	c, ok := meta.(*client.Client)
+	if !ok {
+		return nil
+	}
+
+	// the platforms' values are only known individually
+	knownKeys := []string{repositoryKey, namespaceKey, terraformNameKey, versionKey, platformKey}
+	if d.NewValueKnown(platformKey) {
+		for i := range d.Get(platformKey).([]interface{}) {
+			knownKeys = append(knownKeys, fmt.Sprintf("%s.%d.%s", platformKey, i, osKey), fmt.Sprintf("%s.%d.%s", platformKey, i, archKey))
+		}
+	}
+	for _, key := range knownKeys {
+		if !d.NewValueKnown(key) {
+			for _, key := range []string{packagePathsKey, shasumsPathKey, shasumsSignaturePathKey, shasumsKey} {
+				if err := d.SetNewComputed(key); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+	}
+
+	release, err := readTerraformProviderRelease(d)
+	if err != nil {
+		return err
+	}
+
+	if !reflect.DeepEqual(release.packagePaths, stringList(d.Get(packagePathsKey))) {
+		if err := d.SetNew(packagePathsKey, release.packagePaths); err != nil {
+			return err
+		}
+	}
+	for key, value := range map[string]string{
+		shasumsPathKey:          release.shasumsPath,
+		shasumsSignaturePathKey: release.shasumsPath + "." + shasumsSignatureExtension,
+	} {
+		if value != d.Get(key).(string) {
+			if err := d.SetNew(key, value); err != nil {
+				return err
+			}
+		}
+	}
+
+	// packages that don't exist yet at plan time, such as those built by other resources, are read on apply
+	for i, file := range release.files {
+		if _, err := os.Stat(file); err != nil || !d.NewValueKnown(fmt.Sprintf("%s.%d.%s", platformKey, i, uploadFileKey)) {
+			return d.SetNewComputed(shasumsKey)
+		}
+	}
+
+	shasums, err := release.shasums(c)
+	if err != nil {
+		return err
+	}
+
+	if shasums != d.Get(shasumsKey).(string) {
+		return d.SetNew(shasumsKey, shasums)
+	}
+
+	return nil
+}
+
+// setTerraformProviderRelease sets the paths a release is published to.
+func setTerraformProviderRelease(d *schema.ResourceData, release terraformProviderRelease) error {
+	if err := d.Set(packagePathsKey, release.packagePaths); err != nil {
+		return err
+	}
+
+	for key, value := range map[string]string{
+		shasumsPathKey:          release.shasumsPath,
+		shasumsSignaturePathKey: release.shasumsPath + "." + shasumsSignatureExtension,
+	} {
+		if err := d.Set(key, value); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
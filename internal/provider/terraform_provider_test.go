@@ -0,0 +1,220 @@
+// Copyright 2021 Splunk, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+// testSigningKey returns a new armored private key, and its ID.
+func testSigningKey(t *testing.T) (string, string) {
+	entity, err := openpgp.NewEntity("Test Signer", "", "signer@example.com", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buffer := &bytes.Buffer{}
+	writer, err := armor.Encode(buffer, openpgp.PrivateKeyType, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := entity.SerializePrivate(writer, nil); err != nil {
+		t.Fatal(err)
+	}
+	writer.Close()
+
+	return buffer.String(), entity.PrimaryKey.KeyIdString()
+}
+
+func TestAccResourceTerraformProvider(t *testing.T) {
+	signingKey, keyID := testSigningKey(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV5ProviderFactories: protoV5ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testResourceTerraformProviderConfig, signingKey),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("artifacts_terraform_provider.test", "package_paths.#", "2"),
+					resource.TestCheckResourceAttr("artifacts_terraform_provider.test", "package_paths.0", "sas-terraform/splunk/artifacts-test/terraform-provider-artifacts-test_1.0.0_linux_amd64.zip"),
+					resource.TestCheckResourceAttr("artifacts_terraform_provider.test", "shasums_path", "sas-terraform/splunk/artifacts-test/terraform-provider-artifacts-test_1.0.0_SHA256SUMS"),
+					resource.TestCheckResourceAttr("artifacts_terraform_provider.test", "shasums_signature_path", "sas-terraform/splunk/artifacts-test/terraform-provider-artifacts-test_1.0.0_SHA256SUMS.sig"),
+					resource.TestCheckResourceAttr("artifacts_terraform_provider.test", "key_id", keyID),
+				),
+			},
+		},
+	})
+}
+
+// TestTerraformProviderWriteOnlySigningKey plans and applies a provider signed with signing_key_wo, checking that the
+// key is used without being stored in plan or state.
+func TestTerraformProviderWriteOnlySigningKey(t *testing.T) {
+	signingKey, keyID := testSigningKey(t)
+
+	files := map[string][]byte{}
+	server := fakeRepository(files)
+	defer server.Close()
+
+	ctx := context.Background()
+	providerServer, schemas := configuredProviderServer(t, server.URL)
+	providerType := schemas.ResourceSchemas[terraformProviderResourceKey].ValueType().(tftypes.Object)
+	config := testTerraformProviderValue(providerType, map[string]tftypes.Value{
+		signingKeyWOKey:        tftypes.NewValue(tftypes.String, signingKey),
+		signingKeyWOVersionKey: tftypes.NewValue(tftypes.Number, 1),
+	})
+	prior := tftypes.NewValue(providerType, nil)
+
+	planResp, err := providerServer.PlanResourceChange(ctx, &tfprotov5.PlanResourceChangeRequest{
+		TypeName:         terraformProviderResourceKey,
+		PriorState:       dynamicValue(t, providerType, prior),
+		ProposedNewState: dynamicValue(t, providerType, config),
+		Config:           dynamicValue(t, providerType, config),
+	})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	checkDiagnostics(t, planResp.Diagnostics)
+
+	applyResp, err := providerServer.ApplyResourceChange(ctx, &tfprotov5.ApplyResourceChangeRequest{
+		TypeName:       terraformProviderResourceKey,
+		PriorState:     dynamicValue(t, providerType, prior),
+		PlannedState:   planResp.PlannedState,
+		Config:         dynamicValue(t, providerType, config),
+		PlannedPrivate: planResp.PlannedPrivate,
+	})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	checkDiagnostics(t, applyResp.Diagnostics)
+
+	for name, dynamic := range map[string]*tfprotov5.DynamicValue{"plan": planResp.PlannedState, "state": applyResp.NewState} {
+		value, err := dynamic.Unmarshal(providerType)
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		attributes := map[string]tftypes.Value{}
+		if err := value.As(&attributes); err != nil {
+			t.Fatalf("err: %s", err)
+		}
+
+		if !attributes[signingKeyWOKey].IsNull() {
+			t.Errorf("expected %s to be null in the %s", signingKeyWOKey, name)
+		}
+		if !attributes[keyIDKey].Equal(tftypes.NewValue(tftypes.String, keyID)) {
+			t.Errorf("expected %s %s in the %s, got %s", keyIDKey, keyID, name, attributes[keyIDKey])
+		}
+	}
+
+	signature := string(files["terraform/splunk/artifacts-test/terraform-provider-artifacts-test_1.0.0_SHA256SUMS.sig"])
+	if signature == "" {
+		t.Errorf("expected the SHA256SUMS to be signed, got files %v", files)
+	}
+	if strings.Contains(string(applyResp.NewState.JSON)+string(applyResp.NewState.MsgPack), "PRIVATE KEY") {
+		t.Error("expected the signing key not to be stored in state")
+	}
+}
+
+// TestTerraformProviderInvalidSigningKey checks that a signing key that can't be read fails the plan, as it would the
+// apply, even if it wasn't known when the configuration was validated.
+func TestTerraformProviderInvalidSigningKey(t *testing.T) {
+	ctx := context.Background()
+	providerServer, schemas := configuredProviderServer(t, "https://repo.example.com/artifactory")
+	providerType := schemas.ResourceSchemas[terraformProviderResourceKey].ValueType().(tftypes.Object)
+	config := testTerraformProviderValue(providerType, map[string]tftypes.Value{
+		signingKeyKey: tftypes.NewValue(tftypes.String, "not a key"),
+	})
+	prior := tftypes.NewValue(providerType, nil)
+
+	planResp, err := providerServer.PlanResourceChange(ctx, &tfprotov5.PlanResourceChangeRequest{
+		TypeName:         terraformProviderResourceKey,
+		PriorState:       dynamicValue(t, providerType, prior),
+		ProposedNewState: dynamicValue(t, providerType, config),
+		Config:           dynamicValue(t, providerType, config),
+	})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	for _, diagnostic := range planResp.Diagnostics {
+		if diagnostic.Severity == tfprotov5.DiagnosticSeverityError && strings.Contains(diagnostic.Detail+diagnostic.Summary, "unable to use signing_key") {
+			return
+		}
+	}
+	t.Errorf("expected the plan to fail for an invalid signing key, got %v", planResp.Diagnostics)
+}
+
+// testTerraformProviderValue returns an artifacts_terraform_provider with a single platform and the given signing
+// attributes.
+func testTerraformProviderValue(providerType tftypes.Object, signingValues map[string]tftypes.Value) tftypes.Value {
+	platformType := providerType.AttributeTypes[platformKey].(tftypes.List).ElementType.(tftypes.Object)
+
+	values := map[string]tftypes.Value{
+		repositoryKey:    tftypes.NewValue(tftypes.String, "terraform"),
+		namespaceKey:     tftypes.NewValue(tftypes.String, "splunk"),
+		terraformNameKey: tftypes.NewValue(tftypes.String, "artifacts-test"),
+		versionKey:       tftypes.NewValue(tftypes.String, "1.0.0"),
+		platformKey: tftypes.NewValue(providerType.AttributeTypes[platformKey], []tftypes.Value{
+			tftypes.NewValue(platformType, map[string]tftypes.Value{
+				osKey:         tftypes.NewValue(tftypes.String, "linux"),
+				archKey:       tftypes.NewValue(tftypes.String, "amd64"),
+				uploadFileKey: tftypes.NewValue(tftypes.String, "test_files/terraform-provider-artifacts-test_1.0.0_linux_amd64.zip"),
+			}),
+		}),
+	}
+	for key, value := range signingValues {
+		values[key] = value
+	}
+
+	return objectValue(providerType, values)
+}
+
+const testResourceTerraformProviderConfig = `
+provider "artifacts" {
+  url = "https://repo.splunk.com/artifactory"
+}
+
+resource "artifacts_terraform_provider" "test" {
+  repository = "sas-terraform"
+  namespace  = "splunk"
+  name       = "artifacts-test"
+  version    = "1.0.0"
+
+  platform {
+    os          = "linux"
+    arch        = "amd64"
+    upload_file = "test_files/terraform-provider-artifacts-test_1.0.0_linux_amd64.zip"
+  }
+
+  platform {
+    os          = "darwin"
+    arch        = "arm64"
+    upload_file = "test_files/terraform-provider-artifacts-test_1.0.0_darwin_arm64.zip"
+  }
+
+  signing_key = <<EOT
+%s
+EOT
+}
+`
@@ -17,128 +17,491 @@ package provider
 import (
 	"context"
 	"fmt"
+	"os"
 	"path/filepath"
+	"strings"
 
-	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
-	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	resourceschema "github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
 
 	"terraform-provider-artifacts/internal/provider/internal/client"
 )
 
-func resourceUpload() *schema.Resource {
-	return &schema.Resource{
-		Description:   "Upload a file to Artifactory",
-		CreateContext: resourceUploadCreate,
-		ReadContext:   resourceUploadRead,
-		UpdateContext: resourceUploadUpdate,
-		DeleteContext: resourceUploadDelete,
-		CustomizeDiff: resourceUploadDiff,
-		Schema: map[string]*schema.Schema{
-			uploadPathKey: {
-				Description: "Path to upload to, relative to the provider's URL",
-				Type:        schema.TypeString,
-				Required:    true,
+// uploadComputedKeys are the computed attributes of artifacts_upload.
+var uploadComputedKeys = []string{
+	idKey, fullPathKey, sha1Key, sha256Key, md5Key, detectedPackageTypeKey, groupIDKey, packageNameKey, versionKey,
+	sidecarPathsKey,
+}
+
+// uploadDescriptions are the descriptions of the attributes artifacts_upload shares with artifacts_archive_upload,
+// whose SDKv2 schema is built by uploadSDKSchema, so that the two schemas describe them the same way.
+var uploadDescriptions = map[string]string{
+	pathVarsKey:               fmt.Sprintf("Values of `{name}` tokens in `%s`, such as `version`, `os`, or `arch`. Merged with, and taking precedence over, the provider's `%s`.", uploadPathKey, pathVarsKey),
+	deleteOldPath:             fmt.Sprintf("Set to false if the remote file should be orphaned on destruction of the resource or change of %s value. Defaults to true.", uploadPathKey),
+	sha1Key:                   "SHA1 of the uploaded file",
+	sha256Key:                 "SHA256 of the uploaded file",
+	md5Key:                    "MD5 of the uploaded file",
+	triggersKey:               "Arbitrary map of values that, when changed, will trigger re-uploading of this resource's file.",
+	checksumSidecarsKey:       fmt.Sprintf("Checksum types, of %s, to publish sidecar files for next to the uploaded file, named after the file with the checksum type appended, such as `artifact.tgz.sha256`. Each contains the checksum and the file's name, in the format of tools such as `sha256sum`.", strings.Join(checksumTypeStrings(), ", ")),
+	signingKeyKey:             fmt.Sprintf("Armored OpenPGP private key to publish a detached signature with, next to the uploaded file, named after the file with `.%s` appended.", signatureExtension),
+	signingKeyPassphraseKey:   fmt.Sprintf("Passphrase to decrypt `%s` with, if it is encrypted.", signingKeyKey),
+	signingKeyWOKey:           fmt.Sprintf("Write-only equivalent of `%s`, which is never stored in plan or state, so it may come from an ephemeral resource. Requires Terraform 1.11 or later. Change `%s` to publish the signature again after changing it.", signingKeyKey, signingKeyWOVersionKey),
+	signingKeyPassphraseWOKey: fmt.Sprintf("Write-only equivalent of `%s`, which is never stored in plan or state.", signingKeyPassphraseKey),
+	signingKeyWOVersionKey:    fmt.Sprintf("Version of `%s`. Changes to write-only attributes can't be detected, so changing this publishes the signature again.", signingKeyWOKey),
+	sidecarPathsKey:           fmt.Sprintf("Paths of the sidecar files published next to the uploaded file, relative to the provider's URL. They are updated and deleted along with the uploaded file, following `%s`.", deleteOldPath),
+}
+
+// uploadResource is artifacts_upload, which is implemented with the plugin framework. Its schema, and the logic it
+// shares with SDKv2 resources, are unchanged from its SDKv2 implementation, so that existing state is compatible.
+type uploadResource struct {
+	client *client.Client
+}
+
+func newUploadResource() resource.Resource {
+	return &uploadResource{}
+}
+
+// Metadata implements resource.Resource.
+func (r *uploadResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = uploadResourceKey
+}
+
+// Schema implements resource.Resource.
+func (r *uploadResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = resourceschema.Schema{
+		Description: "Upload a file to Artifactory",
+		Attributes: map[string]resourceschema.Attribute{
+			idKey: resourceschema.StringAttribute{
+				Description: "The ID of this resource.",
+				Computed:    true,
 			},
-			uploadFileKey: {
-				Description: "File containing content to upload",
-				Type:        schema.TypeString,
+			uploadPathKey: resourceschema.StringAttribute{
+				Description: fmt.Sprintf("Path to upload to, relative to the provider's URL and `%s`. May contain `{name}` tokens, which are replaced with values from `%s`. Defaults to the canonical path of the package in `%s`, when `%s` is set. The full path must start with a repository key, and must not have leading or trailing slashes, empty, `.`, or `..` segments, or whitespace around segments.", basePathKey, pathVarsKey, repositoryKey, packageTypeKey),
+				Optional:    true,
+				Validators:  []validator.String{uploadPathValidator{}},
+			},
+			pathVarsKey: resourceschema.MapAttribute{
+				Description: uploadDescriptions[pathVarsKey],
+				ElementType: types.StringType,
+				Optional:    true,
+			},
+			fullPathKey: resourceschema.StringAttribute{
+				Description: fmt.Sprintf("Path the file is uploaded to, relative to the provider's URL, after applying the provider's `%s` and expanding `{name}` tokens, or the canonical path of its package.", basePathKey),
+				Computed:    true,
+			},
+			uploadFileKey: resourceschema.StringAttribute{
+				Description: "File containing content to upload. It must be a regular file that can be read when the plan is made, if its path is known then and the resource is being created, or `upload_file` or `triggers` changed. A missing file of an existing resource that is otherwise unchanged only produces a warning. To upload a new file created by another resource during apply, reference one of that resource's attributes that isn't known until apply.",
 				Required:    true,
 			},
-			deleteOldPath: {
-				Description: fmt.Sprintf("Set to false if the remote file should be orphaned on destruction of the resource or change of %s value. Defaults to true.", uploadPathKey),
-				Type:        schema.TypeBool,
+			deleteOldPath: resourceschema.BoolAttribute{
+				Description: uploadDescriptions[deleteOldPath],
 				Optional:    true,
-				Default:     true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(true),
+			},
+			sha1Key: resourceschema.StringAttribute{
+				Description: uploadDescriptions[sha1Key],
+				Computed:    true,
 			},
-			sha1Key: {
-				Description: "SHA1 of the uploaded file",
-				Type:        schema.TypeString,
+			sha256Key: resourceschema.StringAttribute{
+				Description: uploadDescriptions[sha256Key],
 				Computed:    true,
 			},
-			triggersKey: {
-				Type:        schema.TypeMap,
-				Description: "Arbitrary map of values that, when changed, will trigger re-uploading of this resource's file.",
+			md5Key: resourceschema.StringAttribute{
+				Description: uploadDescriptions[md5Key],
+				Computed:    true,
+			},
+			triggersKey: resourceschema.MapAttribute{
+				Description: uploadDescriptions[triggersKey],
+				ElementType: types.StringType,
 				Optional:    true,
-				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			checksumSidecarsKey: resourceschema.ListAttribute{
+				Description: uploadDescriptions[checksumSidecarsKey],
+				ElementType: types.StringType,
+				Optional:    true,
+				Validators: []validator.List{
+					listvalidator.ValueStringsAre(stringvalidator.OneOf(checksumTypeStrings()...)),
+				},
+			},
+			signingKeyKey: resourceschema.StringAttribute{
+				Description: uploadDescriptions[signingKeyKey],
+				Optional:    true,
+				Sensitive:   true,
+				Validators: []validator.String{
+					stringValidator{description: "value must be an armored OpenPGP private key", validate: validateSigningKey},
+				},
+			},
+			signingKeyPassphraseKey: resourceschema.StringAttribute{
+				Description: uploadDescriptions[signingKeyPassphraseKey],
+				Optional:    true,
+				Sensitive:   true,
+			},
+			signingKeyWOKey: resourceschema.StringAttribute{
+				Description: uploadDescriptions[signingKeyWOKey],
+				Optional:    true,
+				Sensitive:   true,
+				WriteOnly:   true,
+				Validators: []validator.String{
+					stringValidator{description: "value must be an armored OpenPGP private key", validate: validateSigningKey},
+					stringvalidator.ConflictsWith(path.MatchRoot(signingKeyKey)),
+				},
+			},
+			signingKeyPassphraseWOKey: resourceschema.StringAttribute{
+				Description: uploadDescriptions[signingKeyPassphraseWOKey],
+				Optional:    true,
+				Sensitive:   true,
+				WriteOnly:   true,
+				Validators: []validator.String{
+					stringvalidator.ConflictsWith(path.MatchRoot(signingKeyPassphraseKey)),
+				},
+			},
+			signingKeyWOVersionKey: resourceschema.Int64Attribute{
+				Description: uploadDescriptions[signingKeyWOVersionKey],
+				Optional:    true,
+				Validators: []validator.Int64{
+					int64validator.AlsoRequires(path.MatchRoot(signingKeyWOKey)),
+				},
+			},
+			packageTypeKey: resourceschema.StringAttribute{
+				Description: fmt.Sprintf("Type of package the file is, one of %s. Its coordinates are read from its metadata: a POM, or the `pom.properties` in a jar, for `maven`; the `package.json` in its tarball for `npm`; and the `METADATA` or `PKG-INFO` of a wheel or source distribution for `pypi`. `auto` detects the type, treating files without its metadata, such as jars not built by Maven, as `generic` files, which have no coordinates.", strings.Join(packageTypeStrings(), ", ")),
+				Optional:    true,
+				Validators:  []validator.String{stringvalidator.OneOf(packageTypeStrings()...)},
+			},
+			repositoryKey: resourceschema.StringAttribute{
+				Description: fmt.Sprintf("Key of the repository to upload a package to, at its canonical path in the repository's layout, when `%s` isn't set. The provider's `%s` isn't applied.", uploadPathKey, basePathKey),
+				Optional:    true,
+			},
+			detectedPackageTypeKey: resourceschema.StringAttribute{
+				Description: fmt.Sprintf("Type of package the file is, as detected for `%s` `auto`", packageTypeKey),
+				Computed:    true,
+			},
+			groupIDKey: resourceschema.StringAttribute{
+				Description: "Group ID of a Maven package",
+				Computed:    true,
+			},
+			packageNameKey: resourceschema.StringAttribute{
+				Description: "Name of the package, or artifact ID of a Maven package",
+				Computed:    true,
+			},
+			versionKey: resourceschema.StringAttribute{
+				Description: "Version of the package",
+				Computed:    true,
+			},
+			sidecarPathsKey: resourceschema.ListAttribute{
+				Description: uploadDescriptions[sidecarPathsKey],
+				ElementType: types.StringType,
+				Computed:    true,
 			},
 		},
 	}
 }
 
-func resourceUploadCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*client.Client)
+// Configure implements resource.ResourceWithConfigure.
+func (r *uploadResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// the provider isn't configured yet when configuration is validated
+	if req.ProviderData == nil {
+		return
+	}
+
+	c, ok := req.ProviderData.(*client.Client)
+	if !ok {
+		resp.Diagnostics.AddError("Unexpected provider data", fmt.Sprintf("Expected *client.Client, got %T.", req.ProviderData))
+		return
+	}
+
+	r.client = c
+}
+
+// ModifyPlan implements resource.ResourceWithModifyPlan.
+func (r *uploadResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// there's nothing to plan when the resource is destroyed
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	d := newFrameworkData(ctx, req.State, &resp.Plan).withConfig(req.Config)
+	d.copyPrior(uploadComputedKeys...)
+
+	resp.Diagnostics.Append(validateUploadPlan(d, r.client)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := diffUpload(d, r.client); err != nil {
+		resp.Diagnostics.AddError("Unable to plan upload", err.Error())
+	}
+	resp.Diagnostics.Append(d.Diagnostics...)
+}
+
+// Create implements resource.Resource.
+func (r *uploadResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	resp.State.Raw = req.Plan.Raw
+	prior := tfsdk.State{Schema: req.Plan.Schema, Raw: tftypes.NewValue(req.Plan.Raw.Type(), nil)}
+	d := newFrameworkData(ctx, prior, &resp.State).withConfig(req.Config)
+
+	if err := createUpload(ctx, d, r.client); err != nil {
+		resp.Diagnostics.AddError("Unable to upload file", err.Error())
+	}
+	resp.Diagnostics.Append(d.Diagnostics...)
+
+	if resp.Diagnostics.HasError() {
+		// the upload is attempted again by the next apply
+		resp.State.RemoveResource(ctx)
+	}
+}
+
+// Read implements resource.Resource.
+func (r *uploadResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	d := newFrameworkData(ctx, req.State, &resp.State)
+
+	if err := readUpload(ctx, d, r.client); err != nil {
+		resp.Diagnostics.AddError("Unable to read uploaded file", err.Error())
+	}
+	resp.Diagnostics.Append(d.Diagnostics...)
+
+	if !resp.Diagnostics.HasError() && d.Id() == "" {
+		resp.State.RemoveResource(ctx)
+	}
+}
+
+// Update implements resource.Resource.
+func (r *uploadResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	resp.State.Raw = req.Plan.Raw
+	d := newFrameworkData(ctx, req.State, &resp.State).withConfig(req.Config)
+
+	if err := updateUpload(ctx, d, r.client); err != nil {
+		resp.Diagnostics.AddError("Unable to upload file", err.Error())
+	}
+	resp.Diagnostics.Append(d.Diagnostics...)
+}
+
+// Delete implements resource.Resource.
+func (r *uploadResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	d := newFrameworkData(ctx, req.State, &req.State)
+
+	if err := deleteUpload(ctx, d, r.client); err != nil {
+		resp.Diagnostics.AddError("Unable to delete uploaded file", err.Error())
+	}
+	resp.Diagnostics.Append(d.Diagnostics...)
+}
+
+// validateUploadPlan checks, once they are known, that upload_file can be uploaded, and that upload_path resolves to
+// a path in a repository. c is nil when the provider hasn't been configured, in which case the path isn't resolved.
+// upload_file is only required to be readable when it's about to be uploaded, on creation or when it or the triggers
+// changed, as it may otherwise be produced during apply, or be gone after an unchanged file was uploaded.
+func validateUploadPlan(d resourceDiffer, c *client.Client) diag.Diagnostics {
+	diags := diag.Diagnostics{}
+
+	if d.NewValueKnown(uploadFileKey) {
+		if err := validateUploadFile(d.Get(uploadFileKey).(string)); err != nil {
+			if d.Id() == "" || d.HasChanges(uploadFileKey, triggersKey) {
+				diags.AddAttributeError(path.Root(uploadFileKey), "Invalid upload file", err.Error())
+			} else {
+				diags.AddAttributeWarning(path.Root(uploadFileKey), "Invalid upload file", err.Error())
+			}
+		}
+	}
+
+	if c == nil || d.Get(uploadPathKey).(string) == "" || !d.NewValueKnown(uploadPathKey) || !d.NewValueKnown(pathVarsKey) {
+		return diags
+	}
+
+	// errors expanding the path are reported along with the rest of the plan
+	fullPath, err := resolveUploadPath(d, c)
+	if err != nil {
+		return diags
+	}
+
+	if err := validateUploadFullPath(fullPath); err != nil {
+		diags.AddAttributeError(path.Root(uploadPathKey), "Invalid upload path", err.Error())
+	}
 
+	return diags
+}
+
+// createUpload uploads an upload's file, and publishes its sidecars.
+func createUpload(ctx context.Context, d resourceData, c *client.Client) error {
 	d.SetId(artifactIDValue)
 
-	uploadPath := d.Get(uploadPathKey).(string)
+	uploadPath, pkg, err := resolveUploadTarget(d, c)
+	if err != nil {
+		return err
+	}
+	if err := d.Set(fullPathKey, uploadPath); err != nil {
+		return err
+	}
+	if err := setUploadPackage(d, pkg); err != nil {
+		return err
+	}
+
 	filename := d.Get(uploadFileKey).(string)
 	filePath, err := filepath.Abs(filename)
 	if err != nil {
-		return diag.Errorf("unable to determine absolute path for file %s", filePath)
+		return fmt.Errorf("unable to determine absolute path for file %s", filePath)
 	}
 
-	if err := client.Upload(uploadPath, filePath); err != nil {
-		return diag.Errorf("failure uploading file %s: %s", filePath, err)
+	if err := c.Upload(ctx, uploadPath, filePath, nil); err != nil {
+		return fmt.Errorf("failure uploading file %s: %s", filePath, err)
 	}
 
-	return resourceUploadRead(ctx, d, meta)
-}
+	if err := publishSidecars(ctx, d, c, uploadPath, filePath); err != nil {
+		return err
+	}
+	if err := d.Set(sidecarPathsKey, sidecarPaths(d, uploadPath)); err != nil {
+		return err
+	}
 
-func resourceUploadRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*client.Client)
+	return readUpload(ctx, d, c)
+}
 
-	uploadPath := d.Get(uploadPathKey).(string)
+// readUpload reads the checksums of an uploaded file, and which of its sidecars still exist. The ID of an upload
+// whose file no longer exists is cleared.
+func readUpload(ctx context.Context, d resourceData, c *client.Client) error {
+	uploadPath := d.Get(fullPathKey).(string)
+	if uploadPath == "" {
+		// state from before full_path existed
+		var err error
+		if uploadPath, err = resolveUploadPath(d, c); err != nil {
+			return fmt.Errorf("unable to resolve %s: %s", uploadPathKey, err)
+		}
+		if err := d.Set(fullPathKey, uploadPath); err != nil {
+			return err
+		}
+	}
 
-	checksums, err := client.Checksums(uploadPath)
+	checksums, err := c.Checksums(ctx, uploadPath)
 	if err != nil {
-		return diag.FromErr(err)
+		return err
 	}
 
-	if checksums.SHA1 == "" {
-		// missing SHA1 value indicates the resource wasn't found on the service, so mark this resource as missing
+	if checksums.Get(c.ChecksumType) == "" {
+		// missing checksum value indicates the resource wasn't found on the service, so mark this resource as missing
 		d.SetId("")
 	} else {
-		if err := d.Set(sha1Key, checksums.SHA1); err != nil {
-			return diag.FromErr(err)
+		if err := setChecksums(d, checksums); err != nil {
+			return err
+		}
+
+		// missing sidecars are dropped, so that they're published again
+		sidecars, err := existingSidecars(ctx, c, uploadPath, checksums, stringList(d.Get(sidecarPathsKey)))
+		if err != nil {
+			return err
+		}
+		if err := d.Set(sidecarPathsKey, sidecars); err != nil {
+			return err
 		}
 	}
 
 	return nil
 }
 
-func resourceUploadUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*client.Client)
-
-	if d.HasChange(uploadPathKey) {
-		if d.Get(deleteOldPath).(bool) {
-			uploadPathInterfaceOld, _ := d.GetChange(uploadPathKey)
-			uploadPathOld := uploadPathInterfaceOld.(string)
-			if err := client.Delete(uploadPathOld); err != nil {
-				return diag.Errorf("failure deleting old path %s: %s", uploadPathOld, err)
-			}
+// setChecksums stores each of the checksums in its computed attribute.
+func setChecksums(d resourceData, checksums client.Checksums) error {
+	for key, value := range map[string]string{
+		sha1Key:   checksums.SHA1,
+		sha256Key: checksums.SHA256,
+		md5Key:    checksums.MD5,
+	} {
+		if err := d.Set(key, value); err != nil {
+			return err
 		}
 	}
 
-	// after potentially deleting the old path, resourceUploadCreate does everything we need
-	return resourceUploadCreate(ctx, d, meta)
+	return nil
 }
 
-func resourceUploadDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*client.Client)
+// updateUpload deletes the old path and unwanted sidecars of an upload, then uploads it again.
+func updateUpload(ctx context.Context, d resourceData, c *client.Client) error {
+	if err := deleteOldUpload(ctx, d, c); err != nil {
+		return err
+	}
+	if err := deleteOldSidecars(ctx, d, c); err != nil {
+		return err
+	}
+
+	// after potentially deleting the old path, createUpload does everything we need
+	return createUpload(ctx, d, c)
+}
 
+// deleteOldUpload deletes the previously uploaded file when the full path has changed, unless delete_old_path is
+// false.
+func deleteOldUpload(ctx context.Context, d resourceData, c *client.Client) error {
+	if !d.HasChanges(uploadPathKey, fullPathKey) || !d.Get(deleteOldPath).(bool) {
+		return nil
+	}
+
+	uploadPathInterfaceOld, _ := d.GetChange(fullPathKey)
+	uploadPathOld := uploadPathInterfaceOld.(string)
+	if uploadPathOld == "" {
+		// state from before full_path existed, when upload_path was the full path
+		uploadPathInterfaceOld, _ = d.GetChange(uploadPathKey)
+		uploadPathOld = uploadPathInterfaceOld.(string)
+	}
+	if err := c.Delete(ctx, uploadPathOld); err != nil {
+		return fmt.Errorf("failure deleting old path %s: %s", uploadPathOld, err)
+	}
+
+	return nil
+}
+
+// deleteOldSidecars deletes previously published sidecars that are no longer wanted. Sidecars of an old path are kept
+// along with it when delete_old_path is false.
+func deleteOldSidecars(ctx context.Context, d resourceData, c *client.Client) error {
+	oldFullPath, newFullPath := d.GetChange(fullPathKey)
+	if oldFullPath.(string) != newFullPath.(string) && !d.Get(deleteOldPath).(bool) {
+		return nil
+	}
+
+	wanted := map[string]bool{}
+	for _, sidecarPath := range sidecarPaths(d, newFullPath.(string)) {
+		wanted[sidecarPath] = true
+	}
+
+	oldSidecars, _ := d.GetChange(sidecarPathsKey)
+	unwanted := []string{}
+	for _, sidecarPath := range stringList(oldSidecars) {
+		if !wanted[sidecarPath] {
+			unwanted = append(unwanted, sidecarPath)
+		}
+	}
+
+	return deleteExisting(ctx, c, unwanted)
+}
+
+// deleteUpload deletes an uploaded file and its sidecars, unless delete_old_path is false.
+func deleteUpload(ctx context.Context, d resourceGetter, c *client.Client) error {
 	if d.Get(deleteOldPath).(bool) {
-		uploadPath := d.Get(uploadPathKey).(string)
-		if err := client.Delete(uploadPath); err != nil {
-			return diag.Errorf("error attempting delete: %s", err)
+		uploadPath := d.Get(fullPathKey).(string)
+		if uploadPath == "" {
+			// state from before full_path existed, when upload_path was the full path
+			uploadPath = d.Get(uploadPathKey).(string)
+		}
+		if err := c.Delete(ctx, uploadPath); err != nil {
+			return fmt.Errorf("error attempting delete: %s", err)
+		}
+		if err := deleteExisting(ctx, c, stringList(d.Get(sidecarPathsKey))); err != nil {
+			return err
 		}
 	}
 
 	return nil
 }
 
-// resourceUploadDiff marks the checksum field as "known after apply" when any field that could
-// impact the artifact's contents has a change.
-func resourceUploadDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+// diffUpload marks the checksum fields as "known after apply" when any field that could impact the artifact's
+// contents has a change, or when the remote file no longer matches the local file. c is nil when the provider hasn't
+// been configured, in which case only changes to the configuration are considered.
+func diffUpload(d resourceDiffer, c *client.Client) error {
 	computeWhenKeys := []string{
 		uploadFileKey, // the file's path is the obvious "may result in changed contents" scenario
 		triggersKey,   // also check for changes to any triggers, as they are used to convey a potential change
@@ -146,12 +509,126 @@ func resourceUploadDiff(ctx context.Context, d *schema.ResourceDiff, meta interf
 
 	for _, key := range computeWhenKeys {
 		if d.HasChange(key) {
-			if err := d.SetNewComputed(sha1Key); err != nil {
+			if err := setNewComputedChecksums(d); err != nil {
 				return err
 			}
 			break
 		}
 	}
 
+	if c == nil {
+		return nil
+	}
+
+	changed, err := diffUploadTarget(d, c)
+	if err != nil {
+		return err
+	}
+	if err := diffSidecars(d); err != nil {
+		return err
+	}
+	if changed {
+		return setNewComputedChecksums(d)
+	}
+
+	drifted, err := uploadDrifted(d, c)
+	if err != nil {
+		return err
+	}
+	if drifted {
+		return setNewComputedChecksums(d)
+	}
+
+	return nil
+}
+
+// diffFullPath sets the new full_path from upload_path and path_vars, returning true if it changed or isn't known yet.
+// A changed full path means uploading to a new location, even if upload_path itself is unchanged.
+func diffFullPath(d resourceDiffer, c *client.Client) (bool, error) {
+	if !d.NewValueKnown(uploadPathKey) || !d.NewValueKnown(pathVarsKey) {
+		return true, d.SetNewComputed(fullPathKey)
+	}
+
+	fullPath, err := resolveUploadPath(d, c)
+	if err != nil {
+		return false, fmt.Errorf("unable to resolve %s: %s", uploadPathKey, err)
+	}
+
+	if fullPath != d.Get(fullPathKey).(string) {
+		return true, d.SetNew(fullPathKey, fullPath)
+	}
+
+	return false, nil
+}
+
+// resolveUploadPath returns the full path of an upload, from its upload_path and path_vars.
+func resolveUploadPath(d resourceGetter, c *client.Client) (string, error) {
+	return c.ResolvePath(d.Get(uploadPathKey).(string), stringMap(d.Get(pathVarsKey)))
+}
+
+// uploadDrifted returns true if the remote checksum stored in state differs from the local file's checksum.
+// Files that don't exist yet at plan time, such as those created by other resources, are never considered drifted.
+func uploadDrifted(d resourceDiffer, meta interface{}) (bool, error) {
+	c, ok := meta.(*client.Client)
+	if !ok || d.Id() == "" || !d.NewValueKnown(uploadFileKey) {
+		return false, nil
+	}
+
+	filePath, err := filepath.Abs(d.Get(uploadFileKey).(string))
+	if err != nil {
+		return false, nil
+	}
+	if _, err := os.Stat(filePath); err != nil {
+		return false, nil
+	}
+
+	// only the selected checksum is computed, as this runs for every plan
+	localChecksum, err := c.FileChecksum(filePath, c.ChecksumType)
+	if err != nil {
+		return false, err
+	}
+
+	// the checksum attributes are named after their checksum types
+	checksumKey := string(c.ChecksumType)
+	remoteChecksum := d.Get(checksumKey).(string)
+
+	return remoteChecksum != localChecksum, nil
+}
+
+// diffPackageFile returns true if the package file in fileKey can be read when planning, because it and the keys
+// in pathKeys that determine where it's uploaded are known. Packages that don't exist yet at plan time, such as those
+// built by other resources, are read on apply, so the attributes read from them in packageKeys and the checksums are
+// marked as "known after apply", unless none of those keys changed.
+func diffPackageFile(d resourceDiffer, fileKey string, pathKeys []string, packageKeys []string) (bool, error) {
+	known := d.NewValueKnown(fileKey)
+	for _, key := range pathKeys {
+		known = known && d.NewValueKnown(key)
+	}
+	if known {
+		if _, err := os.Stat(d.Get(fileKey).(string)); err == nil {
+			return true, nil
+		}
+	}
+
+	if d.Id() != "" && !d.HasChanges(append([]string{fileKey}, pathKeys...)...) {
+		return false, nil
+	}
+	for _, key := range packageKeys {
+		if err := d.SetNewComputed(key); err != nil {
+			return false, err
+		}
+	}
+
+	return false, setNewComputedChecksums(d)
+}
+
+// setNewComputedChecksums marks all of the checksum fields as "known after apply".
+func setNewComputedChecksums(d resourceDiffer) error {
+	for _, key := range []string{sha1Key, sha256Key, md5Key} {
+		if err := d.SetNewComputed(key); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
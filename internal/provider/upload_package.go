@@ -0,0 +1,171 @@
+// Copyright 2021 Splunk, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"terraform-provider-artifacts/internal/provider/internal/client"
+	"terraform-provider-artifacts/internal/provider/internal/layout"
+	"terraform-provider-artifacts/internal/provider/internal/pkginfo"
+)
+
+// packageTypeStrings returns the supported package types as strings.
+func packageTypeStrings() []string {
+	types := make([]string, 0, len(pkginfo.PackageTypes))
+	for _, packageType := range pkginfo.PackageTypes {
+		types = append(types, string(packageType))
+	}
+
+	return types
+}
+
+// detectUploadPackage reads the package in an upload's file, of its package_type. Uploads without a package_type have
+// no package, so that their state is unchanged from before package types existed.
+func detectUploadPackage(d resourceGetter) (pkginfo.Package, error) {
+	packageType := d.Get(packageTypeKey).(string)
+	if packageType == "" {
+		return pkginfo.Package{}, nil
+	}
+
+	return pkginfo.DetectPackage(d.Get(uploadFileKey).(string), pkginfo.PackageType(packageType))
+}
+
+// resolveUploadTarget returns the full path of an upload, along with the package in its file. upload_path takes
+// precedence over the package's canonical path in the repository.
+func resolveUploadTarget(d resourceGetter, c *client.Client) (string, pkginfo.Package, error) {
+	pkg, err := detectUploadPackage(d)
+	if err != nil {
+		return "", pkg, err
+	}
+
+	if d.Get(uploadPathKey).(string) != "" {
+		fullPath, err := resolveUploadPath(d, c)
+		if err != nil {
+			return "", pkg, fmt.Errorf("unable to resolve %s: %s", uploadPathKey, err)
+		}
+		return fullPath, pkg, nil
+	}
+
+	filename := d.Get(uploadFileKey).(string)
+	var packagePath string
+	switch pkg.Type {
+	case "":
+		return "", pkg, fmt.Errorf("%s must be set, unless %s is set to upload a package to its canonical path", uploadPathKey, packageTypeKey)
+	case pkginfo.MavenPackage:
+		coordinates := layout.MavenCoordinates{
+			GroupID:    pkg.GroupID,
+			ArtifactID: pkg.Name,
+			Version:    pkg.Version,
+			Extension:  mavenExtension(filename),
+		}
+		if err := coordinates.Validate(); err != nil {
+			return "", pkg, err
+		}
+		packagePath = coordinates.Path(pkg.Version)
+	case pkginfo.NPMPackage:
+		packagePath = layout.NPMPath(pkg.Name, pkg.Version)
+	case pkginfo.PyPIPackage:
+		packagePath = layout.PyPIPath(pkg.Name, pkg.Version, filepath.Base(filename))
+	default:
+		return "", pkg, fmt.Errorf("%s must be set for %s, which has no package metadata to determine its path from", uploadPathKey, filename)
+	}
+
+	repository := d.Get(repositoryKey).(string)
+	if repository == "" {
+		return "", pkg, fmt.Errorf("%s must be set to upload %s to its canonical path, unless %s is set", repositoryKey, filename, uploadPathKey)
+	}
+
+	fullPath, err := client.JoinPath(repository, packagePath)
+	if err != nil {
+		return "", pkg, err
+	}
+
+	return fullPath, pkg, nil
+}
+
+// uploadPackageAttributes returns the attributes of an upload's package.
+func uploadPackageAttributes(pkg pkginfo.Package) map[string]string {
+	return map[string]string{
+		detectedPackageTypeKey: string(pkg.Type),
+		groupIDKey:             pkg.GroupID,
+		packageNameKey:         pkg.Name,
+		versionKey:             pkg.Version,
+	}
+}
+
+// setUploadPackage sets the attributes of an upload's package.
+func setUploadPackage(d resourceData, pkg pkginfo.Package) error {
+	for key, value := range uploadPackageAttributes(pkg) {
+		if err := d.Set(key, value); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// diffUploadTarget sets the new full_path and package attributes of an upload, returning true if the full path
+// changed or isn't known yet. Files of packages that don't exist yet at plan time, such as those built by other
+// resources, are read on apply.
+func diffUploadTarget(d resourceDiffer, c *client.Client) (bool, error) {
+	if !d.NewValueKnown(packageTypeKey) || d.Get(packageTypeKey).(string) != "" {
+		targetKeys := []string{uploadFileKey, packageTypeKey, repositoryKey, uploadPathKey, pathVarsKey}
+
+		known := true
+		for _, key := range targetKeys {
+			known = known && d.NewValueKnown(key)
+		}
+		if known {
+			_, err := os.Stat(d.Get(uploadFileKey).(string))
+			known = err == nil
+		}
+
+		if !known {
+			if d.Id() != "" && !d.HasChanges(targetKeys...) {
+				return false, nil
+			}
+			for key := range uploadPackageAttributes(pkginfo.Package{}) {
+				if err := d.SetNewComputed(key); err != nil {
+					return false, err
+				}
+			}
+			return true, d.SetNewComputed(fullPathKey)
+		}
+	} else if !d.NewValueKnown(uploadPathKey) || !d.NewValueKnown(pathVarsKey) {
+		return true, d.SetNewComputed(fullPathKey)
+	}
+
+	fullPath, pkg, err := resolveUploadTarget(d, c)
+	if err != nil {
+		return false, err
+	}
+
+	for key, value := range uploadPackageAttributes(pkg) {
+		if value != d.Get(key).(string) {
+			if err := d.SetNew(key, value); err != nil {
+				return false, err
+			}
+		}
+	}
+
+	if fullPath != d.Get(fullPathKey).(string) {
+		return true, d.SetNew(fullPathKey, fullPath)
+	}
+
+	return false, nil
+}
@@ -15,20 +15,34 @@
 package provider
 
 import (
+	"context"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"regexp"
 	"testing"
 
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 )
 
 func TestAccResourceUpload(t *testing.T) {
 	resource.Test(t, resource.TestCase{
-		ProviderFactories: providerFactories,
+		ProtoV5ProviderFactories: protoV5ProviderFactories,
 		Steps: []resource.TestStep{
 			{
 				Config: testResourceUploadCreateConfig,
 				Check: resource.ComposeTestCheckFunc(
 					resource.TestCheckResourceAttr("artifacts_upload.test", "upload_path", "sas-binary/terraform-provider-artifacts-test/test_file_1.txt"),
 					resource.TestCheckResourceAttr("artifacts_upload.test", "sha1", "af3d968c42b3046f86296c7522b3b20dfdc58c59"),
+					resource.TestCheckResourceAttr("artifacts_upload.test", "sha256", "8376f260fca20effdf3c7b66f2f56d7f118b532e6712af09a4f8922ccab58c5c"),
+					resource.TestCheckResourceAttr("artifacts_upload.test", "md5", "95266c5332e914ce4c6c49eb6fecd36a"),
 				),
 			},
 			{
@@ -36,6 +50,8 @@ func TestAccResourceUpload(t *testing.T) {
 				Check: resource.ComposeTestCheckFunc(
 					resource.TestCheckResourceAttr("artifacts_upload.test", "upload_path", "sas-binary/terraform-provider-artifacts-test/test_file_2.txt"),
 					resource.TestCheckResourceAttr("artifacts_upload.test", "sha1", "af3d968c42b3046f86296c7522b3b20dfdc58c59"),
+					resource.TestCheckResourceAttr("artifacts_upload.test", "sha256", "8376f260fca20effdf3c7b66f2f56d7f118b532e6712af09a4f8922ccab58c5c"),
+					resource.TestCheckResourceAttr("artifacts_upload.test", "md5", "95266c5332e914ce4c6c49eb6fecd36a"),
 				),
 			},
 			{
@@ -43,12 +59,322 @@ func TestAccResourceUpload(t *testing.T) {
 				Check: resource.ComposeTestCheckFunc(
 					resource.TestCheckResourceAttr("artifacts_upload.test", "upload_path", "sas-binary/terraform-provider-artifacts-test/test_file_2.txt"),
 					resource.TestCheckResourceAttr("artifacts_upload.test", "sha1", "27f1703d965438b9f78d412d60d47816d878c9a5"),
+					resource.TestCheckResourceAttr("artifacts_upload.test", "sha256", "ab8fb1ed019a6ddc9c4b6588887212ebc44289fd946412fee14c4b2b99a13370"),
+					resource.TestCheckResourceAttr("artifacts_upload.test", "md5", "53bb72a023655a632693ff6b07c39b92"),
 				),
 			},
 		},
 	})
 }
 
+func TestAccResourceUploadBasePath(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV5ProviderFactories: protoV5ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testResourceUploadBasePathConfig,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("artifacts_upload.test", "full_path", "sas-binary/terraform-provider-artifacts-test/1.0.0/test_file_linux.txt"),
+					resource.TestCheckResourceAttr("artifacts_upload.test", "sha1", "af3d968c42b3046f86296c7522b3b20dfdc58c59"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccResourceUploadSidecars(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV5ProviderFactories: protoV5ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testResourceUploadSidecarsConfig,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("artifacts_upload.test", "sidecar_paths.#", "2"),
+					resource.TestCheckResourceAttr("artifacts_upload.test", "sidecar_paths.0", "sas-binary/terraform-provider-artifacts-test/sidecars.txt.sha256"),
+					resource.TestCheckResourceAttr("artifacts_upload.test", "sidecar_paths.1", "sas-binary/terraform-provider-artifacts-test/sidecars.txt.md5"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccResourceUploadPackageType(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV5ProviderFactories: protoV5ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testResourceUploadPackageTypeConfig,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("artifacts_upload.test", "full_path", "sas-npm/terraform-provider-artifacts-test/-/terraform-provider-artifacts-test-1.0.0.tgz"),
+					resource.TestCheckResourceAttr("artifacts_upload.test", "detected_package_type", "npm"),
+					resource.TestCheckResourceAttr("artifacts_upload.test", "package_name", "terraform-provider-artifacts-test"),
+					resource.TestCheckResourceAttr("artifacts_upload.test", "version", "1.0.0"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccResourceUploadInvalid(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV5ProviderFactories: protoV5ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testResourceUploadLeadingSlashConfig,
+				ExpectError: regexp.MustCompile("Invalid upload path"),
+			},
+			{
+				Config:      testResourceUploadInvalidRepositoryConfig,
+				ExpectError: regexp.MustCompile("must start with the key of a repository"),
+			},
+			{
+				Config:      testResourceUploadMissingFileConfig,
+				ExpectError: regexp.MustCompile("Invalid upload file"),
+			},
+		},
+	})
+}
+
+// TestAccResourceUploadUpgrade applies with the last release, which used SDKv2, and checks that the current
+// provider plans no changes to its state. Null and empty triggers, path_vars and sidecar_paths that weren't in the
+// release's state, and the delete_old_path default, must not appear as differences.
+func TestAccResourceUploadUpgrade(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		Steps: []resource.TestStep{
+			{
+				ExternalProviders: map[string]resource.ExternalProvider{
+					"artifacts": {
+						Source:            "splunk/artifacts",
+						VersionConstraint: "1.1.0",
+					},
+				},
+				Config: testResourceUploadUpgradeConfig,
+			},
+			{
+				ProtoV5ProviderFactories: protoV5ProviderFactories,
+				Config:                   testResourceUploadUpgradeConfig,
+				PlanOnly:                 true,
+			},
+		},
+	})
+}
+
+// TestUploadUpgradeFromSDKv2State refreshes and plans state written by the last release, which used SDKv2, as
+// TestAccResourceUploadUpgrade does, checking that no changes are planned. SDKv2 stored null or empty triggers as
+// they were configured.
+func TestUploadUpgradeFromSDKv2State(t *testing.T) {
+	content, err := os.ReadFile("test_files/source_file.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sha1Sum := fmt.Sprintf("%x", sha1.Sum(content))
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{"checksums": map[string]string{
+			"sha1":   sha1Sum,
+			"sha256": fmt.Sprintf("%x", sha256.Sum256(content)),
+			"md5":    fmt.Sprintf("%x", md5.Sum(content)),
+		}})
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	providerServer, schemas := configuredProviderServer(t, server.URL)
+	uploadSchema := schemas.ResourceSchemas[uploadResourceKey]
+	uploadType := uploadSchema.ValueType().(tftypes.Object)
+	triggersType := uploadType.AttributeTypes[triggersKey]
+
+	tests := []struct {
+		name          string
+		triggersJSON  string
+		triggers      tftypes.Value
+		deleteOldPath *bool
+	}{
+		{name: "defaults", triggersJSON: "null", triggers: tftypes.NewValue(triggersType, nil)},
+		{name: "empty triggers", triggersJSON: "{}", triggers: tftypes.NewValue(triggersType, map[string]tftypes.Value{})},
+		{
+			name:         "triggers",
+			triggersJSON: `{"version":"1.0.0"}`,
+			triggers:     tftypes.NewValue(triggersType, map[string]tftypes.Value{"version": tftypes.NewValue(tftypes.String, "1.0.0")}),
+		},
+		{name: "keep old path", triggersJSON: "null", triggers: tftypes.NewValue(triggersType, nil), deleteOldPath: new(bool)},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// artifacts_upload's state as the 1.1.0 release wrote it, with its placeholder id
+			upgradeResp, err := providerServer.UpgradeResourceState(ctx, &tfprotov5.UpgradeResourceStateRequest{
+				TypeName: uploadResourceKey,
+				Version:  0,
+				RawState: &tfprotov5.RawState{JSON: []byte(fmt.Sprintf(
+					`{"id":%q,"upload_path":"repo/file.txt","upload_file":"test_files/source_file.txt","delete_old_path":%t,"sha1":%q,"triggers":%s}`,
+					artifactIDValue, test.deleteOldPath == nil || *test.deleteOldPath, sha1Sum, test.triggersJSON,
+				))},
+			})
+			if err != nil {
+				t.Fatalf("err: %s", err)
+			}
+			checkDiagnostics(t, upgradeResp.Diagnostics)
+
+			readResp, err := providerServer.ReadResource(ctx, &tfprotov5.ReadResourceRequest{
+				TypeName:     uploadResourceKey,
+				CurrentState: upgradeResp.UpgradedState,
+			})
+			if err != nil {
+				t.Fatalf("err: %s", err)
+			}
+			checkDiagnostics(t, readResp.Diagnostics)
+			prior, err := readResp.NewState.Unmarshal(uploadType)
+			if err != nil {
+				t.Fatalf("err: %s", err)
+			}
+
+			configValues := map[string]tftypes.Value{
+				uploadPathKey: tftypes.NewValue(tftypes.String, "repo/file.txt"),
+				uploadFileKey: tftypes.NewValue(tftypes.String, "test_files/source_file.txt"),
+				triggersKey:   test.triggers,
+			}
+			if test.deleteOldPath != nil {
+				configValues[deleteOldPath] = tftypes.NewValue(tftypes.Bool, *test.deleteOldPath)
+			}
+			configValue := objectValue(uploadType, configValues)
+			config, err := tfprotov5.NewDynamicValue(uploadType, configValue)
+			if err != nil {
+				t.Fatalf("err: %s", err)
+			}
+			proposed, err := tfprotov5.NewDynamicValue(uploadType, proposedNewState(t, uploadSchema, prior, configValue))
+			if err != nil {
+				t.Fatalf("err: %s", err)
+			}
+
+			planResp, err := providerServer.PlanResourceChange(ctx, &tfprotov5.PlanResourceChangeRequest{
+				TypeName:         uploadResourceKey,
+				PriorState:       readResp.NewState,
+				ProposedNewState: &proposed,
+				Config:           &config,
+			})
+			if err != nil {
+				t.Fatalf("err: %s", err)
+			}
+			checkDiagnostics(t, planResp.Diagnostics)
+
+			planned, err := planResp.PlannedState.Unmarshal(uploadType)
+			if err != nil {
+				t.Fatalf("err: %s", err)
+			}
+			diffs, err := prior.Diff(planned)
+			if err != nil {
+				t.Fatalf("err: %s", err)
+			}
+			for _, diff := range diffs {
+				t.Errorf("planned change to %s from %s to %s", diff.Path, diff.Value1, diff.Value2)
+			}
+
+			attributes := map[string]tftypes.Value{}
+			if err := planned.As(&attributes); err != nil {
+				t.Fatalf("err: %s", err)
+			}
+			var id string
+			if err := attributes[idKey].As(&id); err != nil {
+				t.Fatalf("err: %s", err)
+			}
+			if id != artifactIDValue {
+				t.Errorf("got id %q, want %q", id, artifactIDValue)
+			}
+		})
+	}
+}
+
+// TestUploadPlanMissingFile checks that a missing upload_file fails the plan only when it's about to be uploaded,
+// and is otherwise a warning, as it may be produced during apply or be gone after it was uploaded.
+func TestUploadPlanMissingFile(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{"checksums": map[string]string{"sha1": "abc"}})
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	providerServer, schemas := configuredProviderServer(t, server.URL)
+	uploadSchema := schemas.ResourceSchemas[uploadResourceKey]
+	uploadType := uploadSchema.ValueType().(tftypes.Object)
+	triggersType := uploadType.AttributeTypes[triggersKey]
+
+	readResp, err := providerServer.ReadResource(ctx, &tfprotov5.ReadResourceRequest{
+		TypeName: uploadResourceKey,
+		CurrentState: dynamicValue(t, uploadType, objectValue(uploadType, map[string]tftypes.Value{
+			"id":          tftypes.NewValue(tftypes.String, "repo/file.txt"),
+			uploadPathKey: tftypes.NewValue(tftypes.String, "repo/file.txt"),
+			uploadFileKey: tftypes.NewValue(tftypes.String, "test_files/missing.txt"),
+			deleteOldPath: tftypes.NewValue(tftypes.Bool, true),
+			fullPathKey:   tftypes.NewValue(tftypes.String, "repo/file.txt"),
+		})),
+	})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	checkDiagnostics(t, readResp.Diagnostics)
+	existing, err := readResp.NewState.Unmarshal(uploadType)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	tests := []struct {
+		name     string
+		prior    tftypes.Value
+		triggers map[string]tftypes.Value
+		severity tfprotov5.DiagnosticSeverity
+	}{
+		{name: "create", prior: tftypes.NewValue(uploadType, nil), severity: tfprotov5.DiagnosticSeverityError},
+		{name: "unchanged", prior: existing, severity: tfprotov5.DiagnosticSeverityWarning},
+		{
+			name:     "triggers changed",
+			prior:    existing,
+			triggers: map[string]tftypes.Value{"version": tftypes.NewValue(tftypes.String, "1.0.1")},
+			severity: tfprotov5.DiagnosticSeverityError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			triggers := tftypes.NewValue(triggersType, nil)
+			if test.triggers != nil {
+				triggers = tftypes.NewValue(triggersType, test.triggers)
+			}
+			configValue := objectValue(uploadType, map[string]tftypes.Value{
+				uploadPathKey: tftypes.NewValue(tftypes.String, "repo/file.txt"),
+				uploadFileKey: tftypes.NewValue(tftypes.String, "test_files/missing.txt"),
+				triggersKey:   triggers,
+			})
+			proposedValue := configValue
+			if !test.prior.IsNull() {
+				proposedValue = proposedNewState(t, uploadSchema, test.prior, configValue)
+			}
+
+			config := dynamicValue(t, uploadType, configValue)
+			proposed := dynamicValue(t, uploadType, proposedValue)
+			planResp, err := providerServer.PlanResourceChange(ctx, &tfprotov5.PlanResourceChangeRequest{
+				TypeName:         uploadResourceKey,
+				PriorState:       dynamicValue(t, uploadType, test.prior),
+				ProposedNewState: proposed,
+				Config:           config,
+			})
+			if err != nil {
+				t.Fatalf("err: %s", err)
+			}
+
+			var severities []tfprotov5.DiagnosticSeverity
+			for _, diagnostic := range planResp.Diagnostics {
+				if diagnostic.Summary == "Invalid upload file" {
+					severities = append(severities, diagnostic.Severity)
+				}
+			}
+			if len(severities) != 1 || severities[0] != test.severity {
+				t.Errorf("got upload file diagnostics with severities %v, want %v", severities, test.severity)
+			}
+		})
+	}
+}
+
 const testResourceUploadCreateConfig = `
 provider "artifacts" {
   url = "https://repo.splunk.com/artifactory"
@@ -81,3 +407,108 @@ resource "artifacts_upload" "test" {
   upload_file = "test_files/source_file_update.txt"
 }
 `
+
+const testResourceUploadBasePathConfig = `
+provider "artifacts" {
+  url       = "https://repo.splunk.com/artifactory"
+  base_path = "sas-binary/terraform-provider-artifacts-test"
+  path_vars = {
+    os = "linux"
+  }
+}
+
+resource "artifacts_upload" "test" {
+  upload_path = "{version}/test_file_{os}.txt"
+  upload_file = "test_files/source_file.txt"
+  path_vars = {
+    version = "1.0.0"
+  }
+}
+`
+
+const testResourceUploadSidecarsConfig = `
+provider "artifacts" {
+  url = "https://repo.splunk.com/artifactory"
+}
+
+resource "artifacts_upload" "test" {
+  upload_path       = "sas-binary/terraform-provider-artifacts-test/sidecars.txt"
+  upload_file       = "test_files/source_file.txt"
+  checksum_sidecars = ["sha256", "md5"]
+}
+`
+
+const testResourceUploadPackageTypeConfig = `
+provider "artifacts" {
+  url = "https://repo.splunk.com/artifactory"
+}
+
+resource "artifacts_upload" "test" {
+  upload_file  = "test_files/terraform-provider-artifacts-test-1.0.0.tgz"
+  package_type = "auto"
+  repository   = "sas-npm"
+}
+`
+
+const testResourceUploadLeadingSlashConfig = `
+provider "artifacts" {
+  url = "https://repo.splunk.com/artifactory"
+}
+
+resource "artifacts_upload" "test" {
+  upload_path = "/sas-binary/terraform-provider-artifacts-test/test_file_1.txt"
+  upload_file = "test_files/source_file.txt"
+}
+`
+
+const testResourceUploadInvalidRepositoryConfig = `
+provider "artifacts" {
+  url = "https://repo.splunk.com/artifactory"
+}
+
+resource "artifacts_upload" "test" {
+  upload_path = "{repo}/test_file_1.txt"
+  upload_file = "test_files/source_file.txt"
+  path_vars = {
+    repo = "sas binary"
+  }
+}
+`
+
+const testResourceUploadMissingFileConfig = `
+provider "artifacts" {
+  url = "https://repo.splunk.com/artifactory"
+}
+
+resource "artifacts_upload" "test" {
+  upload_path = "sas-binary/terraform-provider-artifacts-test/test_file_1.txt"
+  upload_file = "test_files/missing_file.txt"
+}
+`
+
+const testResourceUploadUpgradeConfig = `
+provider "artifacts" {
+  url = "https://repo.splunk.com/artifactory"
+}
+
+resource "artifacts_upload" "defaults" {
+  upload_path = "sas-binary/terraform-provider-artifacts-test/upgrade/defaults.txt"
+  upload_file = "test_files/source_file.txt"
+}
+
+resource "artifacts_upload" "empty_triggers" {
+  upload_path     = "sas-binary/terraform-provider-artifacts-test/upgrade/empty_triggers.txt"
+  upload_file     = "test_files/source_file.txt"
+  triggers        = {}
+  delete_old_path = true
+}
+
+resource "artifacts_upload" "triggers" {
+  upload_path = "sas-binary/terraform-provider-artifacts-test/upgrade/triggers.txt"
+  upload_file = "test_files/source_file.txt"
+  triggers = {
+    version = "1.0.0"
+  }
+  delete_old_path = false
+}
+`
@@ -0,0 +1,202 @@
+// Copyright 2021 Splunk, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	"terraform-provider-artifacts/internal/provider/internal/client"
+)
+
+// stringValidator adapts a schema.SchemaValidateFunc, as used by SDKv2 resources, to a validator.String for
+// resources ported to the plugin framework.
+type stringValidator struct {
+	description string
+	validate    schema.SchemaValidateFunc
+}
+
+// Description implements validator.Describer.
+func (v stringValidator) Description(ctx context.Context) string {
+	return v.description
+}
+
+// MarkdownDescription implements validator.Describer.
+func (v stringValidator) MarkdownDescription(ctx context.Context) string {
+	return v.description
+}
+
+// ValidateString implements validator.String. Values that aren't known yet aren't validated.
+func (v stringValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	warnings, errs := v.validate(req.ConfigValue.ValueString(), req.Path.String())
+	for _, warning := range warnings {
+		resp.Diagnostics.AddAttributeWarning(req.Path, "Attribute value warning", warning)
+	}
+	for _, err := range errs {
+		resp.Diagnostics.AddAttributeError(req.Path, "Invalid attribute value", err.Error())
+	}
+}
+
+// multipleOfValidator checks that an Int64 attribute is a multiple of a unit, such as a part size accepted in whole
+// MiB.
+type multipleOfValidator struct {
+	unit     int64
+	unitName string
+}
+
+// Description implements validator.Describer.
+func (v multipleOfValidator) Description(ctx context.Context) string {
+	return fmt.Sprintf("value must be a multiple of %d (%s)", v.unit, v.unitName)
+}
+
+// MarkdownDescription implements validator.Describer.
+func (v multipleOfValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+// ValidateInt64 implements validator.Int64. Values that aren't known yet aren't validated.
+func (v multipleOfValidator) ValidateInt64(ctx context.Context, req validator.Int64Request, resp *validator.Int64Response) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	if req.ConfigValue.ValueInt64()%v.unit != 0 {
+		resp.Diagnostics.AddAttributeError(req.Path, "Invalid attribute value", fmt.Sprintf("Attribute %s %s, got: %d", req.Path, v.Description(ctx), req.ConfigValue.ValueInt64()))
+	}
+}
+
+// uploadPathValidator checks the syntax of an upload_path, before its {name} tokens are expanded, so that typos are
+// reported when the configuration is validated, rather than partway through an apply. The repository key the path
+// starts with is checked once the provider's base_path is known, by validateUploadPlan.
+type uploadPathValidator struct{}
+
+// Description implements validator.Describer.
+func (v uploadPathValidator) Description(ctx context.Context) string {
+	return "value must be the relative path of a file, without leading or trailing slashes, empty, `.`, or `..` segments, or whitespace around segments"
+}
+
+// MarkdownDescription implements validator.Describer.
+func (v uploadPathValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+// ValidateString implements validator.String. Values that aren't known yet aren't validated.
+func (v uploadPathValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	if err := validateUploadPath(req.ConfigValue.ValueString()); err != nil {
+		resp.Diagnostics.AddAttributeError(req.Path, "Invalid upload path", err.Error())
+	}
+}
+
+// validateUploadPathFunc is a schema.SchemaValidateFunc that checks the syntax of an upload_path of an SDKv2
+// resource, as uploadPathValidator does for artifacts_upload.
+func validateUploadPathFunc(value interface{}, key string) ([]string, []error) {
+	if err := validateUploadPath(value.(string)); err != nil {
+		return nil, []error{fmt.Errorf("invalid %s: %s", key, err)}
+	}
+
+	return nil, nil
+}
+
+// validateUploadPath returns an error if uploadPath isn't the relative path of a file. An empty path, which defers to
+// the canonical path of a package, is valid.
+func validateUploadPath(uploadPath string) error {
+	if uploadPath == "" {
+		return nil
+	}
+
+	if strings.HasPrefix(uploadPath, "/") {
+		return fmt.Errorf("path %q must be relative to the provider's URL, without a leading slash", uploadPath)
+	}
+	if strings.HasSuffix(uploadPath, "/") {
+		return fmt.Errorf("path %q must be the path of a file, without a trailing slash", uploadPath)
+	}
+
+	for _, segment := range strings.Split(uploadPath, "/") {
+		switch {
+		case segment == "":
+			return fmt.Errorf("path %q must not contain double slashes", uploadPath)
+		case segment == "." || segment == "..":
+			return fmt.Errorf("path %q must not contain %q segments", uploadPath, segment)
+		case strings.TrimSpace(segment) != segment:
+			return fmt.Errorf("path %q must not have whitespace around its segment %q", uploadPath, segment)
+		}
+	}
+
+	return nil
+}
+
+// validateUploadFullPath returns an error if fullPath, an upload_path after applying the provider's base_path and
+// expanding its tokens, isn't the path of a file in a repository.
+func validateUploadFullPath(fullPath string) error {
+	repositoryKey, repositoryPath, _ := strings.Cut(fullPath, "/")
+	if err := client.ValidateRepositoryKey(repositoryKey); err != nil {
+		return fmt.Errorf("path %q must start with the key of a repository: %s", fullPath, err)
+	}
+	if repositoryPath == "" {
+		return fmt.Errorf("path %q must be the path of a file in repository %s, not the repository itself", fullPath, repositoryKey)
+	}
+
+	return nil
+}
+
+// validateUploadFile returns an error if filename isn't a regular file that can be read.
+func validateUploadFile(filename string) error {
+	info, err := os.Stat(filename)
+	if os.IsNotExist(err) {
+		return fmt.Errorf("file %s doesn't exist", filename)
+	}
+	if err != nil {
+		return fmt.Errorf("unable to read file %s: %s", filename, err)
+	}
+	if !info.Mode().IsRegular() {
+		return fmt.Errorf("%s must be a regular file, not a %s", filename, fileKind(info.Mode()))
+	}
+
+	file, err := os.Open(filename)
+	if err != nil {
+		return fmt.Errorf("unable to read file %s: %s", filename, err)
+	}
+
+	return file.Close()
+}
+
+// fileKind describes the type of a file that isn't a regular file.
+func fileKind(mode os.FileMode) string {
+	switch {
+	case mode.IsDir():
+		return "directory"
+	case mode&os.ModeNamedPipe != 0:
+		return "named pipe"
+	case mode&os.ModeSocket != 0:
+		return "socket"
+	case mode&os.ModeDevice != 0:
+		return "device"
+	}
+
+	return "special file"
+}
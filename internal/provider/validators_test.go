@@ -0,0 +1,103 @@
+// Copyright 2021 Splunk, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestMultipleOfValidator(t *testing.T) {
+	v := multipleOfValidator{unit: 1 << 20, unitName: "1 MiB"}
+
+	for value, valid := range map[types.Int64]bool{
+		types.Int64Value(100 << 20): true,
+		types.Int64Value(5 << 20):   true,
+		types.Int64Value(5<<20 + 1): false,
+		types.Int64Value(5_000_000): false,
+		types.Int64Null():           true,
+		types.Int64Unknown():        true,
+	} {
+		resp := &validator.Int64Response{}
+		v.ValidateInt64(context.Background(), validator.Int64Request{Path: path.Root(multipartPartSizeKey), ConfigValue: value}, resp)
+
+		if resp.Diagnostics.HasError() == valid {
+			t.Errorf("%s: got diagnostics %v, want valid %t", value, resp.Diagnostics, valid)
+		}
+	}
+}
+
+func TestValidateUploadPath(t *testing.T) {
+	for uploadPath, valid := range map[string]bool{
+		"":                            true,
+		"sas-binary/tool/tool.tgz":    true,
+		"{version}/tool-{os}.tgz":     true,
+		"sas-binary/my tool/tool.tgz": true,
+		"/sas-binary/tool.tgz":        false,
+		"sas-binary/tool/":            false,
+		"sas-binary//tool.tgz":        false,
+		"sas-binary/../tool.tgz":      false,
+		"sas-binary/./tool.tgz":       false,
+		"sas-binary/tool.tgz ":        false,
+		" sas-binary/tool.tgz":        false,
+		"sas-binary/ tool/tool.tgz":   false,
+	} {
+		if err := validateUploadPath(uploadPath); (err == nil) != valid {
+			t.Errorf("validateUploadPath(%q): got error %v, want valid %t", uploadPath, err, valid)
+		}
+	}
+}
+
+func TestValidateUploadFullPath(t *testing.T) {
+	for fullPath, valid := range map[string]bool{
+		"sas-binary/tool/tool.tgz": true,
+		"sas-binary":               false,
+		"my repo/tool.tgz":         false,
+		"../tool.tgz":              false,
+	} {
+		if err := validateUploadFullPath(fullPath); (err == nil) != valid {
+			t.Errorf("validateUploadFullPath(%q): got error %v, want valid %t", fullPath, err, valid)
+		}
+	}
+}
+
+func TestValidateUploadFile(t *testing.T) {
+	unreadable := filepath.Join(t.TempDir(), "unreadable.txt")
+	if err := os.WriteFile(unreadable, []byte("unreadable"), 0o000); err != nil {
+		t.Fatalf("unable to write %s: %s", unreadable, err)
+	}
+
+	tests := map[string]bool{
+		"test_files/source_file.txt": true,
+		"test_files/missing.txt":     false,
+		"test_files":                 false,
+	}
+	// root can read files regardless of their permissions
+	if os.Geteuid() != 0 {
+		tests[unreadable] = false
+	}
+
+	for filename, valid := range tests {
+		if err := validateUploadFile(filename); (err == nil) != valid {
+			t.Errorf("validateUploadFile(%q): got error %v, want valid %t", filename, err, valid)
+		}
+	}
+}
@@ -15,7 +15,11 @@
 package main
 
 import (
-	"github.com/hashicorp/terraform-plugin-sdk/v2/plugin"
+	"flag"
+	"log"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5/tf5server"
 	"terraform-provider-artifacts/internal/provider"
 )
 
@@ -26,7 +30,22 @@ const (
 )
 
 func main() {
-	opts := &plugin.ServeOpts{ProviderFunc: provider.New(version)}
+	var debug bool
+	flag.BoolVar(&debug, "debug", false, "set to true to run the provider with support for debuggers like delve")
+	flag.Parse()
+
+	var opts []tf5server.ServeOpt
+	if debug {
+		opts = append(opts, tf5server.WithManagedDebug())
+	}
+
+	server, err := provider.ProtoV5ProviderServer(version)()
+	if err != nil {
+		log.Fatal(err)
+	}
 
-	plugin.Serve(opts)
+	err = tf5server.Serve("registry.terraform.io/splunk/artifacts", func() tfprotov5.ProviderServer { return server }, opts...)
+	if err != nil {
+		log.Fatal(err)
+	}
 }